@@ -21,10 +21,15 @@ func showUsage() {
 USAGE:
     subdomainx <domain> [OPTIONS]                    # Single domain scan
     subdomainx --wildcard <domains_file> [OPTIONS]   # Multiple domains scan
+    cat targets.txt | subdomainx [OPTIONS] -         # Targets from stdin
 
 REQUIRED (choose one):
     <domain>              Target domain for single domain scan
     --wildcard FILE       Path to file containing target domains (one per line)
+                          Targets may be domains, *.wildcards, URLs, IPs, CIDRs or
+                          ASNs (AS13335); HackerOne/Bugcrowd CSV or JSON scope
+                          exports and Burp target-scope JSON are detected too.
+                          Use - to read targets from stdin.
 
 OPTIONS:
     --version              Show version information
@@ -43,8 +48,30 @@ OPTIONS:
     --timeout N            Timeout in seconds (default: 30)
     --rate-limit N         Rate limit per second (default: 100)
     --wordlist FILE        Custom wordlist file for brute-forcing
+    --resolvers LIST       DNS resolvers (comma-separated or file); supports tcp://,
+                           tls:// (DNS-over-TLS) and https:// (DNS-over-HTTPS)
+                           (default: system resolver and /etc/hosts)
+    --dns-threads N        Number of concurrent DNS queries (default: 50)
+    --dns-rate-limit N     DNS queries per second (default: 500)
+    --dns-records          Store the full record set of every subdomain (A, AAAA, CNAME
+                           chain, MX, TXT, NS, SOA, CAA) in every output format
+    --permutation-depth N  Permutation rounds for --permute (default: 1)
+    --permutation-budget N Max permutation candidates per domain (default: 20000)
+    --recursion-depth N    Enumerate discovered sub-zones recursively up to N levels (default: 0, off)
+    --recursion-min-children N
+                           Children a sub-zone needs to be enumerated, unless it has
+                           its own NS records (default: 3)
+    --wildcard-filter MODE Wildcard DNS matches: drop, flag or off (default: drop)
+    --scope-include LIST   In-scope host patterns, comma-separated globs or re:regex
+    --scope-exclude LIST   Out-of-scope host patterns (e.g. '*.corp.example.com')
+    --scope-allow-cidr LIST
+                           In-scope address ranges (CIDRs or IPs)
+    --scope-deny-cidr LIST Out-of-scope address ranges (CIDRs or IPs)
     --max-http-targets N   Maximum subdomains to scan with httpx (default: 1000)
     --resume SCAN_ID       Resume scan from checkpoint (scan ID)
+    --import FILES         Scan names from earlier recon output instead of enumerating:
+                           subfinder -oJ, amass JSON, massdns -o S or plain text
+                           (comma-separated files or globs; no target needed)
     --list-checkpoints     List available checkpoints
 
     # Filter Options
@@ -70,6 +97,17 @@ OPTIONS:
     --hackertarget         Use HackerTarget API
     --waybackurls          Use waybackurls tool
     --linkheader           Use Link Header enumeration
+    --bruteforce           Use native DNS brute-forcing (shipped wordlist unless --wordlist)
+    --permute              Permute discovered subdomains and resolve the candidates
+    --axfr                 Attempt zone transfers (AXFR) against every nameserver
+    --zonewalk             Walk NSEC chains / crack NSEC3 hashes of signed zones
+    --tls-san              Harvest names from TLS certificates (ports 443, 8443, ...)
+    --sources LIST         Use declarative API sources by name (comma-separated)
+    --sources-dir DIR      Directory of YAML source definitions (default: ~/.config/subdomainx/sources)
+    --custom-tools LIST    Use declared CLI tool wrappers by name (comma-separated)
+    --tools-dir DIR        Directory of YAML tool definitions (default: ~/.config/subdomainx/tools)
+    --plugins LIST         Use enumerator and scanner plugins by name (comma-separated)
+    --plugins-dir DIR      Directory of plugin executables (default: ~/.config/subdomainx/plugins)
     --httpx                Use httpx for HTTP scanning
     --smap                 Use smap for port scanning
 
@@ -79,12 +117,41 @@ OPTIONS:
     --screenshot-timeout N     Timeout per page in seconds (default: 10)
     --screenshot-resolution WxH  Viewport resolution (default: 1280x720)
 
+    # Email Security Options
+    --email-security       Check SPF (with include chains and the 10-lookup limit), DMARC,
+                           DKIM, MTA-STS, TLS-RPT and BIMI of every target domain
+    --dkim-selectors LIST  Extra DKIM selectors to probe (comma-separated)
+
+    # IP Enrichment Options
+    --ip-enrich            Tag every IP with ASN, organisation, country and cloud/CDN
+                           provider from local data files (no network lookups)
+    --update-ip-data       Download the ip2asn table and AWS, GCP, Azure and Cloudflare
+                           IP ranges into the data directory and exit
+    --ip-data-dir DIR      IP data directory (default: ~/.cache/subdomainx/ipdata)
+    --ip-db FILES          Extra ip2asn TSV or MaxMind .mmdb files (comma-separated)
+    --client-asn LIST      The client's own ASNs, tagged as provider "client"
+    --provider-filter LIST Keep hosts on these providers; !name excludes (e.g. '!cloudflare')
+    --asn-filter LIST      Keep hosts in these ASNs; !ASN excludes
+    --country-filter LIST  Keep hosts in these countries (ISO codes); !CC excludes
+
+    # Origin Exposure Options
+    --origin-check         Report CDN/WAF-fronted sites that a sibling subdomain serves from a
+                           direct address, matched on certificate, body, favicon and title
+                           (implies --httpx; more accurate with --ip-enrich and --dns-records)
+
+    # Crawl Options
+    --crawl                Crawl live HTTP results for host names in links, scripts,
+                           CSP and other headers (implies --httpx)
+    --crawl-depth N        Link levels followed from each live URL (default: 2)
+    --crawl-pages N        Maximum pages fetched by the crawler (default: 500)
+
     # Diff/Monitoring Options
     --diff                 Compare results against the most recent previous scan
     --baseline FILE        Compare results against a specific baseline file
 
     # Notification Options
-    --notify CHANNELS      Send notifications (comma-separated: slack,discord,telegram,email)
+    --notify CHANNELS      Send notifications (comma-separated: slack,discord,telegram,email
+                           or the name of a notifier plugin)
                            Credentials via environment variables:
                              SUBDOMAINX_SLACK_WEBHOOK
                              SUBDOMAINX_DISCORD_WEBHOOK
@@ -93,6 +160,9 @@ OPTIONS:
 
     # Configuration
     --config FILE          Use custom configuration file (optional)
+    --credentials FILE     API keys file, several keys per provider, rotated on 429/quota
+                           (default: ~/.config/subdomainx/credentials.yaml)
+    --encrypt-credentials  Encrypt the credentials file with $SUBDOMAINX_CREDENTIALS_PASSPHRASE
     --verbose              Enable verbose output
 
 EXAMPLES:
@@ -105,6 +175,12 @@ EXAMPLES:
     # Multiple domains scan
     subdomainx --wildcard domains.txt
 
+    # Scan a HackerOne scope export, honouring its out-of-scope entries
+    subdomainx --wildcard scope.csv
+
+    # Pipe targets from another tool
+    cat urls.txt | subdomainx --httpx -
+
     # Multiple domains with specific tools
     subdomainx --wildcard domains.txt --amass --subfinder --httpx
 
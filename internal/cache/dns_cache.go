@@ -1,14 +1,18 @@
 package cache
 
 import (
-	"net"
+	"context"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
 )
 
 type DNSCache struct {
-	cache map[string]cacheEntry
-	mutex sync.RWMutex
+	cache    map[string]cacheEntry
+	mutex    sync.RWMutex
+	resolver resolver.Resolver // nil means the resolver in each lookup's context
 }
 
 type cacheEntry struct {
@@ -17,8 +21,15 @@ type cacheEntry struct {
 }
 
 func NewDNSCache() *DNSCache {
+	return NewDNSCacheWithResolver(nil)
+}
+
+// NewDNSCacheWithResolver creates a cache that resolves through r instead of
+// the resolver carried by each lookup's context.
+func NewDNSCacheWithResolver(r resolver.Resolver) *DNSCache {
 	return &DNSCache{
-		cache: make(map[string]cacheEntry),
+		cache:    make(map[string]cacheEntry),
+		resolver: r,
 	}
 }
 
@@ -45,24 +56,35 @@ func (d *DNSCache) Lookup(domain string) []string {
 
 // Resolve performs DNS resolution for a domain and caches the result
 func (d *DNSCache) Resolve(domain string) []string {
+	return d.ResolveContext(context.Background(), domain)
+}
+
+// ResolveContext resolves the A and AAAA records of a domain, honouring ctx,
+// and caches the sorted result. Failed lookups are cached as an empty slice.
+func (d *DNSCache) ResolveContext(ctx context.Context, domain string) []string {
 	// Check cache first
 	if ips := d.Lookup(domain); ips != nil {
 		return ips
 	}
 
 	// Perform DNS resolution
-	ips, err := net.LookupIP(domain)
+	r := d.resolver
+	if r == nil {
+		r = resolver.FromContext(ctx)
+	}
+	addrs, err := r.LookupHost(ctx, domain)
 	if err != nil {
-		// Store empty result to avoid repeated failed lookups
-		d.Store(domain, []string{})
+		// Store empty result to avoid repeated failed lookups, unless the
+		// lookup was cut short by cancellation
+		if ctx.Err() == nil {
+			d.Store(domain, []string{})
+		}
 		return []string{}
 	}
 
-	// Convert IP addresses to strings
-	var ipStrings []string
-	for _, ip := range ips {
-		ipStrings = append(ipStrings, ip.String())
-	}
+	// Copy and sort so equal answers compare equal across scans
+	ipStrings := append([]string{}, addrs...)
+	sort.Strings(ipStrings)
 
 	// Store in cache
 	d.Store(domain, ipStrings)
@@ -1,9 +1,12 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
 )
 
 func TestNewDNSCache(t *testing.T) {
@@ -256,3 +259,22 @@ func TestDNSCacheResolveLocalhost(t *testing.T) {
 		t.Error("Expected cached result for localhost, got nil")
 	}
 }
+
+func TestDNSCacheResolveContextCancelled(t *testing.T) {
+	cache := NewDNSCacheWithResolver(resolver.NewSystem())
+	domain := "cancelled.example.com"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A cancelled lookup returns no IPs
+	result := cache.ResolveContext(ctx, domain)
+	if len(result) != 0 {
+		t.Errorf("Expected no IPs for cancelled lookup, got %d", len(result))
+	}
+
+	// ...and must not be cached as a failed lookup
+	if cache.Lookup(domain) != nil {
+		t.Error("Expected cancelled lookup not to be cached")
+	}
+}
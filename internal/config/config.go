@@ -10,6 +10,7 @@ import (
 
 type Config struct {
 	WildcardFile   string            `yaml:"wildcard_file" json:"wildcard_file"`
+	TargetHosts    []string          `yaml:"target_hosts" json:"target_hosts"` // probed as given, not enumerated
 	UniqueName     string            `yaml:"unique_name" json:"unique_name"`
 	OutputDir      string            `yaml:"output_dir" json:"output_dir"`
 	OutputFormat   string            `yaml:"output_format" json:"output_format"`
@@ -32,6 +33,47 @@ type Config struct {
 	TechFilter     string            `yaml:"tech_filter" json:"tech_filter"`
 	Takeover       bool              `yaml:"takeover" json:"takeover"`
 	TakeoverOnly   bool              `yaml:"takeover_only" json:"takeover_only"`
+	Crawl          bool              `yaml:"crawl" json:"crawl"`
+	CrawlDepth     int               `yaml:"crawl_depth" json:"crawl_depth"` // link levels followed from each live URL
+	CrawlPages     int               `yaml:"crawl_pages" json:"crawl_pages"` // pages fetched per scan
+	Resolvers      []string          `yaml:"resolvers" json:"resolvers"`
+	DNSThreads     int               `yaml:"dns_threads" json:"dns_threads"`
+	DNSRateLimit   int               `yaml:"dns_rate_limit" json:"dns_rate_limit"`
+	DNSRecords     bool              `yaml:"dns_records" json:"dns_records"` // store every record type per subdomain
+	EmailSecurity  bool              `yaml:"email_security" json:"email_security"` // SPF, DMARC, DKIM, MTA-STS and BIMI checks per apex
+	DKIMSelectors  []string          `yaml:"dkim_selectors" json:"dkim_selectors"` // probed in addition to the common ones
+	IPEnrich       bool              `yaml:"ip_enrich" json:"ip_enrich"`           // tag IPs with ASN, organisation, country and provider
+	IPDataDir      string            `yaml:"ip_data_dir" json:"ip_data_dir"`       // defaults to the user cache directory
+	IPDatabases    []string          `yaml:"ip_databases" json:"ip_databases"`     // extra ip2asn TSV or .mmdb files
+	ClientASNs     []string          `yaml:"client_asns" json:"client_asns"`       // tagged with the provider "client"
+	ProviderFilter string            `yaml:"provider_filter" json:"provider_filter"` // e.g. "aws,!cloudflare"
+	ASNFilter      string            `yaml:"asn_filter" json:"asn_filter"`
+	CountryFilter  string            `yaml:"country_filter" json:"country_filter"`
+	OriginCheck    bool              `yaml:"origin_check" json:"origin_check"` // find sites reachable around their CDN or WAF
+	WildcardFilter string            `yaml:"wildcard_filter" json:"wildcard_filter"` // drop, flag or off
+	PermutationDepth int             `yaml:"permutation_depth" json:"permutation_depth"`
+	PermutationBudget int            `yaml:"permutation_budget" json:"permutation_budget"`
+	RecursionDepth int               `yaml:"recursion_depth" json:"recursion_depth"` // 0 disables recursion
+	RecursionMinChildren int         `yaml:"recursion_min_children" json:"recursion_min_children"`
+	ScopeApexes     []string         `yaml:"scope_apexes" json:"scope_apexes"` // defaults to the wildcard file domains
+	ScopeInclude    []string         `yaml:"scope_include" json:"scope_include"` // host globs or re: patterns
+	ScopeExclude    []string         `yaml:"scope_exclude" json:"scope_exclude"` // host globs or re: patterns
+	ScopeAllowCIDRs []string         `yaml:"scope_allow_cidrs" json:"scope_allow_cidrs"`
+	ScopeDenyCIDRs  []string         `yaml:"scope_deny_cidrs" json:"scope_deny_cidrs"`
+	SourcePolicies  map[string]SourcePolicy `yaml:"source_policies" json:"source_policies"` // per tool; "default" applies to the rest
+	ToolTimeouts    map[string]int   `yaml:"tool_timeouts" json:"tool_timeouts"`   // seconds per tool and domain; "default" applies to the rest
+	DomainTimeout   int              `yaml:"domain_timeout" json:"domain_timeout"` // seconds for all tools on one domain, 0 for no limit
+	ImportFiles     []string         `yaml:"import_files" json:"import_files"`     // recon output scanned instead of enumerating
+}
+
+// SourcePolicy limits how a run calls one enumeration source. Zero values
+// mean no limit, except that FailureThreshold and Cooldown fall back to
+// the "default" policy and then to 3 failures and 300 seconds.
+type SourcePolicy struct {
+	RequestsPerMinute int `yaml:"requests_per_minute" json:"requests_per_minute"`
+	DailyBudget       int `yaml:"daily_budget" json:"daily_budget"`           // requests per UTC day
+	FailureThreshold  int `yaml:"failure_threshold" json:"failure_threshold"` // consecutive failures that open the circuit
+	Cooldown          int `yaml:"cooldown" json:"cooldown"`                   // seconds the circuit stays open
 }
 
 func LoadConfig() (*Config, error) {
@@ -41,15 +83,22 @@ func LoadConfig() (*Config, error) {
 func LoadConfigFromFile(configPath string) (*Config, error) {
 	// Default configuration
 	cfg := &Config{
-		OutputDir:    "output",
-		OutputFormat: "json",
-		Threads:      10,
-		Retries:      3,
-		Timeout:      30,
-		RateLimit:    100,
-		Wordlist:     "", // No default wordlist required
-		Tools:        make(map[string]bool),
-		Filters:      make(map[string]string),
+		OutputDir:            "output",
+		OutputFormat:         "json",
+		Threads:              10,
+		Retries:              3,
+		Timeout:              30,
+		RateLimit:            100,
+		Wordlist:             "", // No default wordlist required
+		DNSThreads:           50,
+		DNSRateLimit:         500,
+		PermutationDepth:     1,
+		PermutationBudget:    20000,
+		RecursionMinChildren: 3,
+		CrawlDepth:           2,
+		CrawlPages:           500,
+		Tools:                make(map[string]bool),
+		Filters:              make(map[string]string),
 	}
 
 	// Load from config file if exists
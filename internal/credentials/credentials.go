@@ -0,0 +1,371 @@
+// Package credentials holds the API keys of passive sources. Keys come from
+// a credentials file that may list several keys per provider, optionally
+// encrypted with a passphrase, or else from the provider's environment
+// variables. Requests rotate through a provider's keys round-robin, keys
+// that hit a rate limit or quota are set aside, and usage is tracked
+// against each key's quota.
+package credentials
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// defaultCooldown is how long a rate-limited key is set aside when the
+// provider did not say.
+const defaultCooldown = time.Minute
+
+// Key is one credential of a provider. Quota is the number of requests the
+// key may make per Period (day or month); zero means unknown.
+type Key struct {
+	Key    string `yaml:"key"`
+	Secret string `yaml:"secret,omitempty"` // second half of ID/secret pairs (Censys)
+	Quota  int    `yaml:"quota,omitempty"`
+	Period string `yaml:"period,omitempty"`
+
+	id string // fingerprint naming the key in usage state and output
+}
+
+// ID returns a short fingerprint of the key that is safe to show.
+func (k *Key) ID() string {
+	return k.id
+}
+
+// usage is what is known about one key's consumption.
+type usage struct {
+	PeriodStart    time.Time `json:"period_start"`
+	Used           int       `json:"used"`
+	Remaining      int       `json:"remaining"` // as reported by the provider, -1 if unknown
+	ExhaustedUntil time.Time `json:"exhausted_until,omitzero"`
+
+	coolUntil time.Time // rate-limited until
+}
+
+// store is the process-wide credential state.
+var store = struct {
+	sync.Mutex
+	fileKeys  map[string][]*Key
+	env       map[string][2]string // provider -> key and secret variables
+	next      map[string]int       // round-robin position per provider
+	usage     map[string]*usage    // provider + ":" + key ID
+	statePath string
+	dirty     bool // usage changed since the last save
+}{
+	fileKeys: map[string][]*Key{},
+	env: map[string][2]string{
+		"securitytrails": {"SECURITYTRAILS_API_KEY", ""},
+		"virustotal":     {"VIRUSTOTAL_API_KEY", ""},
+		"censys":         {"CENSYS_API_ID", "CENSYS_SECRET"},
+		"urlscan":        {"URLSCAN_API_KEY", ""},
+		"hackertarget":   {"HACKERTARGET_API_KEY", ""},
+	},
+	next:  map[string]int{},
+	usage: map[string]*usage{},
+}
+
+// DefaultPath returns the credentials file used when none is given.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "subdomainx", "credentials.yaml")
+}
+
+// Load reads the credentials file at path, decrypting it with passphrase
+// if it is encrypted, and the usage recorded next to it. A missing file is
+// not an error. The file maps provider names to lists of keys:
+//
+//	securitytrails:
+//	  - key: KEY1
+//	    quota: 50
+//	  - key: KEY2
+//	censys:
+//	  - key: API_ID
+//	    secret: SECRET
+func Load(path, passphrase string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read credentials: %v", err)
+	}
+	if IsEncrypted(data) {
+		if passphrase == "" {
+			return fmt.Errorf("credentials file %s is encrypted; set %s", path, PassphraseEnv)
+		}
+		if data, err = Decrypt(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	var file map[string][]*Key
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return fmt.Errorf("failed to parse credentials %s: %v", path, err)
+	}
+	for provider, keys := range file {
+		var valid []*Key
+		for _, k := range keys {
+			if k == nil || strings.TrimSpace(k.Key) == "" {
+				continue
+			}
+			if k.Period != "" && k.Period != "day" && k.Period != "month" {
+				return fmt.Errorf("credentials %s: %s key has unknown period %q", path, provider, k.Period)
+			}
+			k.Key, k.Secret = strings.TrimSpace(k.Key), strings.TrimSpace(k.Secret)
+			k.id = fingerprint(k.Key)
+			valid = append(valid, k)
+		}
+		file[provider] = valid
+	}
+
+	store.Lock()
+	defer store.Unlock()
+	store.fileKeys = file
+	store.next = map[string]int{}
+	store.usage = map[string]*usage{}
+	store.dirty = false
+	store.statePath = strings.TrimSuffix(path, filepath.Ext(path)) + ".usage.json"
+	if data, err := os.ReadFile(store.statePath); err == nil {
+		_ = json.Unmarshal(data, &store.usage)
+	}
+	return nil
+}
+
+// RegisterProvider sets the environment variables holding the key (and
+// secret) of a provider that has none in the credentials file.
+func RegisterProvider(provider, keyEnv, secretEnv string) {
+	store.Lock()
+	defer store.Unlock()
+	store.env[provider] = [2]string{keyEnv, secretEnv}
+}
+
+// keys returns the keys of provider: those in the credentials file, or
+// else the one in its environment variables. Callers hold the lock.
+func keys(provider string) []*Key {
+	if ks := store.fileKeys[provider]; len(ks) > 0 {
+		return ks
+	}
+	env, ok := store.env[provider]
+	if !ok {
+		return nil
+	}
+	key := strings.TrimSpace(os.Getenv(env[0]))
+	secret := ""
+	if env[1] != "" {
+		if secret = strings.TrimSpace(os.Getenv(env[1])); secret == "" {
+			return nil
+		}
+	}
+	if key == "" {
+		return nil
+	}
+	return []*Key{{Key: key, Secret: secret, id: fingerprint(key)}}
+}
+
+// Has reports whether provider has at least one key.
+func Has(provider string) bool {
+	store.Lock()
+	defer store.Unlock()
+	return len(keys(provider)) > 0
+}
+
+// Count returns how many keys provider has.
+func Count(provider string) int {
+	store.Lock()
+	defer store.Unlock()
+	return len(keys(provider))
+}
+
+// Next returns the provider's next usable key in round-robin order,
+// skipping keys that are rate limited or out of quota. It returns nil if
+// there is none.
+func Next(provider string) *Key {
+	store.Lock()
+	defer store.Unlock()
+	ks := keys(provider)
+	now := time.Now()
+	for range ks {
+		i := store.next[provider] % len(ks)
+		store.next[provider] = i + 1
+		if k := ks[i]; usable(k, usageOf(provider, k, now), now) {
+			return k
+		}
+	}
+	return nil
+}
+
+// RateLimited sets k aside for retryAfter, or a minute if that is zero.
+func RateLimited(provider string, k *Key, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultCooldown
+	}
+	store.Lock()
+	defer store.Unlock()
+	usageOf(provider, k, time.Now()).coolUntil = time.Now().Add(retryAfter)
+}
+
+// Exhausted sets k aside until its quota period ends, or a day if it has
+// no quota.
+func Exhausted(provider string, k *Key) {
+	store.Lock()
+	defer store.Unlock()
+	now := time.Now()
+	u := usageOf(provider, k, now)
+	if k.Quota > 0 {
+		u.ExhaustedUntil = periodEnd(k, u.PeriodStart)
+	} else {
+		u.ExhaustedUntil = now.Add(24 * time.Hour)
+	}
+	save()
+}
+
+// Record counts one successful request with k and takes the remaining
+// quota from the response's rate-limit headers when the provider sends
+// them. Usage is saved right away only when the key runs out of quota;
+// otherwise it waits for Save.
+func Record(provider string, k *Key, header http.Header) {
+	store.Lock()
+	defer store.Unlock()
+	u := usageOf(provider, k, time.Now())
+	u.Used++
+	for _, name := range []string{"X-RateLimit-Remaining", "RateLimit-Remaining", "X-Quota-Remaining"} {
+		if n, err := strconv.Atoi(strings.TrimSpace(header.Get(name))); err == nil {
+			u.Remaining = n
+			break
+		}
+	}
+	if (k.Quota > 0 && u.Used >= k.Quota) || u.Remaining == 0 {
+		save()
+	} else {
+		store.dirty = true
+	}
+}
+
+// Save writes the usage recorded since the last save next to the
+// credentials file. Call it before the process exits.
+func Save() {
+	store.Lock()
+	defer store.Unlock()
+	if store.dirty {
+		save()
+	}
+}
+
+// Status describes the keys of provider and their remaining quota for
+// --check-tools, or returns "" if it has no keys.
+func Status(provider string) string {
+	store.Lock()
+	defer store.Unlock()
+	ks := keys(provider)
+	if len(ks) == 0 {
+		return ""
+	}
+	now := time.Now()
+	var parts []string
+	for _, k := range ks {
+		u := usageOf(provider, k, now)
+		state := "ok"
+		switch {
+		case now.Before(u.ExhaustedUntil):
+			state = "exhausted until " + u.ExhaustedUntil.Format("2006-01-02 15:04")
+		case now.Before(u.coolUntil):
+			state = "rate limited"
+		case u.Remaining >= 0:
+			state = fmt.Sprintf("%d remaining", u.Remaining)
+		case k.Quota > 0:
+			state = fmt.Sprintf("%d/%d remaining this %s", max(k.Quota-u.Used, 0), k.Quota, period(k))
+		}
+		parts = append(parts, k.id+": "+state)
+	}
+	sort.Strings(parts)
+	noun := "keys"
+	if len(ks) == 1 {
+		noun = "key"
+	}
+	return fmt.Sprintf("%d %s; %s", len(ks), noun, strings.Join(parts, ", "))
+}
+
+// usable reports whether k may be used now.
+func usable(k *Key, u *usage, now time.Time) bool {
+	// A reported remaining count of zero may be a short window, so only
+	// the 429 that follows sets the key aside
+	if now.Before(u.coolUntil) || now.Before(u.ExhaustedUntil) {
+		return false
+	}
+	return k.Quota == 0 || u.Used < k.Quota
+}
+
+// usageOf returns the usage of k, starting (and saving) a new period when
+// the last one ended. Callers hold the lock.
+func usageOf(provider string, k *Key, now time.Time) *usage {
+	id := provider + ":" + k.id
+	u, ok := store.usage[id]
+	if !ok {
+		u = &usage{PeriodStart: periodStart(k, now), Remaining: -1}
+		store.usage[id] = u
+	}
+	if !now.Before(periodEnd(k, u.PeriodStart)) {
+		u.PeriodStart, u.Used, u.Remaining, u.ExhaustedUntil = periodStart(k, now), 0, -1, time.Time{}
+		save()
+	}
+	return u
+}
+
+func period(k *Key) string {
+	if k.Period == "day" {
+		return "day"
+	}
+	return "month"
+}
+
+// periodStart returns the start of the quota period holding t, in UTC.
+func periodStart(k *Key, t time.Time) time.Time {
+	t = t.UTC()
+	if period(k) == "day" {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// periodEnd returns the end of the quota period starting at start.
+func periodEnd(k *Key, start time.Time) time.Time {
+	if period(k) == "day" {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// save writes the usage of every key next to the credentials file, so
+// quotas are tracked across runs. Callers hold the lock.
+func save() {
+	if store.statePath == "" {
+		return
+	}
+	data, err := json.MarshalIndent(store.usage, "", "  ")
+	if err != nil {
+		return
+	}
+	if os.WriteFile(store.statePath, data, 0600) == nil {
+		store.dirty = false
+	}
+}
+
+// fingerprint returns the first 8 hex digits of the SHA-256 of key.
+func fingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,159 @@
+package credentials
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testFile = `rotate:
+  - key: key-one
+  - key: key-two
+quota:
+  - key: key-quota
+    quota: 2
+    period: day
+`
+
+func loadTest(t *testing.T, data []byte, passphrase string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "credentials.yaml")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(path, passphrase); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	return path
+}
+
+func TestNextRotates(t *testing.T) {
+	loadTest(t, []byte(testFile), "")
+
+	first, second, third := Next("rotate"), Next("rotate"), Next("rotate")
+	if first == nil || second == nil || first.Key == second.Key || third.Key != first.Key {
+		t.Fatalf("Expected round-robin over both keys, got %v, %v, %v", first, second, third)
+	}
+
+	RateLimited("rotate", first, time.Hour)
+	for range 3 {
+		if k := Next("rotate"); k == nil || k.Key != second.Key {
+			t.Fatalf("Expected the rate-limited key to be skipped, got %v", k)
+		}
+	}
+	Exhausted("rotate", second)
+	if k := Next("rotate"); k != nil {
+		t.Errorf("Expected no usable key, got %v", k)
+	}
+	if Count("rotate") != 2 || !Has("rotate") {
+		t.Errorf("Expected set-aside keys to still be counted")
+	}
+}
+
+func TestQuota(t *testing.T) {
+	path := loadTest(t, []byte(testFile), "")
+
+	statePath := strings.TrimSuffix(path, ".yaml") + ".usage.json"
+	k := Next("quota")
+	Record("quota", k, http.Header{})
+	if status := Status("quota"); !strings.Contains(status, "1/2 remaining this day") {
+		t.Errorf("Unexpected status %q", status)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("Expected usage to wait for Save, got %v", err)
+	}
+	Record("quota", k, http.Header{})
+	if k := Next("quota"); k != nil {
+		t.Errorf("Expected the used-up key to be skipped, got %v", k)
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Errorf("Expected usage to be saved when the quota ran out: %v", err)
+	}
+
+	Record("rotate", Next("rotate"), http.Header{"X-Ratelimit-Remaining": {"41"}})
+	if status := Status("rotate"); !strings.HasPrefix(status, "2 keys; ") || !strings.Contains(status, "41 remaining") {
+		t.Errorf("Expected the reported remaining quota, got %q", status)
+	}
+}
+
+func TestSave(t *testing.T) {
+	path := loadTest(t, []byte(testFile), "")
+	statePath := strings.TrimSuffix(path, ".yaml") + ".usage.json"
+
+	Record("rotate", Next("rotate"), http.Header{})
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Fatalf("Expected usage to wait for Save, got %v", err)
+	}
+	Save()
+	if err := Load(path, ""); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if status := Status("quota"); !strings.Contains(status, "2/2 remaining this day") {
+		t.Errorf("Unexpected status %q", status)
+	}
+	data, err := os.ReadFile(statePath)
+	if err != nil || !strings.Contains(string(data), `"used": 1`) {
+		t.Errorf("Expected the recorded request to be saved, got %q (%v)", data, err)
+	}
+}
+
+func TestSavePeriodRollover(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "credentials.yaml")
+	statePath := filepath.Join(dir, "credentials.usage.json")
+	k := &Key{Key: "key-quota"}
+	usage := `{"quota:` + fingerprint(k.Key) + `": {"period_start": "2000-01-01T00:00:00Z", "used": 2, "remaining": -1}}`
+	if err := os.WriteFile(statePath, []byte(usage), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(testFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(path, ""); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if Next("quota") == nil {
+		t.Fatal("Expected the key to be usable in a new period")
+	}
+	data, err := os.ReadFile(statePath)
+	if err != nil || strings.Contains(string(data), "2000-01-01") {
+		t.Errorf("Expected the new period to be saved, got %q (%v)", data, err)
+	}
+}
+
+func TestEnvFallback(t *testing.T) {
+	RegisterProvider("envonly", "TEST_CREDENTIALS_KEY", "TEST_CREDENTIALS_SECRET")
+	t.Setenv("TEST_CREDENTIALS_KEY", "from-env")
+	if Has("envonly") {
+		t.Error("Expected a missing secret to leave the provider without keys")
+	}
+	t.Setenv("TEST_CREDENTIALS_SECRET", "s")
+	if k := Next("envonly"); k == nil || k.Key != "from-env" || k.Secret != "s" {
+		t.Errorf("Expected the key from the environment, got %v", k)
+	}
+}
+
+func TestEncryptedFile(t *testing.T) {
+	encrypted, err := Encrypt([]byte(testFile), "hunter2")
+	if err != nil {
+		t.Fatalf("Encrypt returned error: %v", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(string(encrypted), "key-one") {
+		t.Fatal("Expected the file to be encrypted")
+	}
+	if _, err := Decrypt(encrypted, "wrong"); err == nil {
+		t.Error("Expected a wrong passphrase to fail")
+	}
+
+	path := loadTest(t, encrypted, "hunter2")
+	if Count("rotate") != 2 {
+		t.Errorf("Expected the decrypted keys to load, got %d", Count("rotate"))
+	}
+	if err := Load(path, ""); err == nil || !strings.Contains(err.Error(), PassphraseEnv) {
+		t.Errorf("Expected a missing passphrase to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package credentials
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of an
+// encrypted credentials file.
+const PassphraseEnv = "SUBDOMAINX_CREDENTIALS_PASSPHRASE"
+
+// encryptedHeader starts every encrypted credentials file. The rest is the
+// base64 of salt, nonce and AES-256-GCM ciphertext.
+const encryptedHeader = "SUBDOMAINX-ENCRYPTED-CREDENTIALS v1\n"
+
+const (
+	saltSize         = 16
+	pbkdf2Iterations = 600000
+)
+
+// IsEncrypted reports whether data is an encrypted credentials file.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedHeader))
+}
+
+// Encrypt seals a plaintext credentials file with passphrase.
+func Encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append(append(salt, nonce...), gcm.Seal(nil, nonce, plaintext, nil)...)
+	return []byte(encryptedHeader + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Decrypt opens an encrypted credentials file with passphrase.
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(bytes.TrimPrefix(data, []byte(encryptedHeader)))))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt encrypted credentials: %v", err)
+	}
+	if len(sealed) < saltSize {
+		return nil, fmt.Errorf("corrupt encrypted credentials")
+	}
+	gcm, err := newGCM(passphrase, sealed[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	sealed = sealed[saltSize:]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("corrupt encrypted credentials")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials: wrong passphrase or corrupt file")
+	}
+	return plaintext, nil
+}
+
+// newGCM derives the AES-256-GCM cipher for passphrase and salt.
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"time"
 
@@ -34,13 +35,28 @@ func Compare(cfg *config.Config, scanID string, results []types.SubdomainResult)
 		// Find domain from results or config.
 		domain := extractDomain(cfg, results)
 		baseline = FindBaseline(history, domain, scanID)
-		if baseline == nil {
-			// First scan — everything is new.
-			return firstScanDiff(scanID, current), nil
-		}
 	}
 
-	return computeDiff(baseline, scanID, current), nil
+	var dr *DiffResult
+	if baseline == nil {
+		// First scan — everything is new.
+		dr = firstScanDiff(scanID, current)
+	} else {
+		dr = computeDiff(baseline, scanID, current)
+		dr.DNSChanges = compareDNS(baseline.DNS, buildDNSMap(results))
+	}
+
+	// Record where each new subdomain came from.
+	sources := buildSourceMap(results)
+	for _, sub := range dr.Added {
+		if records, ok := sources[sub]; ok {
+			if dr.AddedSources == nil {
+				dr.AddedSources = make(map[string][]types.SourceRecord)
+			}
+			dr.AddedSources[sub] = records
+		}
+	}
+	return dr, nil
 }
 
 // WriteDiffReport writes the diff result as JSON to the output directory.
@@ -83,7 +99,14 @@ func PrintSummary(dr *DiffResult) {
 		}
 	}
 
-	if len(dr.Added) == 0 && len(dr.Removed) == 0 && len(dr.IPChanges) == 0 {
+	if len(dr.DNSChanges) > 0 {
+		fmt.Printf("~ %d DNS record changes:\n", len(dr.DNSChanges))
+		for _, c := range dr.DNSChanges {
+			fmt.Printf("  ~ %s %s (%v -> %v)\n", c.Subdomain, c.Type, c.Old, c.New)
+		}
+	}
+
+	if len(dr.Added) == 0 && len(dr.Removed) == 0 && len(dr.IPChanges) == 0 && len(dr.DNSChanges) == 0 {
 		fmt.Println("  No changes detected.")
 	}
 
@@ -133,6 +156,28 @@ func computeDiff(baseline *HistoryEntry, currentScanID string, current map[strin
 	return dr
 }
 
+// compareDNS returns the record types that changed for every subdomain with
+// a record set in both scans, ordered by subdomain and then type.
+func compareDNS(baseline, current map[string]*types.DNSRecords) []DNSChange {
+	var changes []DNSChange
+	for sub, records := range current {
+		old, ok := baseline[sub]
+		if !ok {
+			continue
+		}
+		for _, rtype := range types.DNSRecordTypes {
+			oldValues, newValues := old.Values(rtype), records.Values(rtype)
+			if !slices.Equal(oldValues, newValues) {
+				changes = append(changes, DNSChange{Subdomain: sub, Type: rtype, Old: oldValues, New: newValues})
+			}
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Subdomain < changes[j].Subdomain
+	})
+	return changes
+}
+
 func firstScanDiff(scanID string, current map[string][]string) *DiffResult {
 	dr := &DiffResult{
 		BaselineScanID: "(none)",
@@ -0,0 +1,31 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+func TestCompareDNS(t *testing.T) {
+	baseline := map[string]*types.DNSRecords{
+		"example.com":     {A: []string{"192.0.2.1"}, MX: []string{"10 mail.example.com"}},
+		"www.example.com": {CNAME: []string{"edge.example.net"}},
+		"old.example.com": {A: []string{"192.0.2.9"}},
+	}
+	current := map[string]*types.DNSRecords{
+		"example.com":     {A: []string{"192.0.2.1"}, MX: []string{"10 mx.provider.net"}, TXT: []string{"v=spf1 -all"}},
+		"www.example.com": {CNAME: []string{"edge.example.net"}},
+		"new.example.com": {A: []string{"192.0.2.5"}}, // no baseline records, not compared
+	}
+
+	changes := compareDNS(baseline, current)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", changes)
+	}
+	if c := changes[0]; c.Subdomain != "example.com" || c.Type != "MX" || c.Old[0] != "10 mail.example.com" || c.New[0] != "10 mx.provider.net" {
+		t.Errorf("Unexpected MX change %+v", c)
+	}
+	if c := changes[1]; c.Type != "TXT" || len(c.Old) != 0 || c.New[0] != "v=spf1 -all" {
+		t.Errorf("Unexpected TXT change %+v", c)
+	}
+}
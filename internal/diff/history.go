@@ -26,6 +26,8 @@ func RecordScan(outputDir, scanID, domain string, results []types.SubdomainResul
 		Domain:    domain,
 		Timestamp: time.Now(),
 		Subdomains: buildSubdomainMap(results),
+		Sources:    buildSourceMap(results),
+		DNS:        buildDNSMap(results),
 	}
 	history = append(history, entry)
 	history = pruneHistory(history, domain)
@@ -79,6 +81,8 @@ func LoadBaselineFromFile(path string) (*HistoryEntry, error) {
 			ScanID:     "baseline",
 			Timestamp:  time.Now(),
 			Subdomains: buildSubdomainMap(scanResults.Subdomains),
+			Sources:    buildSourceMap(scanResults.Subdomains),
+			DNS:        buildDNSMap(scanResults.Subdomains),
 		}
 		return entry, nil
 	}
@@ -90,6 +94,8 @@ func LoadBaselineFromFile(path string) (*HistoryEntry, error) {
 			ScanID:     "baseline",
 			Timestamp:  time.Now(),
 			Subdomains: buildSubdomainMap(subResults),
+			Sources:    buildSourceMap(subResults),
+			DNS:        buildDNSMap(subResults),
 		}
 		return entry, nil
 	}
@@ -111,6 +117,32 @@ func buildSubdomainMap(results []types.SubdomainResult) map[string][]string {
 	return m
 }
 
+// buildSourceMap maps each subdomain to its source records, deriving them
+// from the legacy source string when needed.
+func buildSourceMap(results []types.SubdomainResult) map[string][]types.SourceRecord {
+	m := make(map[string][]types.SourceRecord, len(results))
+	for _, r := range results {
+		if records := r.SourceRecords(); len(records) > 0 {
+			m[r.Subdomain] = records
+		}
+	}
+	return m
+}
+
+// buildDNSMap maps each subdomain that has a record set to it.
+func buildDNSMap(results []types.SubdomainResult) map[string]*types.DNSRecords {
+	var m map[string]*types.DNSRecords
+	for _, r := range results {
+		if r.DNS != nil {
+			if m == nil {
+				m = make(map[string]*types.DNSRecords)
+			}
+			m[r.Subdomain] = r.DNS
+		}
+	}
+	return m
+}
+
 func pruneHistory(history []HistoryEntry, domain string) []HistoryEntry {
 	// Separate entries for this domain from others.
 	var domainEntries []HistoryEntry
@@ -1,27 +1,35 @@
 package diff
 
-import "time"
+import (
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
 
 // HistoryEntry is the on-disk record for one completed scan.
 type HistoryEntry struct {
-	ScanID      string              `json:"scan_id"`
-	Domain      string              `json:"domain"`
-	Timestamp   time.Time           `json:"timestamp"`
-	ResultsFile string              `json:"results_file"`
-	Subdomains  map[string][]string `json:"subdomains"` // subdomain -> IPs
+	ScanID      string                          `json:"scan_id"`
+	Domain      string                          `json:"domain"`
+	Timestamp   time.Time                       `json:"timestamp"`
+	ResultsFile string                          `json:"results_file"`
+	Subdomains  map[string][]string             `json:"subdomains"` // subdomain -> IPs
+	Sources     map[string][]types.SourceRecord `json:"sources,omitempty"`
+	DNS         map[string]*types.DNSRecords    `json:"dns,omitempty"` // subdomain -> record set, with --dns-records
 }
 
 // DiffResult holds the computed differences between two scans.
 type DiffResult struct {
-	BaselineScanID string     `json:"baseline_scan_id"`
-	BaselineTime   time.Time  `json:"baseline_time"`
-	CurrentScanID  string     `json:"current_scan_id"`
-	CurrentTime    time.Time  `json:"current_time"`
-	Added          []string   `json:"added"`
-	Removed        []string   `json:"removed"`
-	IPChanges      []IPChange `json:"ip_changes,omitempty"`
-	TotalCurrent   int        `json:"total_current"`
-	TotalBaseline  int        `json:"total_baseline"`
+	BaselineScanID string                          `json:"baseline_scan_id"`
+	BaselineTime   time.Time                       `json:"baseline_time"`
+	CurrentScanID  string                          `json:"current_scan_id"`
+	CurrentTime    time.Time                       `json:"current_time"`
+	Added          []string                        `json:"added"`
+	Removed        []string                        `json:"removed"`
+	IPChanges      []IPChange                      `json:"ip_changes,omitempty"`
+	DNSChanges     []DNSChange                     `json:"dns_changes,omitempty"`
+	AddedSources   map[string][]types.SourceRecord `json:"added_sources,omitempty"` // provenance of each added subdomain
+	TotalCurrent   int                             `json:"total_current"`
+	TotalBaseline  int                             `json:"total_baseline"`
 }
 
 // IPChange records a subdomain whose resolved IPs changed between scans.
@@ -30,3 +38,12 @@ type IPChange struct {
 	OldIPs    []string `json:"old_ips"`
 	NewIPs    []string `json:"new_ips"`
 }
+
+// DNSChange records a subdomain whose records of one type changed between
+// scans. Only subdomains with record sets in both scans are compared.
+type DNSChange struct {
+	Subdomain string   `json:"subdomain"`
+	Type      string   `json:"type"` // e.g. "MX"
+	Old       []string `json:"old"`
+	New       []string `json:"new"`
+}
@@ -17,13 +17,19 @@ func (a *AltDNSEnumerator) Name() string {
 }
 
 func (a *AltDNSEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return a.EnumerateSeeded(ctx, domain, nil, cfg)
+}
+
+// EnumerateSeeded feeds altdns the subdomains discovered by the first pass so
+// it permutes real names rather than just the apex.
+func (a *AltDNSEnumerator) EnumerateSeeded(ctx context.Context, domain string, known []string, cfg *config.Config) ([]string, error) {
 	// Create temp input file with base subdomains
 	inputFile, err := os.CreateTemp("", "altdns-input-*.txt")
 	if err != nil {
 		return nil, fmt.Errorf("altdns: failed to create input file: %v", err)
 	}
 	inputPath := inputFile.Name()
-	_, _ = inputFile.WriteString(domain + "\n")
+	_, _ = inputFile.WriteString(strings.Join(append([]string{domain}, known...), "\n") + "\n")
 	_ = inputFile.Close()
 	defer func() { _ = os.Remove(inputPath) }()
 
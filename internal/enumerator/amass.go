@@ -17,6 +17,11 @@ func (a *AmassEnumerator) Name() string {
 }
 
 func (a *AmassEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
+	return collectStream(ctx, a, domain, cfg)
+}
+
+// EnumerateStream emits subdomains as amass prints its JSON lines.
+func (a *AmassEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
 	// Build amass command with more robust options
 	args := []string{
 		"enum",
@@ -35,43 +40,30 @@ func (a *AmassEnumerator) Enumerate(ctx context.Context, domain string, cfg *con
 	// Create command with context timeout
 	cmd := exec.CommandContext(ctx, "amass", args...)
 
-	// Capture both stdout and stderr
-	output, err := cmd.Output()
-	if err != nil {
-		// Try to get stderr for better error reporting
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			stderr := string(exitErr.Stderr)
-			return nil, fmt.Errorf("amass execution failed (exit %d): %s", exitErr.ExitCode(), stderr)
-		}
-		return nil, fmt.Errorf("amass execution failed: %v", err)
-	}
-
-	// Parse JSON output
-	lines := strings.Split(string(output), "\n")
-	var subdomains []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
+	// Parse JSON output line by line
+	err := streamCommand(cmd, func(line string) {
 		var result struct {
 			Name string `json:"name"`
 		}
 
 		if err := json.Unmarshal([]byte(line), &result); err != nil {
 			// Skip malformed JSON lines
-			continue
+			return
 		}
 
 		if result.Name != "" && strings.HasSuffix(result.Name, domain) {
-			subdomains = append(subdomains, result.Name)
-			// DNS resolution will be done later in the main enumerator
+			emit(result.Name)
+		}
+	})
+	if err != nil {
+		// Include stderr for better error reporting
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr := string(exitErr.Stderr)
+			return fmt.Errorf("amass execution failed (exit %d): %s", exitErr.ExitCode(), stderr)
 		}
+		return fmt.Errorf("amass execution failed: %v", err)
 	}
-
-	return subdomains, nil
+	return nil
 }
 
 func init() {
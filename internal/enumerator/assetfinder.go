@@ -16,29 +16,26 @@ func (a *AssetfinderEnumerator) Name() string {
 }
 
 func (a *AssetfinderEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
+	return collectStream(ctx, a, domain, cfg)
+}
+
+// EnumerateStream emits subdomains as assetfinder prints them.
+func (a *AssetfinderEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
 	// Build assetfinder command
 	args := []string{domain}
 
 	cmd := exec.CommandContext(ctx, "assetfinder", args...)
 
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("assetfinder execution failed: %v", err)
-	}
-
 	// Parse output (one subdomain per line)
-	lines := strings.Split(string(output), "\n")
-	var subdomains []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			subdomains = append(subdomains, line)
-			// Cache the DNS result
+	err := streamCommand(cmd, func(line string) {
+		if !strings.HasPrefix(line, "#") {
+			emit(line)
 		}
+	})
+	if err != nil {
+		return fmt.Errorf("assetfinder execution failed: %v", err)
 	}
-
-	return subdomains, nil
+	return nil
 }
 
 func init() {
@@ -0,0 +1,190 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+// nameserverPort is the port a zone's own nameservers are queried on for
+// transfers and zone walks; tests point it at a local server.
+var nameserverPort = "53"
+
+// AXFREnumerator asks every nameserver of a zone for a full zone transfer.
+// A nameserver that allows one hands over every name in the zone, which is
+// also reported as a finding.
+type AXFREnumerator struct{}
+
+func (a *AXFREnumerator) Name() string {
+	return "axfr"
+}
+
+func (a *AXFREnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	subdomains, _, err := a.EnumerateFindings(ctx, domain, cfg)
+	return subdomains, err
+}
+
+// EnumerateFindings tries a transfer against each address of each NS of
+// domain and returns the names from every successful transfer, with one
+// finding per nameserver that allowed it.
+func (a *AXFREnumerator) EnumerateFindings(ctx context.Context, domain string, cfg *config.Config) ([]string, []types.Finding, error) {
+	dnsResolver := resolver.FromContext(ctx)
+	nameservers, err := lookupNS(ctx, dnsResolver, domain)
+	if err != nil {
+		return nil, nil, fmt.Errorf("axfr: %v", err)
+	}
+	if len(nameservers) == 0 {
+		return nil, nil, fmt.Errorf("axfr: no nameservers found for %s", domain)
+	}
+
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		records  = make(map[string]map[string]bool) // name → record types
+		findings []types.Finding
+	)
+	for _, ns := range nameservers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+			addrs, err := dnsResolver.LookupHost(lookupCtx, ns+".")
+			cancel()
+			if err != nil {
+				return
+			}
+
+			// One successful transfer per nameserver is enough
+			for _, addr := range addrs {
+				rrs, err := transferZone(ctx, domain, net.JoinHostPort(addr, nameserverPort), timeout)
+				if err != nil {
+					continue
+				}
+
+				mu.Lock()
+				for _, rr := range rrs {
+					name := strings.ToLower(strings.TrimSuffix(rr.Header().Name, "."))
+					if records[name] == nil {
+						records[name] = make(map[string]bool)
+					}
+					records[name][dns.TypeToString[rr.Header().Rrtype]] = true
+				}
+				findings = append(findings, axfrFinding(domain, ns, addr, rrs))
+				mu.Unlock()
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	var subdomains []string
+	for name := range records {
+		if strings.HasSuffix(name, "."+domain) && !strings.HasPrefix(name, "*.") {
+			subdomains = append(subdomains, name)
+		}
+	}
+	sort.Strings(subdomains)
+	for i := range findings {
+		findings[i].Details = recordSummary(records)
+	}
+	return subdomains, findings, nil
+}
+
+// lookupNS returns the nameserver hosts of domain, without trailing dots.
+func lookupNS(ctx context.Context, r resolver.Resolver, domain string) ([]string, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+	defer cancel()
+
+	resp, err := r.Query(lookupCtx, domain, dns.TypeNS)
+	if err != nil {
+		return nil, err
+	}
+	var nameservers []string
+	for _, rr := range resp.Answer {
+		if ns, ok := rr.(*dns.NS); ok {
+			nameservers = append(nameservers, strings.ToLower(strings.TrimSuffix(ns.Ns, ".")))
+		}
+	}
+	return nameservers, nil
+}
+
+// transferZone requests an AXFR of zone from server (host:port) over TCP and
+// returns every record received. Cancelling ctx aborts the transfer.
+func transferZone(ctx context.Context, zone, server string, timeout time.Duration) ([]dns.RR, error) {
+	dialer := &net.Dialer{Timeout: utils.DNSQueryTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	msg := new(dns.Msg)
+	msg.SetAxfr(dns.Fqdn(zone))
+
+	t := &dns.Transfer{Conn: &dns.Conn{Conn: conn}, ReadTimeout: timeout}
+	envelopes, err := t.In(msg, server)
+	if err != nil {
+		return nil, err
+	}
+
+	var rrs []dns.RR
+	for env := range envelopes {
+		if env.Error != nil {
+			return nil, env.Error
+		}
+		rrs = append(rrs, env.RR...)
+	}
+	if len(rrs) == 0 {
+		return nil, fmt.Errorf("empty transfer from %s", server)
+	}
+	return rrs, nil
+}
+
+// axfrFinding reports that ns (at addr) transferred zone.
+func axfrFinding(zone, ns, addr string, rrs []dns.RR) types.Finding {
+	return types.Finding{
+		Type:     "axfr-open",
+		Severity: types.SeverityMedium,
+		Target:   zone,
+		Title:    fmt.Sprintf("Zone transfer allowed by %s", ns),
+		Evidence: fmt.Sprintf("%s (%s) returned %d records for %s to an unauthenticated AXFR request", ns, addr, len(rrs), zone),
+	}
+}
+
+// recordSummary lists every transferred name with its record types, e.g.
+// "www.example.com A,AAAA".
+func recordSummary(records map[string]map[string]bool) []string {
+	lines := make([]string, 0, len(records))
+	for name, rtypes := range records {
+		var list []string
+		for t := range rtypes {
+			list = append(list, t)
+		}
+		sort.Strings(list)
+		lines = append(lines, name+" "+strings.Join(list, ","))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func init() {
+	RegisterEnumerator(&AXFREnumerator{})
+}
@@ -0,0 +1,131 @@
+package enumerator
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/miekg/dns"
+)
+
+var axfrZone = testZone{
+	"example.com.":          {"example.com. 60 IN NS ns1.example.com.", "example.com. 60 IN MX 10 mail.example.com."},
+	"ns1.example.com.":      {"ns1.example.com. 60 IN A 127.0.0.1"},
+	"www.example.com.":      {"www.example.com. 60 IN A 192.0.2.1", "www.example.com. 60 IN AAAA 2001:db8::1"},
+	"mail.example.com.":     {"mail.example.com. 60 IN A 192.0.2.2"},
+	"intranet.example.com.": {"intranet.example.com. 60 IN CNAME www.example.com."},
+	"*.dev.example.com.":    {"*.dev.example.com. 60 IN CNAME www.example.com."},
+}
+
+// axfrHandler answers regular queries from zone and, when open, transfers
+// every record in it; otherwise AXFR requests are refused.
+type axfrHandler struct {
+	zone testZone
+	open bool
+}
+
+func (h axfrHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	if r.Question[0].Qtype != dns.TypeAXFR {
+		h.zone.ServeDNS(w, r)
+		return
+	}
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	if !h.open {
+		m.Rcode = dns.RcodeRefused
+		_ = w.WriteMsg(m)
+		return
+	}
+
+	soa, _ := dns.NewRR("example.com. 60 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 60")
+	m.Answer = append(m.Answer, soa)
+	for _, texts := range h.zone {
+		for _, text := range texts {
+			rr, err := dns.NewRR(text)
+			if err != nil {
+				panic(err)
+			}
+			m.Answer = append(m.Answer, rr)
+		}
+	}
+	m.Answer = append(m.Answer, soa)
+	_ = w.WriteMsg(m)
+}
+
+// nameserverContext serves handler locally, resolves through it and points
+// queries to the zone's own nameservers at it.
+func nameserverContext(t *testing.T, handler dns.Handler) context.Context {
+	t.Helper()
+	addr := startDNSServer(t, handler)
+	_, port, _ := net.SplitHostPort(addr)
+	old := nameserverPort
+	nameserverPort = port
+	t.Cleanup(func() { nameserverPort = old })
+
+	pool, err := resolver.NewPool([]string{addr})
+	if err != nil {
+		t.Fatalf("NewPool returned error: %v", err)
+	}
+	return resolver.NewContext(context.Background(), pool)
+}
+
+func TestAXFREnumeratorOpen(t *testing.T) {
+	ctx := nameserverContext(t, axfrHandler{zone: axfrZone, open: true})
+
+	subdomains, findings, err := (&AXFREnumerator{}).EnumerateFindings(ctx, "example.com", &config.Config{Timeout: 5})
+	if err != nil {
+		t.Fatalf("EnumerateFindings returned error: %v", err)
+	}
+
+	want := []string{"intranet.example.com", "mail.example.com", "ns1.example.com", "www.example.com"}
+	if len(subdomains) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, subdomains)
+	}
+	for i := range want {
+		if subdomains[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, subdomains)
+			break
+		}
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected one finding, got %+v", findings)
+	}
+	f := findings[0]
+	if f.Type != "axfr-open" || f.Severity != types.SeverityMedium || f.Target != "example.com" || f.Title != "Zone transfer allowed by ns1.example.com" {
+		t.Errorf("Unexpected finding: %+v", f)
+	}
+	found := false
+	for _, d := range f.Details {
+		if d == "www.example.com A,AAAA" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected record types for www.example.com in details, got %v", f.Details)
+	}
+}
+
+func TestAXFREnumeratorRefused(t *testing.T) {
+	ctx := nameserverContext(t, axfrHandler{zone: axfrZone})
+
+	subdomains, findings, err := (&AXFREnumerator{}).EnumerateFindings(ctx, "example.com", &config.Config{Timeout: 5})
+	if err != nil {
+		t.Fatalf("EnumerateFindings returned error: %v", err)
+	}
+	if len(subdomains) != 0 || len(findings) != 0 {
+		t.Errorf("Expected nothing from a refused transfer, got %v and %+v", subdomains, findings)
+	}
+}
+
+func TestAXFREnumeratorNoNameservers(t *testing.T) {
+	ctx := nameserverContext(t, axfrHandler{zone: testZone{"example.org.": {"example.org. 60 IN A 192.0.2.9"}}})
+
+	if _, _, err := (&AXFREnumerator{}).EnumerateFindings(ctx, "example.org", &config.Config{}); err == nil {
+		t.Error("Expected an error for a zone without NS records")
+	}
+}
@@ -0,0 +1,103 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/pkg/wordlists"
+)
+
+// BruteForceEnumerator resolves wordlist candidates (word.domain) natively,
+// without depending on massdns or any other external binary.
+type BruteForceEnumerator struct{}
+
+func (b *BruteForceEnumerator) Name() string {
+	return "bruteforce"
+}
+
+func (b *BruteForceEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, b, domain, cfg)
+}
+
+// EnumerateStream emits each candidate as soon as it resolves.
+func (b *BruteForceEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
+	words, err := loadBruteForceWords(cfg)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("bruteforce: wordlist is empty")
+	}
+
+	dnsResolver := resolver.FromContext(ctx)
+
+	threads := cfg.DNSThreads
+	if threads <= 0 {
+		threads = 50
+	}
+	pool := utils.NewWorkerPool(threads, cfg.DNSRateLimit)
+	defer pool.Stop()
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found int
+	)
+
+	for _, word := range words {
+		if ctx.Err() != nil {
+			break
+		}
+		candidate := word + "." + domain
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+			if ctx.Err() != nil {
+				return
+			}
+
+			lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+			defer cancel()
+
+			// Query the rooted name so the local search list is never appended.
+			addrs, err := dnsResolver.LookupHost(lookupCtx, candidate+".")
+			if err != nil || len(addrs) == 0 {
+				return
+			}
+
+			mu.Lock()
+			found++
+			emit(candidate)
+			mu.Unlock()
+		})
+	}
+
+	wg.Wait()
+
+	if found == 0 && ctx.Err() != nil {
+		return fmt.Errorf("bruteforce: %v", ctx.Err())
+	}
+	return nil
+}
+
+// loadBruteForceWords returns the configured wordlist, falling back to the
+// wordlist shipped in pkg/wordlists.
+func loadBruteForceWords(cfg *config.Config) ([]string, error) {
+	if cfg.Wordlist == "" {
+		return wordlists.Default(), nil
+	}
+	lines, err := utils.ReadLines(cfg.Wordlist)
+	if err != nil {
+		return nil, fmt.Errorf("bruteforce: failed to read wordlist: %v", err)
+	}
+	return wordlists.Parse(strings.Join(lines, "\n")), nil
+}
+
+func init() {
+	RegisterEnumerator(&BruteForceEnumerator{})
+}
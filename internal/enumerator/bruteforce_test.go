@@ -0,0 +1,103 @@
+package enumerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+var bruteForceZone = testZone{
+	"example.com.":      {"example.com. 60 IN A 192.0.2.1"},
+	"www.example.com.":  {"www.example.com. 60 IN A 192.0.2.10"},
+	"mail.example.com.": {"mail.example.com. 60 IN A 192.0.2.11"},
+	"dev.example.com.":  {"dev.example.com. 60 IN CNAME www.example.com."},
+}
+
+// bruteForce runs the enumerator for domain and returns the sorted names.
+func bruteForce(t *testing.T, ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	t.Helper()
+	names, err := (&BruteForceEnumerator{}).Enumerate(ctx, domain, cfg)
+	slices.Sort(names)
+	return names, err
+}
+
+func TestBruteForceEnumerator(t *testing.T) {
+	ctx := testResolverContext(t, bruteForceZone)
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte("# hosts\nwww\nDEV\nmissing\n\nwww\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := bruteForce(t, ctx, "example.com", &config.Config{Wordlist: wordlist})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	if want := []string{"dev.example.com", "www.example.com"}; !slices.Equal(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
+
+func TestBruteForceEnumeratorDefaultWordlist(t *testing.T) {
+	ctx := testResolverContext(t, bruteForceZone)
+
+	names, err := bruteForce(t, ctx, "example.com", &config.Config{})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	if want := []string{"dev.example.com", "mail.example.com", "www.example.com"}; !slices.Equal(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
+
+func TestBruteForceEnumeratorMissingWordlist(t *testing.T) {
+	ctx := testResolverContext(t, bruteForceZone)
+	if _, err := bruteForce(t, ctx, "example.com", &config.Config{Wordlist: filepath.Join(t.TempDir(), "none.txt")}); err == nil {
+		t.Error("Expected an error for a missing wordlist")
+	}
+}
+
+func TestBruteForceEnumeratorWildcard(t *testing.T) {
+	ctx := testResolverContext(t, wildcardZone)
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte("www\nlb\nno-such-host\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every candidate resolves through the wildcard...
+	names, err := bruteForce(t, ctx, "example.com", &config.Config{Wordlist: wordlist})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	if want := []string{"lb.example.com", "no-such-host.example.com", "www.example.com"}; !slices.Equal(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+
+	// ...and the wildcard filter keeps only the real host
+	var results []types.SubdomainResult
+	for _, name := range names {
+		results = append(results, types.SubdomainResult{Subdomain: name})
+	}
+	kept := filterWildcards(ctx, &config.Config{WildcardFilter: "drop"}, []string{"example.com"}, results, tui.NewCLIEventSink())
+	if got := subdomainNames(kept); !slices.Equal(got, []string{"www.example.com"}) {
+		t.Errorf("filterWildcards kept %v, want [www.example.com]", got)
+	}
+}
+
+func TestBruteForceEnumeratorCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(testResolverContext(t, bruteForceZone))
+	cancel()
+
+	names, err := bruteForce(t, ctx, "example.com", &config.Config{})
+	if err == nil {
+		t.Error("Expected an error from a cancelled run")
+	}
+	if len(names) != 0 {
+		t.Errorf("Expected no names from a cancelled run, got %v", names)
+	}
+}
@@ -6,17 +6,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
-	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
 )
 
 type CensysEnumerator struct {
-	apiID  string
-	secret string
 	client *http.Client
 }
 
@@ -34,11 +31,6 @@ func (c *CensysEnumerator) Name() string {
 }
 
 func (c *CensysEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
-	// Check if API credentials are configured
-	if c.apiID == "" || c.secret == "" {
-		return nil, fmt.Errorf("censys API credentials not configured")
-	}
-
 	// Build API URL
 	url := "https://search.censys.io/api/v2/hosts/search"
 
@@ -46,19 +38,17 @@ func (c *CensysEnumerator) Enumerate(ctx context.Context, domain string, cfg *co
 	query := fmt.Sprintf("names:*.%s", domain)
 	requestBody := fmt.Sprintf(`{"q":"%s","per_page":100}`, query)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	// Add headers
-	req.SetBasicAuth(c.apiID, c.secret)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	// Make request with 429 retry handling
-	resp, err := utils.DoWithRetry(c.client, req, cfg.Retries, cfg.Timeout)
+	// Make request, rotating API ID/secret pairs on 429 and quota errors
+	resp, err := doWithKeys(c.client, cfg, "censys", true, func(key *credentials.Key) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(requestBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.SetBasicAuth(key.Key, key.Secret)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("censys API request failed: %v", err)
 	}
@@ -96,23 +86,12 @@ func (c *CensysEnumerator) Enumerate(ctx context.Context, domain string, cfg *co
 }
 
 func init() {
-	// Create Censys enumerator with default settings
+	// Create Censys enumerator with default settings; API credentials come
+	// from the credentials file or CENSYS_API_ID and CENSYS_SECRET
 	enumerator := &CensysEnumerator{
-		apiID:  getCensysAPIID(),
-		secret: getCensysSecret(),
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 	RegisterEnumerator(enumerator)
 }
-
-// getCensysAPIID retrieves the API ID from environment variable
-func getCensysAPIID() string {
-	return strings.TrimSpace(os.Getenv("CENSYS_API_ID"))
-}
-
-// getCensysSecret retrieves the API secret from environment variable
-func getCensysSecret() string {
-	return strings.TrimSpace(os.Getenv("CENSYS_SECRET"))
-}
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
-	"github.com/itszeeshan/subdomainx/v2/internal/utils"
 )
 
 type CrtShEnumerator struct {
@@ -39,7 +38,7 @@ func (c *CrtShEnumerator) Enumerate(ctx context.Context, domain string, cfg *con
 	req.Header.Set("Accept", "application/json")
 
 	// Make request with 429 retry handling
-	resp, err := utils.DoWithRetry(c.client, req, cfg.Retries, cfg.Timeout)
+	resp, err := doRequest(c.client, req, cfg.Retries, cfg.Timeout)
 	if err != nil {
 		return nil, fmt.Errorf("crtsh API request failed (check network/proxy): %v", err)
 	}
@@ -0,0 +1,125 @@
+package enumerator
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/miekg/dns"
+)
+
+// testZone is an in-memory zone for tests: rooted, lower-case owner names
+// mapped to records in presentation format. Owners starting with "*." are
+// wildcards and answer for names that do not exist below their parent.
+type testZone map[string][]string
+
+func (z testZone) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	q := r.Question[0]
+	m.Answer, m.Rcode = z.answer(strings.ToLower(q.Name), q.Qtype, 0)
+	_ = w.WriteMsg(m)
+}
+
+// answer returns the records of qtype for name, following CNAMEs inside the
+// zone and synthesizing wildcard answers like an authoritative server.
+func (z testZone) answer(name string, qtype uint16, depth int) ([]dns.RR, int) {
+	records, ok := z[name]
+	if !ok {
+		if z.exists(name) {
+			return nil, dns.RcodeSuccess // empty non-terminal
+		}
+		wildcard := z.wildcardFor(name)
+		if wildcard == "" {
+			return nil, dns.RcodeNameError
+		}
+		records = z[wildcard]
+	}
+
+	var out []dns.RR
+	for _, text := range records {
+		rr, err := dns.NewRR(text)
+		if err != nil {
+			panic(err)
+		}
+		rr.Header().Name = name
+		switch rtype := rr.Header().Rrtype; {
+		case rtype == qtype:
+			out = append(out, rr)
+		case rtype == dns.TypeCNAME && depth < 8:
+			out = append(out, rr)
+			chained, _ := z.answer(strings.ToLower(rr.(*dns.CNAME).Target), qtype, depth+1)
+			out = append(out, chained...)
+		}
+	}
+	return out, dns.RcodeSuccess
+}
+
+// exists reports whether name owns records or has descendants.
+func (z testZone) exists(name string) bool {
+	for owner := range z {
+		if owner == name || strings.HasSuffix(owner, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardFor returns the wildcard owner covering name: the "*" child of its
+// closest existing ancestor, if there is one.
+func (z testZone) wildcardFor(name string) string {
+	for parent := name; ; {
+		_, rest, ok := strings.Cut(parent, ".")
+		if !ok || rest == "" {
+			return ""
+		}
+		parent = rest
+		if z.exists(parent) {
+			if _, ok := z["*."+parent]; ok {
+				return "*." + parent
+			}
+			return ""
+		}
+	}
+}
+
+// startDNSServer serves handler over UDP and TCP on one local port and
+// returns its address.
+func startDNSServer(t *testing.T, handler dns.Handler) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen on UDP: %v", err)
+	}
+	ln, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		_ = pc.Close()
+		t.Fatalf("Failed to listen on TCP: %v", err)
+	}
+
+	for _, server := range []*dns.Server{
+		{PacketConn: pc, Handler: handler},
+		{Listener: ln, Handler: handler},
+	} {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+		go func() { _ = server.ActivateAndServe() }()
+		t.Cleanup(func() { _ = server.Shutdown() })
+		<-started
+	}
+	return pc.LocalAddr().String()
+}
+
+// testResolverContext returns a context carrying a resolver pool that only
+// queries handler.
+func testResolverContext(t *testing.T, handler dns.Handler) context.Context {
+	t.Helper()
+	pool, err := resolver.NewPool([]string{startDNSServer(t, handler)})
+	if err != nil {
+		t.Fatalf("NewPool returned error: %v", err)
+	}
+	return resolver.NewContext(context.Background(), pool)
+}
@@ -2,12 +2,17 @@ package enumerator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/scope"
 	"github.com/itszeeshan/subdomainx/v2/internal/tui"
 	"github.com/itszeeshan/subdomainx/v2/internal/types"
 	"github.com/itszeeshan/subdomainx/v2/internal/utils"
@@ -18,17 +23,67 @@ type Enumerator interface {
 	Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error)
 }
 
+// SeededEnumerator is an Enumerator that runs after the first enumeration
+// pass and derives new names from the subdomains found so far.
+type SeededEnumerator interface {
+	Enumerator
+	EnumerateSeeded(ctx context.Context, domain string, known []string, cfg *config.Config) ([]string, error)
+}
+
+// FindingEnumerator is an Enumerator that can also report findings about
+// the zones it enumerates, such as nameservers allowing zone transfers.
+type FindingEnumerator interface {
+	Enumerator
+	EnumerateFindings(ctx context.Context, domain string, cfg *config.Config) ([]string, []types.Finding, error)
+}
+
 var enumerators = make(map[string]Enumerator)
 
 func RegisterEnumerator(e Enumerator) {
 	enumerators[e.Name()] = e
 }
 
-func Run(cfg *config.Config, sink tui.EventSink) ([]types.SubdomainResult, error) {
-	// Read domains from wildcard file
-	domains, err := utils.ReadLines(cfg.WildcardFile)
+// Run enumerates the domains in cfg.WildcardFile with every enabled tool and
+// returns the subdomains along with any findings the tools reported.
+// ctx carries the scan's resolver; cancelling it stops every pass.
+func Run(ctx context.Context, cfg *config.Config, sink tui.EventSink) ([]types.SubdomainResult, []types.Finding, error) {
+	// Read domains from wildcard file; imports need none
+	var domains []string
+	if cfg.WildcardFile != "" || len(cfg.ImportFiles) == 0 {
+		var err error
+		if domains, err = utils.ReadLines(cfg.WildcardFile); err != nil {
+			return nil, nil, fmt.Errorf("failed to read wildcard file: %v", err)
+		}
+	}
+
+	// Names outside the program scope are dropped as soon as a tool reports them
+	sc, err := scope.New(cfg, domains, cfg.TargetHosts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read wildcard file: %v", err)
+		return nil, nil, err
+	}
+
+	// Collect and deduplicate subdomains, tracking sources per subdomain
+	collector := newSubdomainCollector(sink, sc)
+
+	// Rate limits, budgets and circuit breakers are shared by all domains
+	ctx = withPolicies(ctx, newPolicySet(cfg))
+
+	// Explicit hosts and addresses from the input are scanned as given
+	collector.add(cfg.TargetHosts, "input")
+
+	// Imported recon output replaces enumeration altogether
+	if len(cfg.ImportFiles) > 0 {
+		if err := importFiles(cfg.ImportFiles, collector, sink); err != nil {
+			return nil, nil, err
+		}
+		finalResults := filterWildcards(ctx, cfg, domains, collector.results(), sink)
+		sink.SubdomainsFound(finalResults, len(finalResults))
+		return finalResults, nil, nil
+	}
+	if len(domains) == 0 {
+		results := collector.results()
+		sink.SubdomainsFound(results, len(results))
+		return results, nil, nil
 	}
 
 	// Filter enumerators to only include available tools
@@ -42,7 +97,7 @@ func Run(cfg *config.Config, sink tui.EventSink) ([]types.SubdomainResult, error
 	}
 
 	if len(availableEnumerators) == 0 {
-		return nil, fmt.Errorf("no enumeration tools are available")
+		return nil, nil, fmt.Errorf("no enumeration tools are available")
 	}
 
 	var toolNames []string
@@ -51,47 +106,157 @@ func Run(cfg *config.Config, sink tui.EventSink) ([]types.SubdomainResult, error
 	}
 	sink.Log("info", fmt.Sprintf("Using %d enumeration tools: %s", len(availableEnumerators), strings.Join(toolNames, ", ")))
 
-	// Use a more reasonable timeout calculation
-	totalTimeout := cfg.Timeout * len(domains) * len(availableEnumerators)
-	if totalTimeout > 300 { // Cap at 5 minutes
-		totalTimeout = 300
+	// Seeded enumerators (e.g. permutations) run in a last pass over what
+	// the earlier passes found.
+	firstPass := make(map[string]Enumerator)
+	seededPass := make(map[string]Enumerator)
+	for name, e := range availableEnumerators {
+		if _, ok := e.(SeededEnumerator); ok {
+			seededPass[name] = e
+		} else {
+			firstPass[name] = e
+		}
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(totalTimeout)*time.Second)
-	defer cancel()
 
-	type subdomainEntry struct {
-		subdomain string
-		source    string
+	// Periodic resource check during enumeration
+	monitorCtx, stopMonitor := context.WithCancel(ctx)
+	defer stopMonitor()
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				utils.CheckResources()
+			case <-monitorCtx.Done():
+				return
+			}
+		}
+	}()
+
+	enumerate := func(ctx context.Context, e Enumerator, target string, emit func(string)) ([]string, error) {
+		if f, ok := e.(FindingEnumerator); ok {
+			subdomains, findings, err := f.EnumerateFindings(ctx, target, cfg)
+			collector.addFindings(findings)
+			return subdomains, err
+		}
+		return enumerateLive(ctx, e, target, cfg, emit)
 	}
 
-	// Create a worker pool for concurrent enumeration
-	subdomainChan := make(chan subdomainEntry, 1000)
-	var wg sync.WaitGroup
+	// First pass over the apex domains
+	runPass(ctx, cfg, sink, domains, firstPass, collector, enumerate)
+
+	// Recursion: queue newly found sub-zones as enumeration targets
+	enumerated := make(map[string]bool)
+	for _, d := range domains {
+		enumerated[d] = true
+	}
+	for depth := 1; depth <= cfg.RecursionDepth && len(firstPass) > 0; depth++ {
+		zones := findSubZones(ctx, cfg, domains, collector.all(), enumerated)
+		if len(zones) == 0 {
+			break
+		}
+		sink.Log("info", fmt.Sprintf("Recursion depth %d: enumerating %d sub-zones: %s", depth, len(zones), strings.Join(zones, ", ")))
+		for _, z := range zones {
+			enumerated[z] = true
+		}
+		runPass(ctx, cfg, sink, zones, firstPass, collector, enumerate)
+	}
+
+	// Seeded pass: enumerators that take discovered hosts as input
+	runPass(ctx, cfg, sink, domains, seededPass, collector, func(ctx context.Context, e Enumerator, target string, _ func(string)) ([]string, error) {
+		return e.(SeededEnumerator).EnumerateSeeded(ctx, target, collector.under(target), cfg)
+	})
+
+	// Drop or flag results that only match wildcard DNS answers
+	finalResults := filterWildcards(ctx, cfg, domains, collector.results(), sink)
+
+	sink.SubdomainsFound(finalResults, len(finalResults))
+
+	return finalResults, collector.findingList(), nil
+}
+
+// runPass runs every tool against every target concurrently, with its own
+// time budget and progress bar, and adds the results to collector. enumerate
+// may report subdomains early through emit; they reach the sink immediately.
+// A tool whose budget (or its domain's) runs out keeps what it reported so
+// far and is reported as "partial".
+func runPass(ctx context.Context, cfg *config.Config, sink tui.EventSink, targets []string, tools map[string]Enumerator, collector *subdomainCollector, enumerate func(ctx context.Context, e Enumerator, target string, emit func(string)) ([]string, error)) {
+	if len(targets) == 0 || len(tools) == 0 {
+		return
+	}
+
+	// Tools without a configured budget share the old pass-wide timeout
+	defaultBudget := cfg.Timeout * len(targets) * len(tools)
+	if defaultBudget > 300 { // Cap at 5 minutes
+		defaultBudget = 300
+	}
+	if n := cfg.ToolTimeouts["default"]; n > 0 {
+		defaultBudget = n
+	}
 
 	// Calculate total tasks for progress tracking
-	totalTasks := len(domains) * len(availableEnumerators)
 	completedTasks := 0
 	var progressMutex sync.Mutex
+	utils.StartEnumerationProgress(len(targets) * len(tools))
+	defer utils.FinishEnumerationProgress()
 
-	// Start progress tracking
-	utils.StartEnumerationProgress(totalTasks)
+	// Start a goroutine for each enabled and available enumerator and target
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		domainCtx := ctx
+		if cfg.DomainTimeout > 0 {
+			var cancel context.CancelFunc
+			domainCtx, cancel = context.WithTimeout(ctx, time.Duration(cfg.DomainTimeout)*time.Second)
+			defer cancel()
+		}
+		for name, enumerator := range tools {
+			budget := defaultBudget
+			if n := cfg.ToolTimeouts[name]; n > 0 {
+				budget = n
+			}
 
-	// Start a goroutine for each enabled and available enumerator and domain
-	for _, domain := range domains {
-		for name, enumerator := range availableEnumerators {
 			wg.Add(1)
-			go func(e Enumerator, d string, toolName string) {
+			go func(e Enumerator, t string, toolName string) {
 				defer wg.Done()
 
-				// Use retry mechanism
+				budgetCtx, cancel := context.WithTimeout(domainCtx, time.Duration(budget)*time.Second)
+				defer cancel()
+				emit := func(subdomain string) {
+					collector.add([]string{subdomain}, toolName)
+				}
+				toolCtx := withProgress(budgetCtx, func(status string, found int) {
+					sink.ToolProgress(toolName, t, status, found, nil)
+				})
+
+				// Use retry mechanism, under the source's policy
+				policy := policyFor(ctx, toolName)
+				partial := false
 				subdomains, err := utils.Retry(func() ([]string, error) {
-					return e.Enumerate(ctx, d, cfg)
+					return policy.call(toolCtx, func(ctx context.Context) ([]string, error) {
+						subdomains, err := enumerate(ctx, e, t, emit)
+						if !errors.Is(budgetCtx.Err(), context.DeadlineExceeded) {
+							return subdomains, err
+						}
+						if len(subdomains) > 0 {
+							partial = true
+							return subdomains, nil
+						}
+						return nil, utils.StopRetry(fmt.Errorf("time budget of %ds expired before any results", budget))
+					})
 				}, cfg.Retries, cfg.Timeout)
-
-				if err != nil {
-					sink.ToolProgress(toolName, d, "failed", 0, err)
-				} else {
-					sink.ToolProgress(toolName, d, "completed", len(subdomains), nil)
+				switch {
+				case errors.Is(err, errCircuitOpen):
+					sink.ToolProgress(toolName, t, "circuit-open", 0, err)
+				case partial:
+					sink.ToolProgress(toolName, t, "partial", len(subdomains), nil)
+				default:
+					reportTool(sink, toolName, t, subdomains, err)
+				}
+				collector.add(subdomains, toolName)
+				if partial {
+					collector.markPartial(subdomains, toolName)
 				}
 
 				// Update progress
@@ -99,64 +264,160 @@ func Run(cfg *config.Config, sink tui.EventSink) ([]types.SubdomainResult, error
 				completedTasks++
 				utils.UpdateEnumerationProgress(completedTasks)
 				progressMutex.Unlock()
-
-				// Send subdomains to channel without DNS resolution
-				for _, subdomain := range subdomains {
-					subdomainChan <- subdomainEntry{subdomain: subdomain, source: toolName}
-				}
-			}(enumerator, domain, name)
+			}(enumerator, target, name)
 		}
 	}
+	wg.Wait()
+}
 
-	// Wait for all enumerators to complete and close subdomain channel
-	go func() {
-		wg.Wait()
-		utils.FinishEnumerationProgress()
-		close(subdomainChan)
-	}()
+type progressKey struct{}
 
-	// Periodic resource check during enumeration
-	go func() {
-		ticker := time.NewTicker(10 * time.Second)
-		defer ticker.Stop()
+// withProgress returns a copy of ctx carrying report, through which a
+// long-running enumerator shows how far it got before it completes.
+func withProgress(ctx context.Context, report func(status string, found int)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
 
-		for {
-			select {
-			case <-ticker.C:
-				utils.CheckResources()
-			case <-ctx.Done():
-				return
+// reportProgress sends status and the number of names found so far for the
+// enumerator run in ctx, if anyone is listening.
+func reportProgress(ctx context.Context, status string, found int) {
+	if report, ok := ctx.Value(progressKey{}).(func(string, int)); ok {
+		report(status, found)
+	}
+}
+
+// reportTool sends the outcome of one enumerator run to the sink.
+func reportTool(sink tui.EventSink, toolName, domain string, subdomains []string, err error) {
+	if err != nil {
+		sink.ToolProgress(toolName, domain, "failed", 0, err)
+	} else {
+		sink.ToolProgress(toolName, domain, "completed", len(subdomains), nil)
+	}
+}
+
+// subdomainCollector deduplicates subdomains reported by concurrent
+// enumerators, records which tools found each one and when, and reports every
+// new subdomain/source pair to the sink as it arrives. Out-of-scope names are
+// logged once and dropped. Findings are deduplicated the same way.
+type subdomainCollector struct {
+	mu       sync.Mutex
+	sink     tui.EventSink
+	scope    *scope.Scope
+	found    map[string]*types.SubdomainResult
+	dropped  map[string]bool
+	findings []types.Finding
+}
+
+func newSubdomainCollector(sink tui.EventSink, sc *scope.Scope) *subdomainCollector {
+	return &subdomainCollector{
+		sink:    sink,
+		scope:   sc,
+		found:   make(map[string]*types.SubdomainResult),
+		dropped: make(map[string]bool),
+	}
+}
+
+func (c *subdomainCollector) add(subdomains []string, source string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for _, subdomain := range subdomains {
+		if c.dropped[subdomain] {
+			continue
+		}
+		r, known := c.found[subdomain]
+		if !known {
+			if ok, reason := c.scope.Host(subdomain); !ok {
+				c.dropped[subdomain] = true
+				c.sink.Log("info", fmt.Sprintf("Out of scope: %s (%s)", subdomain, reason))
+				continue
+			}
+			r = &types.SubdomainResult{Subdomain: subdomain, IPs: []string{}}
+			if net.ParseIP(subdomain) != nil {
+				r.IPs = []string{subdomain} // address targets need no resolution
 			}
+			c.found[subdomain] = r
 		}
-	}()
 
-	// Collect and deduplicate subdomains, tracking sources per subdomain
-	subdomainSources := make(map[string][]string)
-	for entry := range subdomainChan {
-		sources := subdomainSources[entry.subdomain]
-		alreadyHas := false
-		for _, s := range sources {
-			if s == entry.source {
-				alreadyHas = true
-				break
-			}
+		if r.AddSource(source, now) {
+			c.sink.SubdomainDiscovered(subdomain, source, len(c.found))
 		}
-		if !alreadyHas {
-			subdomainSources[entry.subdomain] = append(subdomainSources[entry.subdomain], entry.source)
+	}
+}
+
+// markPartial flags the records of source on subdomains as cut short by
+// the source's time budget.
+func (c *subdomainCollector) markPartial(subdomains []string, source string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, subdomain := range subdomains {
+		if r, ok := c.found[subdomain]; ok {
+			r.MarkPartial(source)
 		}
 	}
+}
+
+// addFindings records findings not reported before, keyed on type, target
+// and title, and logs each new one.
+func (c *subdomainCollector) addFindings(findings []types.Finding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range findings {
+		if slices.ContainsFunc(c.findings, func(g types.Finding) bool {
+			return g.Type == f.Type && g.Target == f.Target && g.Title == f.Title
+		}) {
+			continue
+		}
+		c.findings = append(c.findings, f)
+		c.sink.Log("warn", fmt.Sprintf("Finding [%s] %s", f.Severity, f.Title))
+	}
+}
+
+// findingList returns a copy of the collected findings.
+func (c *subdomainCollector) findingList() []types.Finding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.findings)
+}
 
-	// Create results without DNS resolution for better performance
-	var finalResults []types.SubdomainResult
-	for subdomain, sources := range subdomainSources {
-		finalResults = append(finalResults, types.SubdomainResult{
-			Subdomain: subdomain,
-			Source:    strings.Join(sources, ","),
-			IPs:       []string{},
-		})
+// results converts the collected subdomains into scored results; DNS
+// resolution runs as its own stage later and rescores them.
+func (c *subdomainCollector) results() []types.SubdomainResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	results := make([]types.SubdomainResult, 0, len(c.found))
+	for _, r := range c.found {
+		result := *r
+		result.Sources = slices.Clone(r.Sources)
+		result.IPs = slices.Clone(r.IPs)
+		result.Score()
+		results = append(results, result)
 	}
+	return results
+}
 
-	sink.SubdomainsFound(finalResults, len(subdomainSources))
+// all returns every collected subdomain, sorted.
+func (c *subdomainCollector) all() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subs := make([]string, 0, len(c.found))
+	for subdomain := range c.found {
+		subs = append(subs, subdomain)
+	}
+	sort.Strings(subs)
+	return subs
+}
 
-	return finalResults, nil
+// under returns the collected subdomains that belong to domain, sorted.
+func (c *subdomainCollector) under(domain string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var subs []string
+	for subdomain := range c.found {
+		if strings.HasSuffix(subdomain, "."+domain) {
+			subs = append(subs, subdomain)
+		}
+	}
+	sort.Strings(subs)
+	return subs
 }
@@ -0,0 +1,70 @@
+package enumerator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/scope"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+)
+
+// slowEnumerator streams one name, then runs until it is stopped.
+type slowEnumerator struct{}
+
+func (slowEnumerator) Name() string { return "slow" }
+
+func (s slowEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, s, domain, cfg)
+}
+
+func (slowEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
+	emit("early." + domain)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// statusSink records the tool statuses it is sent.
+type statusSink struct {
+	*tui.CLIEventSink
+	mu       sync.Mutex
+	statuses []string
+}
+
+func (s *statusSink) ToolProgress(tool, domain, status string, found int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses = append(s.statuses, status)
+}
+
+func TestRunPassPartialOnBudget(t *testing.T) {
+	cfg := &config.Config{Retries: 3, Timeout: 30, ToolTimeouts: map[string]int{"slow": 1}}
+	sc, err := scope.New(cfg, []string{"example.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &statusSink{CLIEventSink: tui.NewCLIEventSink()}
+	collector := newSubdomainCollector(sink, sc)
+
+	start := time.Now()
+	runPass(context.Background(), cfg, sink, []string{"example.com"}, map[string]Enumerator{"slow": slowEnumerator{}}, collector,
+		func(ctx context.Context, e Enumerator, target string, emit func(string)) ([]string, error) {
+			return enumerateLive(ctx, e, target, cfg, emit)
+		})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the budget to stop the tool without retries, took %v", elapsed)
+	}
+
+	if len(sink.statuses) != 1 || sink.statuses[0] != "partial" {
+		t.Errorf("Expected one partial report, got %v", sink.statuses)
+	}
+	results := collector.results()
+	if len(results) != 1 || results[0].Subdomain != "early.example.com" {
+		t.Fatalf("Expected the streamed name to be kept, got %+v", results)
+	}
+	if len(results[0].Sources) != 1 || !results[0].Sources[0].Partial {
+		t.Errorf("Expected the source record to be marked partial, got %+v", results[0].Sources)
+	}
+}
@@ -11,6 +11,13 @@ import (
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
 )
 
+var (
+	// Match patterns like "Found: hostname.domain. (IP)"
+	fierceFoundRe = regexp.MustCompile(`(?i)Found:\s+(\S+)`)
+	// Also match lines with IP-to-hostname mappings
+	fierceIPHostRe = regexp.MustCompile(`(\S+\.\S+)\.\s+\(?\d`)
+)
+
 type FierceEnumerator struct{}
 
 func (f *FierceEnumerator) Name() string {
@@ -18,45 +25,37 @@ func (f *FierceEnumerator) Name() string {
 }
 
 func (f *FierceEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, f, domain, cfg)
+}
+
+// EnumerateStream emits hostnames as fierce prints them.
+func (f *FierceEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
 	args := []string{"--domain", domain}
 
 	cmd := exec.CommandContext(ctx, "fierce", args...)
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("fierce execution failed: %v", err)
-	}
-
 	// Parse fierce output — it prints found subdomains with their IPs
 	// Lines look like: "Found: sub.example.com. (1.2.3.4)" or just hostnames
-	var subdomains []string
 	seen := make(map[string]bool)
+	add := func(host string) {
+		if !seen[host] {
+			seen[host] = true
+			emit(host)
+		}
+	}
 
-	// Match patterns like "Found: hostname.domain. (IP)" or bare hostnames
-	foundRe := regexp.MustCompile(`(?i)Found:\s+(\S+)`)
-	// Also match lines with IP-to-hostname mappings
-	ipHostRe := regexp.MustCompile(`(\S+\.\S+)\.\s+\(?\d`)
-
-	for _, line := range strings.Split(string(output), "\n") {
-		line = strings.TrimSpace(line)
-
-		if matches := foundRe.FindStringSubmatch(line); len(matches) > 1 {
+	err := streamCommand(cmd, func(line string) {
+		if matches := fierceFoundRe.FindStringSubmatch(line); len(matches) > 1 {
 			host := strings.TrimSuffix(matches[1], ".")
 			if strings.HasSuffix(host, "."+domain) || host == domain {
-				if !seen[host] {
-					seen[host] = true
-					subdomains = append(subdomains, host)
-				}
+				add(host)
 			}
 		}
 
-		if matches := ipHostRe.FindStringSubmatch(line); len(matches) > 1 {
+		if matches := fierceIPHostRe.FindStringSubmatch(line); len(matches) > 1 {
 			host := strings.TrimSuffix(matches[1], ".")
 			if strings.HasSuffix(host, "."+domain) || host == domain {
-				if !seen[host] {
-					seen[host] = true
-					subdomains = append(subdomains, host)
-				}
+				add(host)
 			}
 		}
 
@@ -65,15 +64,14 @@ func (f *FierceEnumerator) Enumerate(ctx context.Context, domain string, cfg *co
 			word = strings.TrimSuffix(word, ".")
 			word = strings.Trim(word, "()")
 			if strings.HasSuffix(word, "."+domain) && net.ParseIP(word) == nil {
-				if !seen[word] {
-					seen[word] = true
-					subdomains = append(subdomains, word)
-				}
+				add(word)
 			}
 		}
+	})
+	if err != nil {
+		return fmt.Errorf("fierce execution failed: %v", err)
 	}
-
-	return subdomains, nil
+	return nil
 }
 
 func init() {
@@ -5,16 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
-	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
 )
 
 type HackerTargetEnumerator struct {
-	apiKey string
 	client *http.Client
 }
 
@@ -26,23 +24,20 @@ func (h *HackerTargetEnumerator) Enumerate(ctx context.Context, domain string, c
 	// Build API URL
 	url := fmt.Sprintf("https://api.hackertarget.com/hostsearch/?q=%s", domain)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	// Add headers
-	req.Header.Set("User-Agent", "SubdomainX/1.0")
-	req.Header.Set("Accept", "text/plain")
-
-	// Add API key if available
-	if h.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", h.apiKey))
-	}
-
-	// Make request with 429 retry handling
-	resp, err := utils.DoWithRetry(h.client, req, cfg.Retries, cfg.Timeout)
+	// Make request, rotating API keys on 429 and quota errors; the key is
+	// optional, so without one the request goes out unauthenticated
+	resp, err := doWithKeys(h.client, cfg, "hackertarget", false, func(key *credentials.Key) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("User-Agent", "SubdomainX/1.0")
+		req.Header.Set("Accept", "text/plain")
+		if key != nil {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key.Key))
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("hackertarget API request failed: %v", err)
 	}
@@ -86,21 +81,9 @@ func (h *HackerTargetEnumerator) Enumerate(ctx context.Context, domain string, c
 func init() {
 	// Create HackerTarget enumerator with default settings
 	enumerator := &HackerTargetEnumerator{
-		apiKey: getHackerTargetAPIKey(),
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 	RegisterEnumerator(enumerator)
 }
-
-// getHackerTargetAPIKey retrieves the API key from environment variable
-func getHackerTargetAPIKey() string {
-	// Try to get from environment variable
-	apiKey := strings.TrimSpace(os.Getenv("HACKERTARGET_API_KEY"))
-	if apiKey == "" {
-		// Could also try to get from config file in the future
-		return ""
-	}
-	return apiKey
-}
@@ -0,0 +1,38 @@
+package enumerator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/importer"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+)
+
+// importFiles adds the names in the recon output files matching patterns
+// to collector, each recorded under the tool that found it.
+func importFiles(patterns []string, collector *subdomainCollector, sink tui.EventSink) error {
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil || len(files) == 0 {
+			files = []string{pattern} // let ParseFile report the missing file
+		}
+		for _, file := range files {
+			result, err := importer.ParseFile(file)
+			if err != nil {
+				return err
+			}
+			tools := make([]string, 0, len(result.Names))
+			for tool := range result.Names {
+				tools = append(tools, tool)
+			}
+			sort.Strings(tools)
+			for _, tool := range tools {
+				collector.add(result.Names[tool], tool)
+				sink.ToolProgress(tool, file, "completed", len(result.Names[tool]), nil)
+			}
+			sink.Log("info", fmt.Sprintf("Imported %d names from %s (%s)", result.Count(), file, result.Format))
+		}
+	}
+	return nil
+}
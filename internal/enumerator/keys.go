@@ -0,0 +1,87 @@
+package enumerator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// doWithKeys sends the request built for each of provider's keys in turn,
+// round-robin, until one is answered without hitting a rate limit or
+// quota; limited keys are set aside for later requests. While other keys
+// remain, a 429 rotates at once instead of backing off. Providers whose key
+// is optional are queried without one (build gets nil) when none is
+// configured.
+func doWithKeys(client *http.Client, cfg *config.Config, provider string, required bool, build func(key *credentials.Key) (*http.Request, error)) (*http.Response, error) {
+	count := credentials.Count(provider)
+	if count == 0 {
+		if required {
+			return nil, fmt.Errorf("%s API key not configured", provider)
+		}
+		req, err := build(nil)
+		if err != nil {
+			return nil, err
+		}
+		return doRequest(client, req, cfg.Retries, cfg.Timeout)
+	}
+
+	lastErr := fmt.Errorf("all %s API keys are rate limited or out of quota", provider)
+	for i := range count {
+		key := credentials.Next(provider)
+		if key == nil {
+			break
+		}
+		req, err := build(key)
+		if err != nil {
+			return nil, err
+		}
+		retries := cfg.Retries
+		if i < count-1 {
+			retries = 0
+		}
+
+		resp, err := doRequest(client, req, retries, cfg.Timeout)
+		var rateLimited *utils.RateLimitError
+		if errors.As(err, &rateLimited) {
+			credentials.RateLimited(provider, key, rateLimited.RetryAfter)
+			lastErr = fmt.Errorf("%s API key %s: %v", provider, key.ID(), err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if quotaExceeded(resp) {
+			_ = resp.Body.Close()
+			credentials.Exhausted(provider, key)
+			lastErr = fmt.Errorf("%s API key %s: quota exceeded", provider, key.ID())
+			continue
+		}
+		credentials.Record(provider, key, resp.Header)
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// quotaExceeded reports whether resp says the key's quota is used up:
+// 402 Payment Required, or a 403 mentioning the quota. The body of other
+// 403s is left readable.
+func quotaExceeded(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusPaymentRequired:
+		return true
+	case http.StatusForbidden:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		lower := strings.ToLower(string(body))
+		return strings.Contains(lower, "quota") || strings.Contains(lower, "exceeded")
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package enumerator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
+)
+
+func TestDoWithKeysRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.yaml")
+	if err := os.WriteFile(path, []byte("testrotate:\n  - key: limited\n  - key: spent\n  - key: good\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := credentials.Load(path, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-Key")
+		used = append(used, key)
+		switch key {
+		case "limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "spent":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"monthly quota exceeded"}`))
+		default:
+			w.Header().Set("X-RateLimit-Remaining", "7")
+		}
+	}))
+	defer server.Close()
+
+	build := func(key *credentials.Key) (*http.Request, error) {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		if err == nil {
+			req.Header.Set("X-Key", key.Key)
+		}
+		return req, err
+	}
+	cfg := &config.Config{Timeout: 5}
+
+	resp, err := doWithKeys(server.Client(), cfg, "testrotate", true, build)
+	if err != nil {
+		t.Fatalf("doWithKeys returned error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if strings.Join(used, ",") != "limited,spent,good" {
+		t.Errorf("Expected each key to be tried once, got %v", used)
+	}
+
+	// The limited and spent keys are now set aside
+	used = nil
+	resp, err = doWithKeys(server.Client(), cfg, "testrotate", true, build)
+	if err != nil {
+		t.Fatalf("doWithKeys returned error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if strings.Join(used, ",") != "good" {
+		t.Errorf("Expected only the good key to be used, got %v", used)
+	}
+	if status := credentials.Status("testrotate"); !strings.Contains(status, "7 remaining") || !strings.Contains(status, "exhausted until") {
+		t.Errorf("Unexpected status %q", status)
+	}
+
+	if _, err := doWithKeys(server.Client(), cfg, "testmissing", true, build); err == nil {
+		t.Error("Expected an error for a provider without keys")
+	}
+}
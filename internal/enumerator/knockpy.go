@@ -16,29 +16,26 @@ func (k *KnockpyEnumerator) Name() string {
 }
 
 func (k *KnockpyEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
+	return collectStream(ctx, k, domain, cfg)
+}
+
+// EnumerateStream emits subdomains as knockpy prints them.
+func (k *KnockpyEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
 	// Build knockpy command
 	args := []string{domain, "--no-http"}
 
 	cmd := exec.CommandContext(ctx, "knockpy", args...)
 
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("knockpy execution failed: %v", err)
-	}
-
 	// Parse output (one subdomain per line)
-	lines := strings.Split(string(output), "\n")
-	var subdomains []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			subdomains = append(subdomains, line)
-			// Cache the DNS result
+	err := streamCommand(cmd, func(line string) {
+		if !strings.HasPrefix(line, "#") {
+			emit(line)
 		}
+	})
+	if err != nil {
+		return fmt.Errorf("knockpy execution failed: %v", err)
 	}
-
-	return subdomains, nil
+	return nil
 }
 
 func init() {
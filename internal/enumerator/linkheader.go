@@ -6,12 +6,19 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
 	"github.com/tomnomnom/linkheader"
 )
 
+// maxLinkHeaderTargets caps the discovered hosts whose Link headers are
+// fetched, since every host costs two HTTP requests.
+const maxLinkHeaderTargets = 200
+
 type LinkHeaderEnumerator struct {
 	client *http.Client
 }
@@ -21,34 +28,63 @@ func (l *LinkHeaderEnumerator) Name() string {
 }
 
 func (l *LinkHeaderEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
-	// This enumerator works by checking discovered subdomains for Link headers
-	// It needs to be run after other enumerators have discovered initial subdomains
-	// For now, we'll check the main domain and common subdomains
-
-	subdomainSet := make(map[string]bool)
-
-	// Check main domain and common subdomains for Link headers
-	targets := []string{
-		domain,
-		"www." + domain,
-		"api." + domain,
-		"docs." + domain,
-		"dev." + domain,
-		"staging." + domain,
-		"test." + domain,
-	}
+	return l.EnumerateSeeded(ctx, domain, nil, cfg)
+}
 
-	for _, target := range targets {
-		subdomains, err := l.checkLinkHeaders(ctx, target, domain)
-		if err != nil {
-			// Continue with other targets even if one fails
-			continue
+// EnumerateSeeded checks the Link headers of the subdomains found by the
+// first pass. Without any it falls back to the main domain and a few common
+// subdomains.
+func (l *LinkHeaderEnumerator) EnumerateSeeded(ctx context.Context, domain string, known []string, cfg *config.Config) ([]string, error) {
+	targets := []string{domain}
+	if len(known) == 0 {
+		for _, prefix := range []string{"www", "api", "docs", "dev", "staging", "test"} {
+			targets = append(targets, prefix+"."+domain)
 		}
-
-		for _, subdomain := range subdomains {
-			subdomainSet[subdomain] = true
+	} else {
+		if len(known) > maxLinkHeaderTargets {
+			known = known[:maxLinkHeaderTargets]
 		}
+		for _, k := range known {
+			if k != domain {
+				targets = append(targets, k)
+			}
+		}
+	}
+
+	threads := cfg.Threads
+	if threads <= 0 {
+		threads = 10
+	}
+	pool := utils.NewWorkerPool(threads, cfg.RateLimit)
+	defer pool.Stop()
+
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		subdomainSet = make(map[string]bool)
+	)
+	for _, target := range targets {
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+			if ctx.Err() != nil {
+				return
+			}
+
+			subdomains, err := l.checkLinkHeaders(ctx, target, domain)
+			if err != nil {
+				// Continue with other targets even if one fails
+				return
+			}
+
+			mu.Lock()
+			for _, subdomain := range subdomains {
+				subdomainSet[subdomain] = true
+			}
+			mu.Unlock()
+		})
 	}
+	wg.Wait()
 
 	// Convert set to slice
 	var subdomains []string
@@ -132,7 +168,8 @@ func init() {
 	// Create Link Header enumerator with default settings
 	enumerator := &LinkHeaderEnumerator{
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: resolver.NewHTTPTransport(),
 		},
 	}
 	RegisterEnumerator(enumerator)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
 	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/pkg/wordlists"
 )
 
 type MassDNSEnumerator struct{}
@@ -20,10 +21,15 @@ func (m *MassDNSEnumerator) Name() string {
 }
 
 func (m *MassDNSEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, m, domain, cfg)
+}
+
+// EnumerateStream emits names as massdns resolves them.
+func (m *MassDNSEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
 	// Find resolver file
 	resolverFile := findResolverFile()
 	if resolverFile == "" {
-		return nil, fmt.Errorf("massdns: no resolver file found; provide one or install massdns with resolvers")
+		return fmt.Errorf("massdns: no resolver file found; provide one or install massdns with resolvers")
 	}
 
 	// Build subdomain list to resolve
@@ -31,15 +37,13 @@ func (m *MassDNSEnumerator) Enumerate(ctx context.Context, domain string, cfg *c
 	if cfg.Wordlist != "" {
 		words, err := utils.ReadLines(cfg.Wordlist)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read wordlist: %v", err)
+			return fmt.Errorf("failed to read wordlist: %v", err)
 		}
 		for _, word := range words {
 			subdomains = append(subdomains, fmt.Sprintf("%s.%s", word, domain))
 		}
 	} else {
-		commonWords := []string{"www", "mail", "ftp", "admin", "blog", "dev", "test", "api", "cdn", "static",
-			"staging", "app", "ns1", "ns2", "mx", "pop", "imap", "smtp", "vpn", "remote"}
-		for _, word := range commonWords {
+		for _, word := range wordlists.Default() {
 			subdomains = append(subdomains, fmt.Sprintf("%s.%s", word, domain))
 		}
 	}
@@ -47,7 +51,7 @@ func (m *MassDNSEnumerator) Enumerate(ctx context.Context, domain string, cfg *c
 	// Write subdomains to temp file (massdns reads from file more reliably than stdin)
 	tmpFile, err := os.CreateTemp("", "massdns-input-*.txt")
 	if err != nil {
-		return nil, fmt.Errorf("massdns: failed to create temp file: %v", err)
+		return fmt.Errorf("massdns: failed to create temp file: %v", err)
 	}
 	tmpPath := tmpFile.Name()
 	_, _ = tmpFile.WriteString(strings.Join(subdomains, "\n"))
@@ -57,32 +61,22 @@ func (m *MassDNSEnumerator) Enumerate(ctx context.Context, domain string, cfg *c
 	args := []string{"-r", resolverFile, "-t", "A", "-o", "S", tmpPath}
 	cmd := exec.CommandContext(ctx, "massdns", args...)
 
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("massdns execution failed: %v", err)
-	}
-
 	// Parse massdns output format: subdomain. A IP
-	lines := strings.Split(string(output), "\n")
-	var results []string
 	seen := make(map[string]bool)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+	err = streamCommand(cmd, func(line string) {
 		parts := strings.Fields(line)
 		if len(parts) >= 3 && parts[1] == "A" {
 			subdomain := strings.TrimSuffix(parts[0], ".")
 			if !seen[subdomain] {
 				seen[subdomain] = true
-				results = append(results, subdomain)
+				emit(subdomain)
 			}
 		}
+	})
+	if err != nil {
+		return fmt.Errorf("massdns execution failed: %v", err)
 	}
-
-	return results, nil
+	return nil
 }
 
 // findResolverFile searches common locations for a DNS resolver list.
@@ -0,0 +1,322 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/pkg/wordlists"
+)
+
+// maxLearnedWords caps the words learned from discovered labels.
+const maxLearnedWords = 200
+
+// numberIncrementRange is how far number-increment steps up and down.
+const numberIncrementRange = 3
+
+var (
+	labelPattern  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	digitsPattern = regexp.MustCompile(`[0-9]+`)
+)
+
+// PermutationEnumerator permutes the subdomains found by the first
+// enumeration pass and keeps the candidates that resolve to something other
+// than a wildcard answer.
+type PermutationEnumerator struct{}
+
+func (p *PermutationEnumerator) Name() string {
+	return "permute"
+}
+
+func (p *PermutationEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return p.EnumerateSeeded(ctx, domain, nil, cfg)
+}
+
+// EnumerateSeeded runs cfg.PermutationDepth rounds; every round permutes the
+// names found by the previous one, and all rounds share cfg.PermutationBudget
+// candidates.
+func (p *PermutationEnumerator) EnumerateSeeded(ctx context.Context, domain string, known []string, cfg *config.Config) ([]string, error) {
+	depth := cfg.PermutationDepth
+	if depth <= 0 {
+		depth = 1
+	}
+	budget := cfg.PermutationBudget
+	if budget <= 0 {
+		budget = 20000
+	}
+
+	words := append(LearnWords(known, domain), wordlists.Permutations()...)
+	detector := NewWildcardDetector(resolver.FromContext(ctx))
+
+	seen := map[string]bool{domain: true}
+	for _, k := range known {
+		seen[k] = true
+	}
+	seeds := known
+	if len(seeds) == 0 {
+		seeds = []string{domain}
+	}
+
+	var found []string
+	for round := 0; round < depth && len(seeds) > 0 && budget > 0; round++ {
+		var candidates []string
+		for _, c := range GeneratePermutations(seeds, domain, words, budget+len(seen)) {
+			if !seen[c] {
+				seen[c] = true
+				candidates = append(candidates, c)
+			}
+			if len(candidates) == budget {
+				break
+			}
+		}
+		budget -= len(candidates)
+
+		seeds = resolvePermutations(ctx, candidates, domain, detector, cfg)
+		found = append(found, seeds...)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(found) == 0 && ctx.Err() != nil {
+		return nil, fmt.Errorf("permute: %v", ctx.Err())
+	}
+	return found, nil
+}
+
+// resolvePermutations returns the candidates that resolve and do not merely
+// match a wildcard record.
+func resolvePermutations(ctx context.Context, candidates []string, apex string, detector *WildcardDetector, cfg *config.Config) []string {
+	threads := cfg.DNSThreads
+	if threads <= 0 {
+		threads = 50
+	}
+	pool := utils.NewWorkerPool(threads, cfg.DNSRateLimit)
+	defer pool.Stop()
+
+	dnsResolver := resolver.FromContext(ctx)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		resolved []string
+	)
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+			if ctx.Err() != nil {
+				return
+			}
+
+			lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+			defer cancel()
+			if addrs, err := dnsResolver.LookupHost(lookupCtx, candidate+"."); err != nil || len(addrs) == 0 {
+				return
+			}
+			if wildcard, _ := detector.Check(ctx, candidate, apex); wildcard {
+				return
+			}
+
+			mu.Lock()
+			resolved = append(resolved, candidate)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	sort.Strings(resolved)
+	return resolved
+}
+
+// LearnWords extracts the words used in discovered subdomains of apex
+// (labels split on '-' and '.', with and without trailing digits), most
+// frequent first.
+func LearnWords(subdomains []string, apex string) []string {
+	counts := make(map[string]int)
+	for _, sub := range subdomains {
+		sub = strings.ToLower(sub)
+		prefix := strings.TrimSuffix(sub, "."+apex)
+		if prefix == sub || prefix == "" {
+			continue
+		}
+		for _, token := range strings.FieldsFunc(prefix, func(r rune) bool { return r == '.' || r == '-' }) {
+			counts[token]++
+			if trimmed := strings.TrimRight(token, "0123456789"); trimmed != token && len(trimmed) > 1 {
+				counts[trimmed]++
+			}
+		}
+	}
+
+	var words []string
+	for w := range counts {
+		if len(w) > 1 && labelPattern.MatchString(w) {
+			words = append(words, w)
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > maxLearnedWords {
+		words = words[:maxLearnedWords]
+	}
+	return words
+}
+
+// GeneratePermutations derives candidate names under apex from seeds. Rules
+// are applied cheapest first so a tight limit keeps the most likely hits:
+// number increment, dash/dot swap, prefix/suffix with a dash, then inserting
+// a word as a new label at every position. Seeds themselves are never
+// returned and at most limit candidates are generated.
+func GeneratePermutations(seeds []string, apex string, words []string, limit int) []string {
+	seen := make(map[string]bool)
+	for _, s := range seeds {
+		seen[strings.ToLower(s)] = true
+	}
+
+	var candidates []string
+	add := func(labels []string) bool {
+		if len(candidates) >= limit {
+			return false
+		}
+		if len(labels) == 0 {
+			return true
+		}
+		for _, l := range labels {
+			if !labelPattern.MatchString(l) {
+				return true
+			}
+		}
+		name := strings.Join(labels, ".") + "." + apex
+		if len(name) > 253 || seen[name] {
+			return true
+		}
+		seen[name] = true
+		candidates = append(candidates, name)
+		return true
+	}
+
+	prefixes := make([][]string, 0, len(seeds))
+	for _, s := range seeds {
+		s = strings.ToLower(s)
+		if s == apex {
+			prefixes = append(prefixes, nil)
+			continue
+		}
+		if p := strings.TrimSuffix(s, "."+apex); p != s {
+			prefixes = append(prefixes, strings.Split(p, "."))
+		}
+	}
+
+	rules := []func(labels []string) bool{
+		func(labels []string) bool { return incrementNumbers(labels, add) },
+		func(labels []string) bool { return swapDashDot(labels, add) },
+		func(labels []string) bool {
+			if len(labels) == 0 {
+				return true
+			}
+			for _, w := range words {
+				if !add(append([]string{w + "-" + labels[0]}, labels[1:]...)) ||
+					!add(append([]string{labels[0] + "-" + w}, labels[1:]...)) {
+					return false
+				}
+			}
+			return true
+		},
+		func(labels []string) bool {
+			for _, w := range words {
+				for pos := 0; pos <= len(labels); pos++ {
+					next := append(append(append([]string{}, labels[:pos]...), w), labels[pos:]...)
+					if !add(next) {
+						return false
+					}
+				}
+			}
+			return true
+		},
+	}
+
+	for _, rule := range rules {
+		for _, labels := range prefixes {
+			if !rule(labels) {
+				return candidates
+			}
+		}
+	}
+	return candidates
+}
+
+// incrementNumbers steps every digit run in the labels up and down by up to
+// numberIncrementRange, keeping zero padding (api01 → api00, api02, ...).
+func incrementNumbers(labels []string, add func([]string) bool) bool {
+	for i, label := range labels {
+		for _, loc := range digitsPattern.FindAllStringIndex(label, -1) {
+			digits := label[loc[0]:loc[1]]
+			n, err := strconv.Atoi(digits)
+			if err != nil {
+				continue
+			}
+			for delta := -numberIncrementRange; delta <= numberIncrementRange; delta++ {
+				if delta == 0 || n+delta < 0 {
+					continue
+				}
+				num := fmt.Sprintf("%0*d", len(digits), n+delta)
+				next := append([]string{}, labels...)
+				next[i] = label[:loc[0]] + num + label[loc[1]:]
+				if !add(next) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// swapDashDot turns dashes into label separators and label separators into
+// dashes, one at a time and all at once.
+func swapDashDot(labels []string, add func([]string) bool) bool {
+	if len(labels) == 0 {
+		return true
+	}
+	joined := strings.Join(labels, ".")
+
+	var variants []string
+	for i, c := range joined {
+		switch c {
+		case '-':
+			variants = append(variants, joined[:i]+"."+joined[i+1:])
+		case '.':
+			variants = append(variants, joined[:i]+"-"+joined[i+1:])
+		}
+	}
+	if strings.Contains(joined, "-") {
+		variants = append(variants, strings.ReplaceAll(joined, "-", "."))
+	}
+	if strings.Contains(joined, ".") {
+		variants = append(variants, strings.ReplaceAll(joined, ".", "-"))
+	}
+
+	for _, v := range variants {
+		if !add(strings.Split(v, ".")) {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	RegisterEnumerator(&PermutationEnumerator{})
+}
@@ -0,0 +1,48 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/plugin"
+)
+
+// PluginEnumerator runs an out-of-process plugin that declared the
+// enumerator capability.
+type PluginEnumerator struct {
+	plugin *plugin.Plugin
+}
+
+// RegisterPlugin registers p as an enumerator.
+func RegisterPlugin(p *plugin.Plugin) {
+	RegisterEnumerator(&PluginEnumerator{plugin: p})
+}
+
+func (e *PluginEnumerator) Name() string {
+	return e.plugin.Name
+}
+
+func (e *PluginEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, e, domain, cfg)
+}
+
+// EnumerateStream emits subdomains as the plugin streams them and forwards
+// its progress reports.
+func (e *PluginEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
+	req := plugin.Message{Type: plugin.TypeEnumerate, Domain: domain, Options: plugin.OptionsFrom(cfg)}
+	err := e.plugin.Call(ctx, req, func(msg plugin.Message) {
+		switch msg.Type {
+		case plugin.TypeSubdomain:
+			if name := normalizeSourceName(msg.Subdomain, domain, false); name != "" {
+				emit(name)
+			}
+		case plugin.TypeProgress:
+			reportProgress(ctx, msg.Status, msg.Found)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s plugin failed: %v", e.plugin.Name, err)
+	}
+	return nil
+}
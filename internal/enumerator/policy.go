@@ -0,0 +1,245 @@
+package enumerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// Fallbacks for policies that set no failure threshold or cooldown.
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 300 * time.Second
+)
+
+// errCircuitOpen is returned for calls to a source whose circuit is open.
+var errCircuitOpen = errors.New("circuit open after repeated failures")
+
+// sourcePolicy is the state one source shares across every domain of a
+// run: its request pacing, its daily budget and its circuit breaker.
+type sourcePolicy struct {
+	limits   config.SourcePolicy
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	next      time.Time // earliest start of the next request
+	day       time.Time // UTC day the budget counts
+	used      int       // requests made that day
+	failures  int       // consecutive failed calls
+	openUntil time.Time
+	probing   bool // a half-open test call is running
+}
+
+// policySet holds the policy of every source used in a run.
+type policySet struct {
+	cfg      map[string]config.SourcePolicy
+	mu       sync.Mutex
+	policies map[string]*sourcePolicy
+}
+
+func newPolicySet(cfg *config.Config) *policySet {
+	return &policySet{cfg: cfg.SourcePolicies, policies: make(map[string]*sourcePolicy)}
+}
+
+// get returns the policy of source, creating it from the configuration on
+// first use.
+func (s *policySet) get(source string) *sourcePolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.policies[source]; ok {
+		return p
+	}
+	limits, ok := s.cfg[source]
+	if !ok {
+		limits = s.cfg["default"]
+	}
+	if limits.FailureThreshold <= 0 {
+		limits.FailureThreshold = defaultFailureThreshold
+		if n := s.cfg["default"].FailureThreshold; n > 0 {
+			limits.FailureThreshold = n
+		}
+	}
+	cooldown := defaultCooldown
+	if limits.Cooldown > 0 {
+		cooldown = time.Duration(limits.Cooldown) * time.Second
+	} else if d := s.cfg["default"].Cooldown; d > 0 {
+		cooldown = time.Duration(d) * time.Second
+	}
+	p := &sourcePolicy{limits: limits, cooldown: cooldown}
+	s.policies[source] = p
+	return p
+}
+
+type policySetKey struct{}
+type policyCallKey struct{}
+
+// withPolicies returns a copy of ctx carrying the run's policies.
+func withPolicies(ctx context.Context, s *policySet) context.Context {
+	return context.WithValue(ctx, policySetKey{}, s)
+}
+
+// policyFor returns the run's policy for source, or nil outside a run.
+func policyFor(ctx context.Context, source string) *sourcePolicy {
+	if s, ok := ctx.Value(policySetKey{}).(*policySet); ok {
+		return s.get(source)
+	}
+	return nil
+}
+
+// policyCall is one attempt of a source under its policy. doRequest marks
+// it stopped when retrying the attempt cannot help: the budget is used up
+// or the source still answered 429 after DoWithRetry's own retries.
+type policyCall struct {
+	policy     *sourcePolicy
+	stopped    atomic.Bool
+	overBudget atomic.Bool // not the source's fault, so not a failure
+}
+
+// doRequest sends req with utils.DoWithRetry once the policy of the source
+// running in the request's context allows another request. Waiting for the
+// requests-per-minute limit reports the source as "throttled".
+func doRequest(client *http.Client, req *http.Request, retries, timeout int) (*http.Response, error) {
+	c, ok := req.Context().Value(policyCallKey{}).(*policyCall)
+	if !ok {
+		return utils.DoWithRetry(client, req, retries, timeout)
+	}
+	if err := c.policy.acquire(req.Context()); err != nil {
+		if req.Context().Err() == nil {
+			c.stopped.Store(true)
+			c.overBudget.Store(true)
+		}
+		return nil, err
+	}
+	resp, err := utils.DoWithRetry(client, req, retries, timeout)
+	var rateLimited *utils.RateLimitError
+	if errors.As(err, &rateLimited) {
+		c.stopped.Store(true)
+	}
+	return resp, err
+}
+
+// acquire takes one request from the daily budget and waits for the
+// source's next request slot.
+func (p *sourcePolicy) acquire(ctx context.Context) error {
+	p.mu.Lock()
+	now := time.Now()
+	if p.limits.DailyBudget > 0 {
+		day := now.UTC().Truncate(24 * time.Hour)
+		if !day.Equal(p.day) {
+			p.day, p.used = day, 0
+		}
+		if p.used >= p.limits.DailyBudget {
+			p.mu.Unlock()
+			return fmt.Errorf("daily budget of %d requests used", p.limits.DailyBudget)
+		}
+		p.used++
+	}
+	var wait time.Duration
+	if p.limits.RequestsPerMinute > 0 {
+		start := now
+		if p.next.After(now) {
+			start = p.next
+		}
+		p.next = start.Add(time.Minute / time.Duration(p.limits.RequestsPerMinute))
+		wait = start.Sub(now)
+	}
+	p.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	reportProgress(ctx, "throttled", 0)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		reportProgress(ctx, "running", 0)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// allow reports whether the source may be called: its circuit is closed,
+// or its cooldown has passed and no other call is already testing it. The
+// test call must be followed by record or release.
+func (p *sourcePolicy) allow() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Now().Before(p.openUntil) {
+		return false
+	}
+	if p.failures < p.limits.FailureThreshold {
+		return true
+	}
+	if p.probing {
+		return false
+	}
+	p.probing = true
+	return true
+}
+
+// open reports whether the circuit is open.
+func (p *sourcePolicy) open() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return time.Now().Before(p.openUntil)
+}
+
+// release ends a call without counting its outcome.
+func (p *sourcePolicy) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probing = false
+}
+
+// record counts the outcome of one call; enough consecutive failures open
+// the circuit for the cooldown. A call that was cancelled or ran out of
+// time is no fault of the source and is not counted.
+func (p *sourcePolicy) record(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probing = false
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	if err == nil {
+		p.failures = 0
+		return
+	}
+	p.failures++
+	if p.failures >= p.limits.FailureThreshold {
+		p.openUntil = time.Now().Add(p.cooldown)
+	}
+}
+
+// call runs one attempt of enumerate under p, with the attempt in the
+// context its requests are sent with. When the circuit is open, or the
+// attempt failed in a way that retrying the whole call cannot fix, the
+// error stops utils.Retry. A nil policy runs enumerate as is.
+func (p *sourcePolicy) call(ctx context.Context, enumerate func(ctx context.Context) ([]string, error)) ([]string, error) {
+	if p == nil {
+		return enumerate(ctx)
+	}
+	if !p.allow() {
+		return nil, utils.StopRetry(errCircuitOpen)
+	}
+	c := &policyCall{policy: p}
+	subdomains, err := enumerate(context.WithValue(ctx, policyCallKey{}, c))
+	if c.overBudget.Load() {
+		p.release()
+	} else {
+		p.record(err)
+	}
+	if err != nil && (c.stopped.Load() || p.open()) {
+		return subdomains, utils.StopRetry(err)
+	}
+	return subdomains, err
+}
@@ -0,0 +1,142 @@
+package enumerator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+func TestPolicyCircuitBreaker(t *testing.T) {
+	policies := newPolicySet(&config.Config{SourcePolicies: map[string]config.SourcePolicy{
+		"default": {FailureThreshold: 2},
+	}})
+	p := policies.get("dead")
+	if policies.get("dead") != p {
+		t.Fatal("Expected one policy per source")
+	}
+
+	calls := 0
+	failing := func(context.Context) ([]string, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	}
+	_, err := utils.Retry(func() ([]string, error) {
+		return p.call(context.Background(), failing)
+	}, 5, 0)
+	if calls != 2 || err == nil || errors.Is(err, errCircuitOpen) {
+		t.Errorf("Expected retries to stop once the circuit opened, got %d calls and %v", calls, err)
+	}
+
+	// Later domains do not call the source at all
+	_, err = utils.Retry(func() ([]string, error) {
+		return p.call(context.Background(), failing)
+	}, 5, 0)
+	if calls != 2 || !errors.Is(err, errCircuitOpen) {
+		t.Errorf("Expected the open circuit to skip the source, got %d calls and %v", calls, err)
+	}
+
+	// After the cooldown one call may close the circuit again
+	p.openUntil = time.Now()
+	if _, err := p.call(context.Background(), func(context.Context) ([]string, error) { return nil, nil }); err != nil || !p.allow() || p.failures != 0 {
+		t.Errorf("Expected a success to close the circuit, got %v", err)
+	}
+}
+
+func TestPolicyHalfOpenProbe(t *testing.T) {
+	p := newPolicySet(&config.Config{}).get("flaky")
+	p.failures = p.limits.FailureThreshold // cooldown over, circuit half open
+
+	// While one call tests the source, others are turned away
+	probing, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := p.call(context.Background(), func(context.Context) ([]string, error) {
+			close(probing)
+			<-release
+			return nil, errors.New("still down")
+		})
+		done <- err
+	}()
+	<-probing
+	if _, err := p.call(context.Background(), func(context.Context) ([]string, error) { return nil, nil }); !errors.Is(err, errCircuitOpen) {
+		t.Errorf("Expected a second call during the probe to be refused, got %v", err)
+	}
+	close(release)
+	if err := <-done; err == nil {
+		t.Fatal("Expected the probe's error")
+	}
+
+	// A failed probe opens the circuit again
+	if p.allow() {
+		t.Error("Expected the failed probe to reopen the circuit")
+	}
+}
+
+func TestPolicyCancellationNotCounted(t *testing.T) {
+	p := newPolicySet(&config.Config{SourcePolicies: map[string]config.SourcePolicy{
+		"default": {FailureThreshold: 1},
+	}}).get("slow")
+
+	for _, err := range []error{context.Canceled, context.DeadlineExceeded} {
+		if _, got := p.call(context.Background(), func(context.Context) ([]string, error) { return nil, err }); !errors.Is(got, err) {
+			t.Errorf("Expected %v, got %v", err, got)
+		}
+	}
+	if p.failures != 0 || !p.allow() {
+		t.Errorf("Expected cancelled calls not to count as failures, got %d", p.failures)
+	}
+}
+
+func TestPolicyRateLimitStopsRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	p := newPolicySet(&config.Config{}).get("limited")
+	_, err := utils.Retry(func() ([]string, error) {
+		return p.call(context.Background(), func(ctx context.Context) ([]string, error) {
+			req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+			_, err := doRequest(server.Client(), req, 1, 1)
+			return nil, err
+		})
+	}, 3, 0)
+	if err == nil || requests != 2 {
+		t.Errorf("Expected only DoWithRetry's own retries, got %d requests and %v", requests, err)
+	}
+}
+
+func TestPolicyBudgetAndPacing(t *testing.T) {
+	p := newPolicySet(&config.Config{SourcePolicies: map[string]config.SourcePolicy{
+		"api": {DailyBudget: 2, RequestsPerMinute: 600},
+	}}).get("api")
+
+	var statuses []string
+	ctx := withProgress(context.Background(), func(status string, _ int) {
+		statuses = append(statuses, status)
+	})
+	start := time.Now()
+	for range 2 {
+		if err := p.acquire(ctx); err != nil {
+			t.Fatalf("acquire returned error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected the second request to wait for its slot, took %v", elapsed)
+	}
+	if len(statuses) == 0 || statuses[0] != "throttled" {
+		t.Errorf("Expected a throttled report, got %v", statuses)
+	}
+	if err := p.acquire(ctx); err == nil {
+		t.Error("Expected the budget to be used up")
+	}
+}
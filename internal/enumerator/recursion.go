@@ -0,0 +1,137 @@
+package enumerator
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+// maxRecursionTargets caps the sub-zones queued per recursion pass so a
+// large first pass cannot multiply the scan time unboundedly.
+const maxRecursionTargets = 50
+
+// findSubZones returns sub-zones of the apexes worth enumerating on their
+// own: zones with at least cfg.RecursionMinChildren discovered descendants,
+// or names delegated with their own NS records. Zones already enumerated and
+// wildcarded zones are skipped; the busiest zones come first.
+func findSubZones(ctx context.Context, cfg *config.Config, apexes []string, discovered []string, enumerated map[string]bool) []string {
+	minChildren := cfg.RecursionMinChildren
+	if minChildren <= 0 {
+		minChildren = 3
+	}
+
+	children := make(map[string]int)
+	var direct []string // hosts one label below an enumerated zone
+	for _, host := range discovered {
+		apex := apexFor(host, apexes)
+		if apex == "" || host == apex {
+			continue
+		}
+		zones := enclosingZones(host, apex)
+		for _, zone := range zones {
+			if zone != apex {
+				children[zone]++
+			}
+		}
+		if enumerated[zones[0]] && !enumerated[host] {
+			direct = append(direct, host)
+		}
+	}
+
+	qualified := make(map[string]bool)
+	var nsCandidates []string
+	for zone, n := range children {
+		if enumerated[zone] {
+			continue
+		}
+		if n >= minChildren {
+			qualified[zone] = true
+		} else {
+			nsCandidates = append(nsCandidates, zone)
+		}
+	}
+	for _, host := range direct {
+		if !qualified[host] && children[host] == 0 {
+			nsCandidates = append(nsCandidates, host)
+		}
+	}
+
+	for _, zone := range delegatedZones(ctx, cfg, nsCandidates) {
+		qualified[zone] = true
+	}
+
+	// Enumerating a wildcarded zone only yields wildcard noise. Each zone is
+	// probed on its own: a wildcard at the apex does not cover names below an
+	// existing sub-zone, so it says nothing about them.
+	detector := NewWildcardDetector(resolver.FromContext(ctx))
+	var zones []string
+	for zone := range qualified {
+		if detector.probeZone(ctx, zone) == nil {
+			zones = append(zones, zone)
+		}
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		if children[zones[i]] != children[zones[j]] {
+			return children[zones[i]] > children[zones[j]]
+		}
+		return zones[i] < zones[j]
+	})
+	if len(zones) > maxRecursionTargets {
+		zones = zones[:maxRecursionTargets]
+	}
+	return zones
+}
+
+// delegatedZones returns the names that have NS records of their own.
+func delegatedZones(ctx context.Context, cfg *config.Config, names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	threads := cfg.DNSThreads
+	if threads <= 0 {
+		threads = 50
+	}
+	pool := utils.NewWorkerPool(threads, cfg.DNSRateLimit)
+	defer pool.Stop()
+
+	dnsResolver := resolver.FromContext(ctx)
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		delegated []string
+	)
+	for _, name := range names {
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+
+			lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+			defer cancel()
+
+			resp, err := dnsResolver.Query(lookupCtx, name, dns.TypeNS)
+			if err != nil || resp.Rcode != dns.RcodeSuccess {
+				return
+			}
+			for _, rr := range resp.Answer {
+				if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, dns.Fqdn(name)) {
+					mu.Lock()
+					delegated = append(delegated, name)
+					mu.Unlock()
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	return delegated
+}
@@ -0,0 +1,85 @@
+package enumerator
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+)
+
+// recursionZone has a wildcard at the apex, a busy sub-zone, a wildcarded
+// sub-zone and two delegated names.
+var recursionZone = testZone{
+	"example.com.":           {"example.com. 60 IN A 192.0.2.1"},
+	"*.example.com.":         {"*.example.com. 60 IN A 198.51.100.1"},
+	"a.dev.example.com.":     {"a.dev.example.com. 60 IN A 192.0.2.10"},
+	"b.dev.example.com.":     {"b.dev.example.com. 60 IN A 192.0.2.11"},
+	"c.dev.example.com.":     {"c.dev.example.com. 60 IN A 192.0.2.12"},
+	"*.stage.example.com.":   {"*.stage.example.com. 60 IN A 198.51.100.2"},
+	"a.stage.example.com.":   {"a.stage.example.com. 60 IN A 192.0.2.20"},
+	"corp.example.com.":      {"corp.example.com. 60 IN NS ns1.corp.example.com."},
+	"ns1.corp.example.com.":  {"ns1.corp.example.com. 60 IN A 192.0.2.30"},
+	"shop.example.com.":      {"shop.example.com. 60 IN NS ns.shop-host.example.net."},
+	"x.one.example.com.":     {"x.one.example.com. 60 IN A 192.0.2.40"},
+	"a.done.example.com.":    {"a.done.example.com. 60 IN A 192.0.2.50"},
+	"b.done.example.com.":    {"b.done.example.com. 60 IN A 192.0.2.51"},
+	"c.done.example.com.":    {"c.done.example.com. 60 IN A 192.0.2.52"},
+	"mail.example.com.":      {"mail.example.com. 60 IN A 192.0.2.60"},
+	"www.plain.example.org.": {"www.plain.example.org. 60 IN A 192.0.2.70"},
+}
+
+func TestDelegatedZones(t *testing.T) {
+	ctx := testResolverContext(t, recursionZone)
+
+	names := []string{"corp.example.com", "shop.example.com", "one.example.com", "mail.example.com", "missing.example.com"}
+	got := delegatedZones(ctx, &config.Config{DNSThreads: 2}, names)
+	sort.Strings(got)
+
+	if len(got) != 2 || got[0] != "corp.example.com" || got[1] != "shop.example.com" {
+		t.Errorf("delegatedZones() = %v, want [corp.example.com shop.example.com]", got)
+	}
+}
+
+func TestFindSubZones(t *testing.T) {
+	ctx := testResolverContext(t, recursionZone)
+
+	discovered := []string{
+		"a.dev.example.com", "b.dev.example.com", "c.dev.example.com",
+		"a.stage.example.com", "b.stage.example.com", "c.stage.example.com",
+		"ns1.corp.example.com",
+		"shop.example.com",
+		"x.one.example.com",
+		"a.done.example.com", "b.done.example.com", "c.done.example.com",
+		"mail.example.com",
+		"www.plain.example.org",
+	}
+	enumerated := map[string]bool{"example.com": true, "done.example.com": true}
+
+	got := findSubZones(ctx, &config.Config{DNSThreads: 2}, []string{"example.com", "example.org"}, discovered, enumerated)
+
+	// dev qualifies on its children even though the apex has a wildcard,
+	// stage is wildcarded itself, corp and shop are delegated, one has too
+	// few children and done was already enumerated.
+	want := []string{"dev.example.com", "corp.example.com", "shop.example.com"}
+	if len(got) != len(want) {
+		t.Fatalf("findSubZones() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("findSubZones() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestFindSubZonesMinChildren(t *testing.T) {
+	ctx := testResolverContext(t, recursionZone)
+
+	discovered := []string{"a.dev.example.com", "b.dev.example.com", "x.one.example.com"}
+	got := findSubZones(ctx, &config.Config{RecursionMinChildren: 1}, []string{"example.com"}, discovered, map[string]bool{"example.com": true})
+	sort.Strings(got)
+
+	if len(got) != 2 || got[0] != "dev.example.com" || got[1] != "one.example.com" {
+		t.Errorf("findSubZones() = %v, want [dev.example.com one.example.com]", got)
+	}
+}
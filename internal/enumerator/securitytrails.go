@@ -6,16 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
-	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
 )
 
 type SecurityTrailsEnumerator struct {
-	apiKey string
 	client *http.Client
 }
 
@@ -31,26 +28,19 @@ func (s *SecurityTrailsEnumerator) Name() string {
 }
 
 func (s *SecurityTrailsEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
-	// Check if API key is configured
-	if s.apiKey == "" {
-		return nil, fmt.Errorf("securitytrails API key not configured")
-	}
-
 	// Build API URL
 	url := fmt.Sprintf("https://api.securitytrails.com/v1/domain/%s/subdomains", domain)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	// Add headers
-	req.Header.Set("APIKEY", s.apiKey)
-	req.Header.Set("Accept", "application/json")
-
-	// Make request with 429 retry handling
-	resp, err := utils.DoWithRetry(s.client, req, cfg.Retries, cfg.Timeout)
+	// Make request, rotating API keys on 429 and quota errors
+	resp, err := doWithKeys(s.client, cfg, "securitytrails", true, func(key *credentials.Key) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("APIKEY", key.Key)
+		req.Header.Set("Accept", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("securitytrails API request failed: %v", err)
 	}
@@ -81,24 +71,12 @@ func (s *SecurityTrailsEnumerator) Enumerate(ctx context.Context, domain string,
 }
 
 func init() {
-	// Create SecurityTrails enumerator with default settings
-	// API key should be configured via environment variable or config file
+	// Create SecurityTrails enumerator with default settings; API keys come
+	// from the credentials file or SECURITYTRAILS_API_KEY
 	enumerator := &SecurityTrailsEnumerator{
-		apiKey: getSecurityTrailsAPIKey(),
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 	RegisterEnumerator(enumerator)
 }
-
-// getSecurityTrailsAPIKey retrieves the API key from environment variable
-func getSecurityTrailsAPIKey() string {
-	// Try to get from environment variable
-	apiKey := strings.TrimSpace(os.Getenv("SECURITYTRAILS_API_KEY"))
-	if apiKey == "" {
-		// Could also try to get from config file in the future
-		return ""
-	}
-	return apiKey
-}
@@ -0,0 +1,509 @@
+package enumerator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// maxSourcePages bounds pagination for definitions that set no max_pages.
+const maxSourcePages = 10
+
+// SourceDefinition describes a passive HTTP API source in YAML, so sources
+// can be added without recompiling. The URL may use {domain}, {page} and
+// {cursor} placeholders.
+type SourceDefinition struct {
+	Name              string            `yaml:"name"`
+	Description       string            `yaml:"description"`
+	Type              string            `yaml:"type"` // provenance record type, default passive-dns
+	URL               string            `yaml:"url"`
+	Method            string            `yaml:"method"`
+	Headers           map[string]string `yaml:"headers"`
+	Body              string            `yaml:"body"` // request body template for POST sources
+	Auth              SourceAuth        `yaml:"auth"`
+	Pagination        SourcePagination  `yaml:"pagination"`
+	Extract           SourceExtract     `yaml:"extract"`
+	RequestsPerMinute int               `yaml:"requests_per_minute"`
+}
+
+// SourceAuth says how a source's API key is sent. Type is one of none,
+// header (key in the header Name), query (key in the query parameter Name),
+// bearer or basic (Env holds the user, SecretEnv the password).
+type SourceAuth struct {
+	Type      string `yaml:"type"`
+	Name      string `yaml:"name"`
+	Env       string `yaml:"env"`
+	SecretEnv string `yaml:"secret_env"`
+	Required  bool   `yaml:"required"` // the source is unavailable without a key
+}
+
+// SourcePagination walks result pages. With page_start set, {page} counts
+// up from it by page_step; with cursor_path set, {cursor} takes the value
+// found there in each response. Paging stops at max_pages, on an empty
+// cursor or when a page adds no new names.
+type SourcePagination struct {
+	PageStart  *int   `yaml:"page_start"`
+	PageStep   int    `yaml:"page_step"`
+	CursorPath string `yaml:"cursor_path"`
+	MaxPages   int    `yaml:"max_pages"`
+}
+
+// SourceExtract pulls host names out of a response, either from a JSON
+// path such as "data.records[].hostname" or with a regex applied to the raw
+// body (its first group if it has one). Values may hold several names
+// separated by whitespace. With append_domain set, values are labels
+// relative to the queried domain.
+type SourceExtract struct {
+	JSONPath     string `yaml:"json_path"`
+	Regex        string `yaml:"regex"`
+	AppendDomain bool   `yaml:"append_domain"`
+}
+
+// SourceEnumerator queries the API described by a SourceDefinition.
+type SourceEnumerator struct {
+	def    SourceDefinition
+	regex  *regexp.Regexp
+	client *http.Client
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+// DefaultSourcesDir returns the directory of source definitions used when
+// none is given: "subdomainx/sources" in the user config directory, or ""
+// when there is none.
+func DefaultSourcesDir() string {
+	return userConfigDir("sources")
+}
+
+// DefaultToolsDir returns the directory of tool definitions used when none
+// is given: "subdomainx/tools" in the user config directory, or "" when
+// there is none.
+func DefaultToolsDir() string {
+	return userConfigDir("tools")
+}
+
+// userConfigDir returns subdomainx/name in the user config directory. The
+// defaults never point at the working directory, where a checkout being
+// scanned could plant definitions that run its own binaries.
+func userConfigDir(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "subdomainx", name)
+}
+
+// LoadSources reads every *.yaml and *.yml definition in dir and registers
+// an enumerator and a --check-tools entry for each. A missing directory is
+// not an error. It returns the names of the loaded sources.
+func LoadSources(dir string) ([]string, error) {
+	files, err := definitionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		var def SourceDefinition
+		if err := readDefinition(file, &def); err != nil {
+			return nil, fmt.Errorf("failed to load source %s: %v", file, err)
+		}
+		s, err := NewSourceEnumerator(def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source %s: %v", file, err)
+		}
+		if isKnownTool(def.Name) {
+			return nil, fmt.Errorf("invalid source %s: %q is already a tool", file, def.Name)
+		}
+
+		install := "Built-in (declarative source " + file + ")"
+		if s.def.Auth.Env != "" {
+			install = "Add keys to the credentials file or set " + s.def.Auth.Env
+			if !s.def.Auth.Required {
+				install += " (optional)"
+			}
+		}
+		RegisterEnumerator(s)
+		types.RegisterSourceType(def.Name, s.def.Type)
+		utils.RegisterTool(utils.Tool{
+			Name:        def.Name,
+			Command:     def.Name,
+			Description: s.description(),
+			InstallCmd:  map[string]string{"linux": install, "darwin": install, "windows": install},
+		}, s.available)
+		names = append(names, def.Name)
+	}
+	return names, nil
+}
+
+// definitionFiles returns the YAML files in dir, sorted. A missing
+// directory, or none at all, has none.
+func definitionFiles(dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil // never the working directory
+	}
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readDefinition decodes the YAML file into def, rejecting unknown keys so
+// typos in a definition are not silently ignored.
+func readDefinition(file string, def any) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return yaml.UnmarshalStrict(data, def)
+}
+
+// isKnownTool reports whether name is taken by an enumerator or any tool
+// --check-tools knows about.
+func isKnownTool(name string) bool {
+	if _, exists := enumerators[name]; exists {
+		return true
+	}
+	for _, tool := range utils.GetRequiredTools() {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSourceEnumerator validates def and fills in its defaults.
+func NewSourceEnumerator(def SourceDefinition) (*SourceEnumerator, error) {
+	if def.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if def.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if (def.Extract.JSONPath == "") == (def.Extract.Regex == "") {
+		return nil, fmt.Errorf("extract needs exactly one of json_path and regex")
+	}
+	if def.Pagination.CursorPath != "" && def.Extract.Regex != "" {
+		return nil, fmt.Errorf("cursor_path needs json_path extraction")
+	}
+	switch def.Auth.Type {
+	case "":
+		def.Auth.Type = "none"
+	case "none", "bearer":
+	case "header", "query":
+		if def.Auth.Name == "" {
+			return nil, fmt.Errorf("%s auth needs a name", def.Auth.Type)
+		}
+	case "basic":
+		if def.Auth.SecretEnv == "" {
+			return nil, fmt.Errorf("basic auth needs secret_env")
+		}
+	default:
+		return nil, fmt.Errorf("unknown auth type %q", def.Auth.Type)
+	}
+	if def.Auth.Type != "none" && def.Auth.Env == "" {
+		return nil, fmt.Errorf("%s auth needs env", def.Auth.Type)
+	}
+	if def.Method == "" {
+		def.Method = http.MethodGet
+	}
+	if def.Type == "" {
+		def.Type = types.SourcePassiveDNS
+	}
+	if def.Pagination.PageStep == 0 {
+		def.Pagination.PageStep = 1
+	}
+	if def.Pagination.MaxPages <= 0 {
+		def.Pagination.MaxPages = maxSourcePages
+	}
+
+	if def.Auth.Env != "" {
+		credentials.RegisterProvider(def.Name, def.Auth.Env, def.Auth.SecretEnv)
+	}
+	s := &SourceEnumerator{def: def, client: &http.Client{Timeout: 30 * time.Second}}
+	if def.Extract.Regex != "" {
+		re, err := regexp.Compile(def.Extract.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %v", err)
+		}
+		s.regex = re
+	}
+	return s, nil
+}
+
+func (s *SourceEnumerator) Name() string {
+	return s.def.Name
+}
+
+func (s *SourceEnumerator) description() string {
+	if s.def.Description != "" {
+		return s.def.Description
+	}
+	return "Declarative API source " + s.def.Name
+}
+
+// available reports whether the source can run: sources whose key is
+// required need one in the credentials file or the environment.
+func (s *SourceEnumerator) available() bool {
+	return !s.def.Auth.Required || credentials.Has(s.def.Name)
+}
+
+// Enumerate requests every page of the source for domain and returns the
+// names below it.
+func (s *SourceEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	found := make(map[string]bool)
+	page := 0
+	if s.def.Pagination.PageStart != nil {
+		page = *s.def.Pagination.PageStart
+	}
+	cursor := ""
+
+	for i := 0; i < s.def.Pagination.MaxPages; i++ {
+		body, err := s.fetch(ctx, domain, strconv.Itoa(page), cursor, cfg)
+		if err != nil {
+			if i > 0 {
+				break // keep what the earlier pages returned
+			}
+			return nil, err
+		}
+
+		values, next, err := s.extract(body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", s.def.Name, err)
+		}
+		added := 0
+		for _, v := range values {
+			for _, name := range strings.Fields(v) {
+				name = normalizeSourceName(name, domain, s.def.Extract.AppendDomain)
+				if name != "" && !found[name] {
+					found[name] = true
+					added++
+				}
+			}
+		}
+
+		switch {
+		case s.def.Pagination.CursorPath != "":
+			if next == "" || next == cursor {
+				i = s.def.Pagination.MaxPages
+			}
+			cursor = next
+		case s.def.Pagination.PageStart != nil && added > 0:
+			page += s.def.Pagination.PageStep
+		default:
+			i = s.def.Pagination.MaxPages // not paginated, or an empty page
+		}
+	}
+
+	subdomains := make([]string, 0, len(found))
+	for name := range found {
+		subdomains = append(subdomains, name)
+	}
+	sort.Strings(subdomains)
+	return subdomains, nil
+}
+
+// fetch sends one request, waiting for the source's rate limit first, and
+// returns the body of a 200 response.
+func (s *SourceEnumerator) fetch(ctx context.Context, domain, page, cursor string, cfg *config.Config) ([]byte, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	replacer := strings.NewReplacer("{domain}", url.QueryEscape(domain), "{page}", page, "{cursor}", url.QueryEscape(cursor))
+	body := ""
+	if s.def.Body != "" {
+		body = strings.NewReplacer("{domain}", domain, "{page}", page, "{cursor}", cursor).Replace(s.def.Body)
+	}
+
+	// Make request, rotating API keys on 429 and quota errors
+	resp, err := doWithKeys(s.client, cfg, s.def.Name, s.def.Auth.Required, func(key *credentials.Key) (*http.Request, error) {
+		var reqBody io.Reader
+		if body != "" {
+			reqBody = strings.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, s.def.Method, replacer.Replace(s.def.URL), reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("User-Agent", "SubdomainX/1.0")
+		req.Header.Set("Accept", "application/json")
+		for k, v := range s.def.Headers {
+			req.Header.Set(k, v)
+		}
+		s.authorize(req, key)
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s API request failed: %v", s.def.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %v", s.def.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s API error: %s - %s", s.def.Name, resp.Status, string(respBody))
+	}
+	return respBody, nil
+}
+
+// authorize adds key to req as the definition says; a nil key (optional
+// auth with none configured) leaves req unauthenticated.
+func (s *SourceEnumerator) authorize(req *http.Request, key *credentials.Key) {
+	if key == nil {
+		return
+	}
+	switch s.def.Auth.Type {
+	case "header":
+		req.Header.Set(s.def.Auth.Name, key.Key)
+	case "query":
+		q := req.URL.Query()
+		q.Set(s.def.Auth.Name, key.Key)
+		req.URL.RawQuery = q.Encode()
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+key.Key)
+	case "basic":
+		req.SetBasicAuth(key.Key, key.Secret)
+	}
+}
+
+// wait blocks until the source's requests_per_minute allows another request.
+func (s *SourceEnumerator) wait(ctx context.Context) error {
+	if s.def.RequestsPerMinute <= 0 {
+		return nil
+	}
+	interval := time.Minute / time.Duration(s.def.RequestsPerMinute)
+
+	s.mu.Lock()
+	next := s.lastRequest.Add(interval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	s.lastRequest = next
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(next)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// extract returns the raw values the definition points at and, for cursor
+// pagination, the next cursor.
+func (s *SourceEnumerator) extract(body []byte) ([]string, string, error) {
+	if s.regex != nil {
+		var values []string
+		for _, m := range s.regex.FindAllSubmatch(body, -1) {
+			if len(m) > 1 {
+				values = append(values, string(m[1]))
+			} else {
+				values = append(values, string(m[0]))
+			}
+		}
+		return values, "", nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, "", fmt.Errorf("failed to parse response: %v", err)
+	}
+	values := jsonPathValues(doc, s.def.Extract.JSONPath)
+	next := ""
+	if s.def.Pagination.CursorPath != "" {
+		if cursors := jsonPathValues(doc, s.def.Pagination.CursorPath); len(cursors) > 0 {
+			next = cursors[0]
+		}
+	}
+	return values, next, nil
+}
+
+// jsonPathValues follows a dotted path through decoded JSON and returns the
+// scalar values at its end. A "[]" suffix on a segment iterates an array,
+// e.g. "passive_dns[].hostname", and "[]" alone iterates a top-level array.
+func jsonPathValues(doc any, path string) []string {
+	nodes := []any{doc}
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			iterate := strings.HasSuffix(segment, "[]")
+			key := strings.TrimSuffix(segment, "[]")
+
+			var next []any
+			for _, n := range nodes {
+				if key != "" {
+					m, ok := n.(map[string]any)
+					if !ok {
+						continue
+					}
+					n = m[key]
+				}
+				if !iterate {
+					next = append(next, n)
+					continue
+				}
+				if list, ok := n.([]any); ok {
+					next = append(next, list...)
+				}
+			}
+			nodes = next
+		}
+	}
+
+	var values []string
+	for _, n := range nodes {
+		switch v := n.(type) {
+		case string:
+			values = append(values, v)
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// normalizeSourceName lower-cases name, strips wildcard labels and returns
+// it if it is domain or lies below it, or "" otherwise.
+func normalizeSourceName(name, domain string, appendDomain bool) string {
+	name = strings.TrimPrefix(strings.ToLower(strings.Trim(strings.TrimSpace(name), ".")), "*.")
+	if appendDomain && name != "" {
+		name += "." + domain
+	}
+	if name != domain && !strings.HasSuffix(name, "."+domain) {
+		return ""
+	}
+	return name
+}
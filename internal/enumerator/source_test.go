@@ -0,0 +1,167 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+func TestSourceEnumeratorJSONPagination(t *testing.T) {
+	t.Setenv("TEST_SOURCE_KEY", "secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `{"data":{"records":[{"host":"www.example.com"},{"host":"*.API.example.com"},{"host":"other.org"}]}}`)
+		case "2":
+			fmt.Fprint(w, `{"data":{"records":[{"host":"mail.example.com"}]}}`)
+		default:
+			fmt.Fprint(w, `{"data":{"records":[]}}`)
+		}
+	}))
+	defer server.Close()
+
+	start := 1
+	s, err := NewSourceEnumerator(SourceDefinition{
+		Name:       "testjson",
+		URL:        server.URL + "/v1/{domain}?page={page}",
+		Auth:       SourceAuth{Type: "header", Name: "X-Key", Env: "TEST_SOURCE_KEY", Required: true},
+		Pagination: SourcePagination{PageStart: &start},
+		Extract:    SourceExtract{JSONPath: "data.records[].host"},
+	})
+	if err != nil {
+		t.Fatalf("NewSourceEnumerator returned error: %v", err)
+	}
+
+	subdomains, err := s.Enumerate(context.Background(), "example.com", &config.Config{Timeout: 5})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	want := []string{"api.example.com", "mail.example.com", "www.example.com"}
+	if !slices.Equal(subdomains, want) {
+		t.Errorf("Expected %v, got %v", want, subdomains)
+	}
+}
+
+func TestSourceEnumeratorRegex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<table><tr><td>dev.example.com</td><td>A</td></tr><tr><td>vpn.example.com</td></tr></table>`)
+	}))
+	defer server.Close()
+
+	s, err := NewSourceEnumerator(SourceDefinition{
+		Name:    "testregex",
+		URL:     server.URL + "/subdomain/{domain}",
+		Extract: SourceExtract{Regex: `<td>([a-z0-9.-]+)</td>`},
+	})
+	if err != nil {
+		t.Fatalf("NewSourceEnumerator returned error: %v", err)
+	}
+
+	subdomains, err := s.Enumerate(context.Background(), "example.com", &config.Config{Timeout: 5})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	if want := []string{"dev.example.com", "vpn.example.com"}; !slices.Equal(subdomains, want) {
+		t.Errorf("Expected %v, got %v", want, subdomains)
+	}
+}
+
+func TestJSONPathValues(t *testing.T) {
+	doc := map[string]any{
+		"subdomains": []any{"a", "b"},
+		"items":      []any{map[string]any{"name": "c"}, map[string]any{"name": "d"}, "skip"},
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"subdomains", []string{"a", "b"}},
+		{"subdomains[]", []string{"a", "b"}},
+		{"items[].name", []string{"c", "d"}},
+		{"missing.name", nil},
+	}
+	for _, tt := range tests {
+		if got := jsonPathValues(doc, tt.path); !slices.Equal(got, tt.want) {
+			t.Errorf("jsonPathValues(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if got := jsonPathValues([]any{"x", "y"}, "[]"); !slices.Equal(got, []string{"x", "y"}) {
+		t.Errorf("Expected a top-level array to be iterated, got %v", got)
+	}
+}
+
+func TestLoadSources(t *testing.T) {
+	dir := t.TempDir()
+	def := `name: testloaded
+description: Test source
+type: ct
+url: https://example.invalid/{domain}
+auth:
+  type: bearer
+  env: TEST_LOADED_KEY
+  required: true
+extract:
+  json_path: "[]"
+`
+	if err := os.WriteFile(filepath.Join(dir, "test.yaml"), []byte(def), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { delete(enumerators, "testloaded") })
+
+	names, err := LoadSources(dir)
+	if err != nil {
+		t.Fatalf("LoadSources returned error: %v", err)
+	}
+	if !slices.Equal(names, []string{"testloaded"}) {
+		t.Fatalf("Expected [testloaded], got %v", names)
+	}
+	if _, ok := enumerators["testloaded"]; !ok {
+		t.Error("Expected the source to be registered as an enumerator")
+	}
+	if got := types.SourceType("testloaded"); got != types.SourceCT {
+		t.Errorf("Expected source type %q, got %q", types.SourceCT, got)
+	}
+	if utils.CheckToolAvailability("testloaded") {
+		t.Error("Expected a source with a missing required key to be unavailable")
+	}
+	t.Setenv("TEST_LOADED_KEY", "k")
+	if !utils.CheckToolAvailability("testloaded") {
+		t.Error("Expected the source to be available once its key is set")
+	}
+
+	if names, err := LoadSources(filepath.Join(dir, "missing")); err != nil || len(names) != 0 {
+		t.Errorf("Expected a missing directory to load nothing, got %v, %v", names, err)
+	}
+}
+
+func TestLoadSourcesInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no extract":   "name: bad\nurl: https://example.invalid/{domain}\n",
+		"bad auth":     "name: bad\nurl: https://example.invalid/\nauth:\n  type: digest\nextract:\n  regex: x\n",
+		"bad regex":    "name: bad\nurl: https://example.invalid/\nextract:\n  regex: '('\n",
+		"regex cursor": "name: bad\nurl: https://example.invalid/\npagination:\n  cursor_path: next\nextract:\n  regex: x\n",
+		"builtin name": "name: crtsh\nurl: https://example.invalid/\nextract:\n  regex: x\n",
+	}
+	for name, def := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(def), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSources(dir); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
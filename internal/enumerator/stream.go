@@ -0,0 +1,102 @@
+package enumerator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+)
+
+// maxStreamLine bounds a single line of tool output; amass JSON lines can be
+// far longer than bufio.Scanner's default 64 KiB.
+const maxStreamLine = 1024 * 1024
+
+// StreamingEnumerator is an Enumerator that reports subdomains while it runs
+// instead of only when it exits, so long tool runs show results live. Calls
+// to emit must not overlap.
+type StreamingEnumerator interface {
+	Enumerator
+	EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(subdomain string)) error
+}
+
+// collectStream adapts a StreamingEnumerator to Enumerate by gathering
+// everything it emits.
+func collectStream(ctx context.Context, e StreamingEnumerator, domain string, cfg *config.Config) ([]string, error) {
+	var subdomains []string
+	if err := e.EnumerateStream(ctx, domain, cfg, func(subdomain string) {
+		subdomains = append(subdomains, subdomain)
+	}); err != nil {
+		return nil, err
+	}
+	return subdomains, nil
+}
+
+// enumerateLive runs e against domain and passes every subdomain to emit as
+// soon as it is known: immediately for streaming enumerators, on completion
+// for the rest. Subdomains emitted before a failure are still returned.
+func enumerateLive(ctx context.Context, e Enumerator, domain string, cfg *config.Config, emit func(subdomain string)) ([]string, error) {
+	s, ok := e.(StreamingEnumerator)
+	if !ok {
+		subdomains, err := e.Enumerate(ctx, domain, cfg)
+		for _, subdomain := range subdomains {
+			emit(subdomain)
+		}
+		return subdomains, err
+	}
+
+	var subdomains []string
+	err := s.EnumerateStream(ctx, domain, cfg, func(subdomain string) {
+		subdomains = append(subdomains, subdomain)
+		emit(subdomain)
+	})
+	return subdomains, err
+}
+
+// streamCommand runs cmd and calls onLine with every trimmed, non-empty line
+// it writes to stdout as soon as the line is complete. Stderr is captured
+// into the returned *exec.ExitError like cmd.Output does. A line longer than
+// maxStreamLine ends the scan with an error; the rest of the output is
+// drained so the tool can still exit.
+func streamCommand(cmd *exec.Cmd, onLine func(line string)) error {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	if cmd.Stderr == nil {
+		cmd.Stderr = &stderr
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			onLine(line)
+		}
+	}
+	scanErr := scanner.Err()
+
+	// Unblock a tool still writing so Wait does not hang until the timeout
+	_, _ = io.Copy(io.Discard, stdout)
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	if err == nil && scanErr != nil {
+		return fmt.Errorf("failed to read %s output: %v", filepath.Base(cmd.Path), scanErr)
+	}
+	return err
+}
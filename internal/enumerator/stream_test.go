@@ -0,0 +1,48 @@
+package enumerator
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestStreamCommandLines(t *testing.T) {
+	var lines []string
+	cmd := exec.Command("sh", "-c", `printf 'a.example.com\n\n  b.example.com  \nc.example.com'`)
+	if err := streamCommand(cmd, func(line string) { lines = append(lines, line) }); err != nil {
+		t.Fatalf("streamCommand returned error: %v", err)
+	}
+	if strings.Join(lines, ",") != "a.example.com,b.example.com,c.example.com" {
+		t.Errorf("Unexpected lines: %v", lines)
+	}
+}
+
+func TestStreamCommandLongLine(t *testing.T) {
+	// A line over maxStreamLine must fail the run, not hang the tool on a
+	// full pipe or silently truncate its output.
+	cmd := exec.Command("sh", "-c", `head -c 2097152 /dev/zero | tr '\0' a; printf '\nlate.example.com\n'`)
+	var lines []string
+	err := streamCommand(cmd, func(line string) { lines = append(lines, line) })
+	if err == nil {
+		t.Fatal("Expected an error for an over-long line")
+	}
+	if len(lines) != 0 {
+		t.Errorf("Expected no lines after the failure, got %d", len(lines))
+	}
+}
+
+func TestStreamCommandExitError(t *testing.T) {
+	cmd := exec.Command("sh", "-c", `echo partial.example.com; echo boom >&2; exit 3`)
+	var lines []string
+	err := streamCommand(cmd, func(line string) { lines = append(lines, line) })
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("Expected *exec.ExitError, got %v", err)
+	}
+	if strings.TrimSpace(string(exitErr.Stderr)) != "boom" {
+		t.Errorf("Expected captured stderr 'boom', got %q", exitErr.Stderr)
+	}
+	if len(lines) != 1 || lines[0] != "partial.example.com" {
+		t.Errorf("Expected the line written before the failure, got %v", lines)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
-	"strings"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
 )
@@ -16,6 +15,11 @@ func (s *SubfinderEnumerator) Name() string {
 }
 
 func (s *SubfinderEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, s, domain, cfg)
+}
+
+// EnumerateStream emits subdomains as subfinder prints them.
+func (s *SubfinderEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
 	// Build subfinder command
 	args := []string{"-d", domain, "-silent"}
 	if cfg.Wordlist != "" {
@@ -24,24 +28,11 @@ func (s *SubfinderEnumerator) Enumerate(ctx context.Context, domain string, cfg
 
 	cmd := exec.CommandContext(ctx, "subfinder", args...)
 
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("subfinder execution failed: %v", err)
-	}
-
 	// Parse output (one subdomain per line)
-	lines := strings.Split(string(output), "\n")
-	var subdomains []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			subdomains = append(subdomains, line)
-			// DNS resolution will be done later in the main enumerator
-		}
+	if err := streamCommand(cmd, emit); err != nil {
+		return fmt.Errorf("subfinder execution failed: %v", err)
 	}
-
-	return subdomains, nil
+	return nil
 }
 
 func init() {
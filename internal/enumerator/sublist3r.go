@@ -16,29 +16,26 @@ func (s *Sublist3rEnumerator) Name() string {
 }
 
 func (s *Sublist3rEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
+	return collectStream(ctx, s, domain, cfg)
+}
+
+// EnumerateStream emits subdomains as sublist3r prints them.
+func (s *Sublist3rEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
 	// Build sublist3r command
 	args := []string{"-d", domain, "-o", "/dev/stdout"}
 
 	cmd := exec.CommandContext(ctx, "sublist3r", args...)
 
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("sublist3r execution failed: %v", err)
-	}
-
 	// Parse output (one subdomain per line)
-	lines := strings.Split(string(output), "\n")
-	var subdomains []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			subdomains = append(subdomains, line)
-			// Cache the DNS result
+	err := streamCommand(cmd, func(line string) {
+		if !strings.HasPrefix(line, "#") {
+			emit(line)
 		}
+	})
+	if err != nil {
+		return fmt.Errorf("sublist3r execution failed: %v", err)
 	}
-
-	return subdomains, nil
+	return nil
 }
 
 func init() {
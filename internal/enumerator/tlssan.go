@@ -0,0 +1,125 @@
+package enumerator
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+const (
+	// maxTLSSANTargets caps the discovered hosts whose certificates are
+	// fetched, since every host costs a handshake per port.
+	maxTLSSANTargets = 200
+	// tlsHandshakeTimeout bounds connecting to and shaking hands with a port.
+	tlsHandshakeTimeout = 5 * time.Second
+)
+
+// tlsSANPorts are the ports commonly serving TLS that every host is tried
+// on; tests point them at a local listener.
+var tlsSANPorts = []string{"443", "8443", "4443", "9443", "10443"}
+
+// TLSSANEnumerator completes a TLS handshake with the hosts found so far and
+// reports the names in their certificates' CN and SubjectAltNames. Shared
+// certificates often list sibling hosts that no passive source knows about.
+type TLSSANEnumerator struct{}
+
+func (t *TLSSANEnumerator) Name() string {
+	return "tls-san"
+}
+
+func (t *TLSSANEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return t.EnumerateSeeded(ctx, domain, nil, cfg)
+}
+
+// EnumerateSeeded fetches the certificates of domain and the subdomains the
+// earlier passes found, on every port in tlsSANPorts, and returns the
+// certificate names that belong to domain.
+func (t *TLSSANEnumerator) EnumerateSeeded(ctx context.Context, domain string, known []string, cfg *config.Config) ([]string, error) {
+	if len(known) > maxTLSSANTargets {
+		known = known[:maxTLSSANTargets]
+	}
+	targets := append([]string{domain}, known...)
+
+	threads := cfg.Threads
+	if threads <= 0 {
+		threads = 10
+	}
+	pool := utils.NewWorkerPool(threads, cfg.RateLimit)
+	defer pool.Stop()
+
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		subdomainSet = make(map[string]bool)
+	)
+	for _, target := range targets {
+		for _, port := range tlsSANPorts {
+			wg.Add(1)
+			pool.Submit(func() {
+				defer wg.Done()
+				if ctx.Err() != nil {
+					return
+				}
+
+				cert, err := fetchCertificate(ctx, target, port)
+				if err != nil {
+					return
+				}
+
+				mu.Lock()
+				for _, name := range cert.Hostnames() {
+					if strings.HasSuffix(name, "."+domain) {
+						subdomainSet[name] = true
+					}
+				}
+				mu.Unlock()
+			})
+		}
+	}
+	wg.Wait()
+
+	var subdomains []string
+	for subdomain := range subdomainSet {
+		subdomains = append(subdomains, subdomain)
+	}
+	sort.Strings(subdomains)
+	return subdomains, nil
+}
+
+// fetchCertificate completes a TLS handshake with host on port, sending host
+// as the server name, and returns the leaf certificate. The certificate is
+// not verified: expired and self-signed ones name hosts just as well.
+func fetchCertificate(ctx context.Context, host, port string) (*types.TLSCert, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, tlsHandshakeTimeout)
+	defer cancel()
+
+	raw, err := resolver.DialContext(dialCtx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+	conn := tls.Client(raw, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.HandshakeContext(dialCtx); err != nil {
+		return nil, err
+	}
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s:%s presented no certificate", host, port)
+	}
+	return types.NewTLSCert(certs[0]), nil
+}
+
+func init() {
+	RegisterEnumerator(&TLSSANEnumerator{})
+}
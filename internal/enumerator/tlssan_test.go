@@ -0,0 +1,99 @@
+package enumerator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+)
+
+// startTLSServer completes TLS handshakes on a local port with a
+// self-signed certificate for cn and sans, and points tlsSANPorts at it.
+func startTLSServer(t *testing.T, cn string, sans []string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     sans,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	old := tlsSANPorts
+	tlsSANPorts = []string{port}
+	t.Cleanup(func() { tlsSANPorts = old })
+}
+
+func TestTLSSANEnumerator(t *testing.T) {
+	startTLSServer(t, "www.example.com", []string{
+		"www.example.com", "*.api.example.com", "Admin.Example.com", "cdn.example.net",
+	})
+	ctx := testResolverContext(t, testZone{
+		"example.com.":     {"example.com. 60 IN A 127.0.0.1"},
+		"www.example.com.": {"www.example.com. 60 IN A 127.0.0.1"},
+	})
+
+	subdomains, err := (&TLSSANEnumerator{}).EnumerateSeeded(ctx, "example.com", []string{"www.example.com"}, &config.Config{})
+	if err != nil {
+		t.Fatalf("EnumerateSeeded returned error: %v", err)
+	}
+
+	want := []string{"admin.example.com", "api.example.com", "www.example.com"}
+	if !slices.Equal(subdomains, want) {
+		t.Errorf("Expected %v, got %v", want, subdomains)
+	}
+}
+
+func TestTLSSANEnumeratorNoTLS(t *testing.T) {
+	old := tlsSANPorts
+	tlsSANPorts = []string{"1"} // nothing listens here
+	t.Cleanup(func() { tlsSANPorts = old })
+	ctx := testResolverContext(t, testZone{"example.com.": {"example.com. 60 IN A 127.0.0.1"}})
+
+	subdomains, err := (&TLSSANEnumerator{}).Enumerate(ctx, "example.com", &config.Config{})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	if len(subdomains) != 0 {
+		t.Errorf("Expected nothing without a TLS listener, got %v", subdomains)
+	}
+}
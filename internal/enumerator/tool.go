@@ -0,0 +1,287 @@
+package enumerator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// ToolDefinition describes how to run an external enumeration tool, so a
+// wrapper can be declared in YAML instead of compiled in. Args may use the
+// placeholders {domain}, {wordlist} (cfg.Wordlist or the shipped list),
+// {input} (a file holding the domain) and {output} (a file the tool writes
+// its results to; without it results are read from stdout).
+type ToolDefinition struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Type        string            `yaml:"type"` // provenance record type, default aggregator
+	Binary      string            `yaml:"binary"`
+	Args        []string          `yaml:"args"`
+	Input       string            `yaml:"input"`  // none, stdin or file
+	Format      string            `yaml:"format"` // lines, jsonl or regex
+	JSONPath    string            `yaml:"json_path"`
+	Regex       string            `yaml:"regex"`
+	RequiresEnv []string          `yaml:"requires_env"` // variables the tool needs to be usable
+	Install     map[string]string `yaml:"install"`      // OS -> install command for --install-tools
+}
+
+// ToolEnumerator runs the external tool described by a ToolDefinition.
+type ToolEnumerator struct {
+	def   ToolDefinition
+	regex *regexp.Regexp
+}
+
+// LoadTools reads every *.yaml and *.yml tool definition in dir and
+// registers an enumerator and a --check-tools entry for each. A missing
+// directory is not an error. It returns the names of the loaded tools.
+func LoadTools(dir string) ([]string, error) {
+	files, err := definitionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		var def ToolDefinition
+		if err := readDefinition(file, &def); err != nil {
+			return nil, fmt.Errorf("failed to load tool %s: %v", file, err)
+		}
+		t, err := NewToolEnumerator(def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool %s: %v", file, err)
+		}
+		if isKnownTool(def.Name) {
+			return nil, fmt.Errorf("invalid tool %s: %q is already a tool", file, def.Name)
+		}
+
+		RegisterEnumerator(t)
+		types.RegisterSourceType(def.Name, t.def.Type)
+		utils.RegisterTool(utils.Tool{
+			Name:        def.Name,
+			Command:     def.Name,
+			Description: t.description(),
+			InstallCmd:  t.installHints(),
+		}, t.available)
+		names = append(names, def.Name)
+	}
+	return names, nil
+}
+
+// NewToolEnumerator validates def and fills in its defaults.
+func NewToolEnumerator(def ToolDefinition) (*ToolEnumerator, error) {
+	if def.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if def.Binary == "" {
+		return nil, fmt.Errorf("binary is required")
+	}
+	if def.Type == "" {
+		def.Type = types.SourceAggregator
+	}
+
+	switch def.Input {
+	case "", "none", "stdin":
+	case "file":
+		if !slices.ContainsFunc(def.Args, func(a string) bool { return strings.Contains(a, "{input}") }) {
+			return nil, fmt.Errorf("file input needs an {input} argument")
+		}
+	default:
+		return nil, fmt.Errorf("unknown input %q", def.Input)
+	}
+
+	t := &ToolEnumerator{def: def}
+	switch def.Format {
+	case "", "lines":
+		t.def.Format = "lines"
+	case "jsonl":
+		if def.JSONPath == "" {
+			return nil, fmt.Errorf("jsonl output needs json_path")
+		}
+	case "regex":
+		re, err := regexp.Compile(def.Regex)
+		if err != nil || def.Regex == "" {
+			return nil, fmt.Errorf("invalid regex %q: %v", def.Regex, err)
+		}
+		t.regex = re
+	default:
+		return nil, fmt.Errorf("unknown format %q", def.Format)
+	}
+	return t, nil
+}
+
+func (t *ToolEnumerator) Name() string {
+	return t.def.Name
+}
+
+func (t *ToolEnumerator) description() string {
+	if t.def.Description != "" {
+		return t.def.Description
+	}
+	return "Declarative wrapper for " + t.def.Binary
+}
+
+// installHints returns the per-OS install commands, defaulting to a manual
+// download note for the systems the definition does not cover.
+func (t *ToolEnumerator) installHints() map[string]string {
+	hints := make(map[string]string)
+	for _, goos := range []string{"linux", "darwin", "windows"} {
+		if cmd := t.def.Install[goos]; cmd != "" {
+			hints[goos] = cmd
+		} else {
+			hints[goos] = "Download " + t.def.Binary + " and put it in PATH"
+		}
+	}
+	return hints
+}
+
+// available reports whether the binary is in PATH and every variable the
+// tool needs is set.
+func (t *ToolEnumerator) available() bool {
+	if _, err := exec.LookPath(t.def.Binary); err != nil {
+		return false
+	}
+	for _, env := range t.def.RequiresEnv {
+		if strings.TrimSpace(os.Getenv(env)) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *ToolEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, t, domain, cfg)
+}
+
+// EnumerateStream runs the tool and emits the names it reports: as they are
+// printed, or once it exits when it writes to an {output} file.
+func (t *ToolEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
+	var temps []string
+	defer func() {
+		for _, path := range temps {
+			_ = os.Remove(path)
+		}
+	}()
+	tempFile := func(content string) (string, error) {
+		f, err := os.CreateTemp("", t.def.Name+"-*.txt")
+		if err != nil {
+			return "", fmt.Errorf("%s: failed to create temp file: %v", t.def.Name, err)
+		}
+		temps = append(temps, f.Name())
+		_, err = f.WriteString(content)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return f.Name(), err
+	}
+
+	placeholders := map[string]string{"{domain}": domain}
+	var outputPath string
+	for _, arg := range t.def.Args {
+		if strings.Contains(arg, "{wordlist}") && placeholders["{wordlist}"] == "" {
+			path := cfg.Wordlist
+			if path == "" {
+				words, err := loadBruteForceWords(cfg)
+				if err != nil {
+					return err
+				}
+				if path, err = tempFile(strings.Join(words, "\n") + "\n"); err != nil {
+					return err
+				}
+			}
+			placeholders["{wordlist}"] = path
+		}
+		if strings.Contains(arg, "{input}") && placeholders["{input}"] == "" {
+			path, err := tempFile(domain + "\n")
+			if err != nil {
+				return err
+			}
+			placeholders["{input}"] = path
+		}
+		if strings.Contains(arg, "{output}") && outputPath == "" {
+			path, err := tempFile("")
+			if err != nil {
+				return err
+			}
+			outputPath = path
+			placeholders["{output}"] = path
+		}
+	}
+
+	args := make([]string, len(t.def.Args))
+	for i, arg := range t.def.Args {
+		for k, v := range placeholders {
+			arg = strings.ReplaceAll(arg, k, v)
+		}
+		args[i] = arg
+	}
+
+	cmd := exec.CommandContext(ctx, t.def.Binary, args...)
+	if t.def.Input == "stdin" {
+		cmd.Stdin = strings.NewReader(domain + "\n")
+	}
+
+	onLine := func(line string) {
+		for _, name := range t.parse(line) {
+			if name = normalizeSourceName(name, domain, false); name != "" {
+				emit(name)
+			}
+		}
+	}
+	if outputPath == "" {
+		if err := streamCommand(cmd, onLine); err != nil {
+			return fmt.Errorf("%s execution failed: %v", t.def.Name, err)
+		}
+		return nil
+	}
+
+	// Results go to the output file; stdout is only progress noise
+	if err := streamCommand(cmd, func(string) {}); err != nil {
+		return fmt.Errorf("%s execution failed: %v", t.def.Name, err)
+	}
+	lines, err := utils.ReadLines(outputPath)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read output: %v", t.def.Name, err)
+	}
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			onLine(line)
+		}
+	}
+	return nil
+}
+
+// parse returns the names in one line of tool output.
+func (t *ToolEnumerator) parse(line string) []string {
+	switch t.def.Format {
+	case "jsonl":
+		var doc any
+		if err := json.Unmarshal([]byte(line), &doc); err != nil {
+			return nil
+		}
+		return jsonPathValues(doc, t.def.JSONPath)
+	case "regex":
+		var names []string
+		for _, m := range t.regex.FindAllStringSubmatch(line, -1) {
+			if len(m) > 1 {
+				names = append(names, m[1])
+			} else {
+				names = append(names, m[0])
+			}
+		}
+		return names
+	default:
+		if strings.HasPrefix(line, "#") {
+			return nil
+		}
+		return strings.Fields(line)
+	}
+}
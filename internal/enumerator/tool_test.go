@@ -0,0 +1,127 @@
+package enumerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// runTool runs a definition of sh and returns its sorted names.
+func runTool(t *testing.T, def ToolDefinition, cfg *config.Config) []string {
+	t.Helper()
+	def.Name, def.Binary = "testtool", "sh"
+	e, err := NewToolEnumerator(def)
+	if err != nil {
+		t.Fatalf("NewToolEnumerator returned error: %v", err)
+	}
+	subdomains, err := e.Enumerate(context.Background(), "example.com", cfg)
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	sort.Strings(subdomains)
+	return subdomains
+}
+
+func TestToolEnumeratorStdinLines(t *testing.T) {
+	got := runTool(t, ToolDefinition{
+		Args:  []string{"-c", `read d; echo "www.$d"; echo '# banner'; echo "API.$d 1.2.3.4"; echo other.org`},
+		Input: "stdin",
+	}, &config.Config{})
+
+	if want := []string{"api.example.com", "www.example.com"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestToolEnumeratorJSONLinesOutputFile(t *testing.T) {
+	got := runTool(t, ToolDefinition{
+		Args:     []string{"-c", `echo progress; printf '{"host":"a.{domain}"}\n{"host":"b.{domain}"}\nnot json\n' > {output}`},
+		Format:   "jsonl",
+		JSONPath: "host",
+	}, &config.Config{})
+
+	if want := []string{"a.example.com", "b.example.com"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestToolEnumeratorRegexInputAndWordlist(t *testing.T) {
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte("dev\nvpn\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := runTool(t, ToolDefinition{
+		Args:   []string{"-c", `d=$(cat {input}); while read w; do echo "https://$w.$d/path"; done < {wordlist}`},
+		Input:  "file",
+		Format: "regex",
+		Regex:  `https?://([^/:]+)`,
+	}, &config.Config{Wordlist: wordlist})
+
+	if want := []string{"dev.example.com", "vpn.example.com"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestLoadTools(t *testing.T) {
+	dir := t.TempDir()
+	def := `name: testloadedtool
+binary: sh
+args: ["-c", "echo x.{domain}"]
+requires_env: [TEST_TOOL_KEY]
+install:
+  linux: go install example.invalid/tool@latest
+`
+	if err := os.WriteFile(filepath.Join(dir, "tool.yml"), []byte(def), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { delete(enumerators, "testloadedtool") })
+
+	names, err := LoadTools(dir)
+	if err != nil {
+		t.Fatalf("LoadTools returned error: %v", err)
+	}
+	if !slices.Equal(names, []string{"testloadedtool"}) {
+		t.Fatalf("Expected [testloadedtool], got %v", names)
+	}
+	if utils.CheckToolAvailability("testloadedtool") {
+		t.Error("Expected the tool to be unavailable without its environment variable")
+	}
+	t.Setenv("TEST_TOOL_KEY", "k")
+	if !utils.CheckToolAvailability("testloadedtool") {
+		t.Error("Expected the tool to be available once its environment variable is set")
+	}
+
+	i := slices.IndexFunc(utils.GetRequiredTools(), func(tool utils.Tool) bool { return tool.Name == "testloadedtool" })
+	if i < 0 {
+		t.Fatal("Expected the tool to be listed for --check-tools")
+	}
+	if got := utils.GetRequiredTools()[i].InstallCmd["linux"]; got != "go install example.invalid/tool@latest" {
+		t.Errorf("Expected the declared install hint, got %q", got)
+	}
+}
+
+func TestLoadToolsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no binary":     "name: bad\nargs: [x]\n",
+		"unknown key":   "name: bad\nbinary: sh\nargz: [x]\n",
+		"file input":    "name: bad\nbinary: sh\ninput: file\nargs: [x]\n",
+		"jsonl no path": "name: bad\nbinary: sh\nformat: jsonl\n",
+		"builtin name":  "name: subfinder\nbinary: sh\n",
+	}
+	for name, def := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(def), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadTools(dir); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -6,16 +6,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
-	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
 )
 
 type URLScanEnumerator struct {
-	apiKey string
 	client *http.Client
 }
 
@@ -35,23 +33,20 @@ func (u *URLScanEnumerator) Enumerate(ctx context.Context, domain string, cfg *c
 	// Build API URL
 	url := fmt.Sprintf("https://urlscan.io/api/v1/search/?q=domain:%s", domain)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	// Add headers
-	req.Header.Set("User-Agent", "SubdomainX/1.0")
-	req.Header.Set("Accept", "application/json")
-
-	// Add API key if available
-	if u.apiKey != "" {
-		req.Header.Set("API-Key", u.apiKey)
-	}
-
-	// Make request with 429 retry handling
-	resp, err := utils.DoWithRetry(u.client, req, cfg.Retries, cfg.Timeout)
+	// Make request, rotating API keys on 429 and quota errors; the key is
+	// optional, so without one the request goes out unauthenticated
+	resp, err := doWithKeys(u.client, cfg, "urlscan", false, func(key *credentials.Key) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("User-Agent", "SubdomainX/1.0")
+		req.Header.Set("Accept", "application/json")
+		if key != nil {
+			req.Header.Set("API-Key", key.Key)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("urlscan API request failed: %v", err)
 	}
@@ -89,21 +84,9 @@ func (u *URLScanEnumerator) Enumerate(ctx context.Context, domain string, cfg *c
 func init() {
 	// Create URLScan enumerator with default settings
 	enumerator := &URLScanEnumerator{
-		apiKey: getURLScanAPIKey(),
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 	RegisterEnumerator(enumerator)
 }
-
-// getURLScanAPIKey retrieves the API key from environment variable
-func getURLScanAPIKey() string {
-	// Try to get from environment variable
-	apiKey := strings.TrimSpace(os.Getenv("URLSCAN_API_KEY"))
-	if apiKey == "" {
-		// Could also try to get from config file in the future
-		return ""
-	}
-	return apiKey
-}
@@ -6,16 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
-	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
 )
 
 type VirusTotalEnumerator struct {
-	apiKey string
 	client *http.Client
 }
 
@@ -34,26 +31,19 @@ func (v *VirusTotalEnumerator) Name() string {
 }
 
 func (v *VirusTotalEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
-	// Check if API key is configured
-	if v.apiKey == "" {
-		return nil, fmt.Errorf("virustotal API key not configured")
-	}
-
 	// Build API URL
 	url := fmt.Sprintf("https://www.virustotal.com/api/v3/domains/%s/subdomains", domain)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	// Add headers
-	req.Header.Set("x-apikey", v.apiKey)
-	req.Header.Set("Accept", "application/json")
-
-	// Make request with 429 retry handling
-	resp, err := utils.DoWithRetry(v.client, req, cfg.Retries, cfg.Timeout)
+	// Make request, rotating API keys on 429 and quota errors
+	resp, err := doWithKeys(v.client, cfg, "virustotal", true, func(key *credentials.Key) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("x-apikey", key.Key)
+		req.Header.Set("Accept", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("virustotal API request failed: %v", err)
 	}
@@ -84,22 +74,12 @@ func (v *VirusTotalEnumerator) Enumerate(ctx context.Context, domain string, cfg
 }
 
 func init() {
-	// Create VirusTotal enumerator with default settings
+	// Create VirusTotal enumerator with default settings; API keys come
+	// from the credentials file or VIRUSTOTAL_API_KEY
 	enumerator := &VirusTotalEnumerator{
-		apiKey: getVirusTotalAPIKey(),
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 	RegisterEnumerator(enumerator)
 }
-
-// getVirusTotalAPIKey retrieves the API key from environment variable
-func getVirusTotalAPIKey() string {
-	// Try to get from environment variable
-	apiKey := strings.TrimSpace(os.Getenv("VIRUSTOTAL_API_KEY"))
-	if apiKey == "" {
-		return ""
-	}
-	return apiKey
-}
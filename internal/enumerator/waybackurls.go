@@ -16,55 +16,46 @@ func (w *WaybackURLsEnumerator) Name() string {
 }
 
 func (w *WaybackURLsEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config, ) ([]string, error) {
-	// Build waybackurls command
-	// waybackurls expects domains on stdin, so we'll echo the domain and pipe it
-	cmd := exec.CommandContext(ctx, "sh", "-c", fmt.Sprintf("echo %s | waybackurls", domain))
-
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("waybackurls execution failed: %v", err)
-	}
-
-	// Parse output (one URL per line)
-	lines := strings.Split(string(output), "\n")
-	subdomainSet := make(map[string]bool)
+	return collectStream(ctx, w, domain, cfg)
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+// EnumerateStream emits each new hostname as waybackurls prints its URLs.
+func (w *WaybackURLsEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
+	// waybackurls expects domains on stdin
+	cmd := exec.CommandContext(ctx, "waybackurls")
+	cmd.Stdin = strings.NewReader(domain + "\n")
 
+	seen := make(map[string]bool)
+	err := streamCommand(cmd, func(line string) {
 		// Extract subdomain from URL
 		// waybackurls returns full URLs, we need to extract the hostname
-		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
-			// Remove protocol
-			url := strings.TrimPrefix(line, "http://")
-			url = strings.TrimPrefix(url, "https://")
+		if !strings.HasPrefix(line, "http://") && !strings.HasPrefix(line, "https://") {
+			return
+		}
 
-			// Get hostname (before first slash or port)
-			hostname := url
-			if slashIndex := strings.Index(url, "/"); slashIndex != -1 {
-				hostname = url[:slashIndex]
-			}
-			if colonIndex := strings.Index(hostname, ":"); colonIndex != -1 {
-				hostname = hostname[:colonIndex]
-			}
+		// Remove protocol
+		url := strings.TrimPrefix(line, "http://")
+		url = strings.TrimPrefix(url, "https://")
 
-			// Check if it's a subdomain of our target domain
-			if strings.HasSuffix(hostname, "."+domain) && hostname != domain {
-				subdomainSet[hostname] = true
-			}
+		// Get hostname (before first slash or port)
+		hostname := url
+		if slashIndex := strings.Index(url, "/"); slashIndex != -1 {
+			hostname = url[:slashIndex]
+		}
+		if colonIndex := strings.Index(hostname, ":"); colonIndex != -1 {
+			hostname = hostname[:colonIndex]
 		}
-	}
 
-	// Convert set to slice
-	var subdomains []string
-	for subdomain := range subdomainSet {
-		subdomains = append(subdomains, subdomain)
+		// Check if it's a subdomain of our target domain
+		if strings.HasSuffix(hostname, "."+domain) && hostname != domain && !seen[hostname] {
+			seen[hostname] = true
+			emit(hostname)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("waybackurls execution failed: %v", err)
 	}
-
-	return subdomains, nil
+	return nil
 }
 
 func init() {
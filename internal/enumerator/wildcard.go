@@ -0,0 +1,258 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// wildcardProbes is the number of random labels queried per zone. A zone is
+// considered wildcarded when every probe resolves.
+const wildcardProbes = 3
+
+// wildcardAnswer holds the answers a wildcard record returns for random labels.
+type wildcardAnswer struct {
+	ips    map[string]bool
+	cnames map[string]bool
+}
+
+// zoneProbe caches the wildcard detection result for one zone.
+type zoneProbe struct {
+	once   sync.Once
+	answer *wildcardAnswer // nil when the zone has no wildcard
+}
+
+// WildcardDetector detects wildcard DNS records per zone (apex and sub-zones)
+// and checks whether a host's answer is indistinguishable from the wildcard.
+type WildcardDetector struct {
+	resolver resolver.Resolver
+	mu       sync.Mutex
+	zones    map[string]*zoneProbe
+}
+
+// NewWildcardDetector creates a detector that queries through resolver.
+func NewWildcardDetector(r resolver.Resolver) *WildcardDetector {
+	return &WildcardDetector{
+		resolver: r,
+		zones:    make(map[string]*zoneProbe),
+	}
+}
+
+// Check reports whether host only matches a wildcard answer, and the closest
+// enclosing zone (up to and including apex) that has a wildcard record.
+func (w *WildcardDetector) Check(ctx context.Context, host, apex string) (matches bool, zone string) {
+	for _, z := range enclosingZones(host, apex) {
+		answer := w.probeZone(ctx, z)
+		if answer == nil {
+			continue
+		}
+		ips, cname := w.resolve(ctx, host)
+		return answer.matches(ips, cname), z
+	}
+	return false, ""
+}
+
+// Wildcards returns the zones found to have wildcard records, along with the
+// IPs and CNAMEs they answer with.
+func (w *WildcardDetector) Wildcards() map[string][]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make(map[string][]string)
+	for zone, p := range w.zones {
+		if p.answer == nil {
+			continue
+		}
+		var values []string
+		for ip := range p.answer.ips {
+			values = append(values, ip)
+		}
+		for cname := range p.answer.cnames {
+			values = append(values, "CNAME "+cname)
+		}
+		sort.Strings(values)
+		result[zone] = values
+	}
+	return result
+}
+
+// probeZone resolves random labels under zone once and caches the outcome.
+func (w *WildcardDetector) probeZone(ctx context.Context, zone string) *wildcardAnswer {
+	w.mu.Lock()
+	p, ok := w.zones[zone]
+	if !ok {
+		p = &zoneProbe{}
+		w.zones[zone] = p
+	}
+	w.mu.Unlock()
+
+	p.once.Do(func() {
+		answer := &wildcardAnswer{ips: make(map[string]bool), cnames: make(map[string]bool)}
+		for i := 0; i < wildcardProbes; i++ {
+			ips, cname := w.resolve(ctx, randomLabel()+"."+zone)
+			if len(ips) == 0 {
+				return // at least one random label did not resolve — no wildcard
+			}
+			for _, ip := range ips {
+				answer.ips[ip] = true
+			}
+			if cname != "" {
+				answer.cnames[cname] = true
+			}
+		}
+		p.answer = answer
+	})
+	return p.answer
+}
+
+// resolve returns the addresses of host and its canonical name when the
+// host is an alias.
+func (w *WildcardDetector) resolve(ctx context.Context, host string) ([]string, string) {
+	lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+	defer cancel()
+
+	fqdn := strings.TrimSuffix(host, ".") + "."
+	ips, err := w.resolver.LookupHost(lookupCtx, fqdn)
+	if err != nil {
+		return nil, ""
+	}
+	cname, err := w.resolver.LookupCNAME(lookupCtx, fqdn)
+	if err != nil || strings.EqualFold(cname, fqdn) {
+		cname = ""
+	}
+	return ips, strings.ToLower(strings.TrimSuffix(cname, "."))
+}
+
+// matches reports whether a host's answer only contains wildcard data.
+func (a *wildcardAnswer) matches(ips []string, cname string) bool {
+	if cname != "" && a.cnames[cname] {
+		return true
+	}
+	if len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !a.ips[ip] {
+			return false
+		}
+	}
+	return true
+}
+
+// enclosingZones returns the zones between host (exclusive) and apex
+// (inclusive), closest first: a.b.example.com → b.example.com, example.com.
+func enclosingZones(host, apex string) []string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	apex = strings.ToLower(strings.TrimSuffix(apex, "."))
+	if !strings.HasSuffix(host, "."+apex) {
+		return nil
+	}
+
+	var zones []string
+	labels := strings.Split(strings.TrimSuffix(host, "."+apex), ".")
+	for i := 1; i < len(labels); i++ {
+		zones = append(zones, strings.Join(labels[i:], ".")+"."+apex)
+	}
+	return append(zones, apex)
+}
+
+// randomLabel returns a label that is vanishingly unlikely to exist.
+func randomLabel() string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, 16)
+	for i := range b {
+		b[i] = alphabet[rand.IntN(len(alphabet))]
+	}
+	return string(b)
+}
+
+// apexFor returns the longest input domain that host belongs to.
+func apexFor(host string, domains []string) string {
+	best := ""
+	for _, d := range domains {
+		d = strings.ToLower(d)
+		if (host == d || strings.HasSuffix(host, "."+d)) && len(d) > len(best) {
+			best = d
+		}
+	}
+	return best
+}
+
+// filterWildcards checks every result against wildcard records in its zones.
+// Depending on cfg.WildcardFilter, results that only match a wildcard answer
+// are dropped ("drop", the default), flagged ("flag"), or left alone ("off").
+// Probing stops when ctx is cancelled.
+func filterWildcards(ctx context.Context, cfg *config.Config, domains []string, results []types.SubdomainResult, sink tui.EventSink) []types.SubdomainResult {
+	mode := cfg.WildcardFilter
+	if mode == "" {
+		mode = "drop"
+	}
+	if mode == "off" || len(results) == 0 {
+		return results
+	}
+
+	detector := NewWildcardDetector(resolver.FromContext(ctx))
+
+	threads := cfg.DNSThreads
+	if threads <= 0 {
+		threads = 50
+	}
+	pool := utils.NewWorkerPool(threads, cfg.DNSRateLimit)
+	defer pool.Stop()
+
+	matched := make([]bool, len(results))
+	var wg sync.WaitGroup
+	for i := range results {
+		if ctx.Err() != nil {
+			break // scan cancelled: keep the remaining results unchecked
+		}
+		apex := apexFor(results[i].Subdomain, domains)
+		if apex == "" || apex == results[i].Subdomain {
+			continue
+		}
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+			if ctx.Err() != nil {
+				return
+			}
+			m, zone := detector.Check(ctx, results[i].Subdomain, apex)
+			matched[i] = m
+			results[i].WildcardZone = zone
+		})
+	}
+	wg.Wait()
+
+	for zone, answers := range detector.Wildcards() {
+		sink.Log("warn", fmt.Sprintf("Wildcard DNS detected for *.%s (%s)", zone, strings.Join(answers, ", ")))
+	}
+
+	var filtered []types.SubdomainResult
+	dropped := 0
+	for i, r := range results {
+		if !matched[i] {
+			filtered = append(filtered, r)
+			continue
+		}
+		if mode == "flag" {
+			r.Wildcard = true
+			filtered = append(filtered, r)
+			continue
+		}
+		dropped++
+	}
+	if dropped > 0 {
+		sink.Log("info", fmt.Sprintf("Dropped %d subdomains that only matched wildcard DNS answers", dropped))
+	}
+
+	return filtered
+}
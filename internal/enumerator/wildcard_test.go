@@ -0,0 +1,109 @@
+package enumerator
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// wildcardZone has an A wildcard at the apex, a CNAME wildcard under
+// apps.example.com and a handful of real hosts.
+var wildcardZone = testZone{
+	"example.com.":          {"example.com. 60 IN A 192.0.2.1"},
+	"*.example.com.":        {"*.example.com. 60 IN A 198.51.100.1", "*.example.com. 60 IN A 198.51.100.2"},
+	"www.example.com.":      {"www.example.com. 60 IN A 192.0.2.10"},
+	"lb.example.com.":       {"lb.example.com. 60 IN A 198.51.100.1"},
+	"mixed.example.com.":    {"mixed.example.com. 60 IN A 198.51.100.1", "mixed.example.com. 60 IN A 192.0.2.20"},
+	"edge.example.com.":     {"edge.example.com. 60 IN A 203.0.113.5"},
+	"apps.example.com.":     {"apps.example.com. 60 IN A 203.0.113.9"},
+	"*.apps.example.com.":   {"*.apps.example.com. 60 IN CNAME edge.example.com."},
+	"svc.apps.example.com.": {"svc.apps.example.com. 60 IN CNAME edge.example.com."},
+	"api.apps.example.com.": {"api.apps.example.com. 60 IN A 192.0.2.30"},
+	"plain.example.org.":    {"plain.example.org. 60 IN A 192.0.2.40"},
+}
+
+func TestWildcardDetectorCheck(t *testing.T) {
+	ctx := testResolverContext(t, wildcardZone)
+	detector := NewWildcardDetector(resolver.FromContext(ctx))
+
+	tests := []struct {
+		host, apex string
+		matches    bool
+		zone       string
+	}{
+		{"www.example.com", "example.com", false, "example.com"},
+		{"lb.example.com", "example.com", true, "example.com"},            // IPs are a subset of the wildcard's
+		{"mixed.example.com", "example.com", false, "example.com"},        // one IP outside the wildcard
+		{"svc.apps.example.com", "example.com", true, "apps.example.com"}, // same CNAME target
+		{"api.apps.example.com", "example.com", false, "apps.example.com"},
+		{"plain.example.org", "example.org", false, ""}, // no wildcard in the zone
+	}
+	for _, tt := range tests {
+		matches, zone := detector.Check(ctx, tt.host, tt.apex)
+		if matches != tt.matches || zone != tt.zone {
+			t.Errorf("Check(%s) = %v, %q; want %v, %q", tt.host, matches, zone, tt.matches, tt.zone)
+		}
+	}
+
+	wildcards := detector.Wildcards()
+	if got := wildcards["example.com"]; len(got) != 2 || got[0] != "198.51.100.1" || got[1] != "198.51.100.2" {
+		t.Errorf("Unexpected apex wildcard answers: %v", got)
+	}
+	if got := wildcards["apps.example.com"]; len(got) != 2 || got[0] != "203.0.113.5" || got[1] != "CNAME edge.example.com" {
+		t.Errorf("Unexpected CNAME wildcard answers: %v", got)
+	}
+}
+
+func TestFilterWildcards(t *testing.T) {
+	ctx := testResolverContext(t, wildcardZone)
+	results := func() []types.SubdomainResult {
+		return []types.SubdomainResult{
+			{Subdomain: "www.example.com"},
+			{Subdomain: "lb.example.com"},
+			{Subdomain: "svc.apps.example.com"},
+			{Subdomain: "api.apps.example.com"},
+		}
+	}
+	sink := tui.NewCLIEventSink()
+
+	kept := filterWildcards(ctx, &config.Config{WildcardFilter: "drop"}, []string{"example.com"}, results(), sink)
+	if got := subdomainNames(kept); len(got) != 2 || got[0] != "api.apps.example.com" || got[1] != "www.example.com" {
+		t.Errorf("drop mode kept %v", got)
+	}
+
+	flagged := filterWildcards(ctx, &config.Config{WildcardFilter: "flag"}, []string{"example.com"}, results(), sink)
+	if len(flagged) != 4 {
+		t.Fatalf("flag mode should keep every result, got %d", len(flagged))
+	}
+	for _, r := range flagged {
+		want := r.Subdomain == "lb.example.com" || r.Subdomain == "svc.apps.example.com"
+		if r.Wildcard != want {
+			t.Errorf("%s: Wildcard = %v, want %v", r.Subdomain, r.Wildcard, want)
+		}
+	}
+}
+
+func TestFilterWildcardsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(testResolverContext(t, wildcardZone))
+	cancel()
+
+	results := []types.SubdomainResult{{Subdomain: "lb.example.com"}, {Subdomain: "www.example.com"}}
+	kept := filterWildcards(ctx, &config.Config{}, []string{"example.com"}, results, tui.NewCLIEventSink())
+	if len(kept) != 2 {
+		t.Errorf("Expected a cancelled scan to leave results unchecked, got %v", subdomainNames(kept))
+	}
+}
+
+func subdomainNames(results []types.SubdomainResult) []string {
+	names := make([]string, 0, len(results))
+	for _, r := range results {
+		names = append(names, r.Subdomain)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,281 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+const (
+	// maxZoneWalkNames bounds an NSEC walk so a looping chain cannot run
+	// forever.
+	maxZoneWalkNames = 50000
+	// maxNSEC3Probes bounds the random names queried to collect NSEC3 hashes.
+	maxNSEC3Probes = 2000
+	// zoneWalkProgressEvery is how many names pass between progress reports.
+	zoneWalkProgressEvery = 50
+)
+
+// ZoneWalkEnumerator lists the names of DNSSEC-signed zones. Zones signed
+// with NSEC are walked along the chain of next-owner names, which yields
+// every name. For NSEC3 zones the hashed owner names are collected from
+// denial responses and cracked offline against the wordlist.
+type ZoneWalkEnumerator struct{}
+
+func (z *ZoneWalkEnumerator) Name() string {
+	return "zonewalk"
+}
+
+func (z *ZoneWalkEnumerator) Enumerate(ctx context.Context, domain string, cfg *config.Config) ([]string, error) {
+	return collectStream(ctx, z, domain, cfg)
+}
+
+// EnumerateStream asks the zone's own nameservers how it is signed and then
+// walks or collects hashes, emitting names as they are found. Zones that are
+// not signed yield nothing.
+func (z *ZoneWalkEnumerator) EnumerateStream(ctx context.Context, domain string, cfg *config.Config, emit func(string)) error {
+	servers, err := nameserverAddrs(ctx, resolver.FromContext(ctx), domain)
+	if err != nil {
+		return fmt.Errorf("zonewalk: %v", err)
+	}
+
+	w := &zoneWalker{zone: dns.Fqdn(strings.ToLower(domain)), emit: emit}
+	for _, server := range servers {
+		w.server = server
+		mode, err := w.signingMode(ctx)
+		if err != nil {
+			continue // try the next nameserver
+		}
+		switch mode {
+		case dns.TypeNSEC:
+			return w.walkNSEC(ctx)
+		case dns.TypeNSEC3:
+			return w.crackNSEC3(ctx, cfg)
+		default:
+			return nil
+		}
+	}
+	return fmt.Errorf("zonewalk: no nameserver of %s answered", domain)
+}
+
+// nameserverAddrs returns host:port addresses of domain's nameservers.
+func nameserverAddrs(ctx context.Context, r resolver.Resolver, domain string) ([]string, error) {
+	nameservers, err := lookupNS(ctx, r, domain)
+	if err != nil {
+		return nil, err
+	}
+	var servers []string
+	for _, ns := range nameservers {
+		lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+		addrs, err := r.LookupHost(lookupCtx, ns+".")
+		cancel()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			servers = append(servers, net.JoinHostPort(addr, nameserverPort))
+		}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no nameservers found for %s", domain)
+	}
+	return servers, nil
+}
+
+// zoneWalker queries one authoritative server of zone.
+type zoneWalker struct {
+	zone   string // rooted, lower case
+	server string // host:port
+	emit   func(string)
+	found  int
+}
+
+// query asks the server for name/qtype with the DNSSEC OK bit set, retrying
+// over TCP when the answer is truncated.
+func (w *zoneWalker) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(name, qtype)
+	m.RecursionDesired = false
+	m.SetEdns0(4096, true)
+
+	lookupCtx, cancel := context.WithTimeout(ctx, utils.DNSQueryTimeout)
+	defer cancel()
+
+	resp, _, err := (&dns.Client{Net: "udp"}).ExchangeContext(lookupCtx, m, w.server)
+	if err == nil && resp.Truncated {
+		resp, _, err = (&dns.Client{Net: "tcp"}).ExchangeContext(lookupCtx, m, w.server)
+	}
+	return resp, err
+}
+
+// signingMode probes a name that does not exist and returns the type of
+// denial record in the answer: TypeNSEC, TypeNSEC3 or 0 for unsigned zones.
+func (w *zoneWalker) signingMode(ctx context.Context) (uint16, error) {
+	resp, err := w.query(ctx, randomLabel()+"."+w.zone, dns.TypeA)
+	if err != nil {
+		return 0, err
+	}
+	for _, rr := range resp.Ns {
+		switch rr.(type) {
+		case *dns.NSEC:
+			return dns.TypeNSEC, nil
+		case *dns.NSEC3:
+			return dns.TypeNSEC3, nil
+		}
+	}
+	return 0, nil
+}
+
+// report emits name if it belongs to the zone and reports progress now
+// and then.
+func (w *zoneWalker) report(ctx context.Context, name string) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if !strings.HasSuffix(name+".", "."+w.zone) || strings.HasPrefix(name, "*.") {
+		return
+	}
+	w.emit(name)
+	w.found++
+	if w.found%zoneWalkProgressEvery == 0 {
+		reportProgress(ctx, "running", w.found)
+	}
+}
+
+// walkNSEC follows the NSEC chain from the apex until it wraps around.
+// Names found before an error are still emitted.
+func (w *zoneWalker) walkNSEC(ctx context.Context) error {
+	seen := map[string]bool{w.zone: true}
+	current := w.zone
+	for range maxZoneWalkNames {
+		if ctx.Err() != nil {
+			return fmt.Errorf("zonewalk: %v", ctx.Err())
+		}
+		next, err := w.nextNSEC(ctx, current)
+		if err != nil {
+			return fmt.Errorf("zonewalk: %v", err)
+		}
+		next = strings.ToLower(next)
+		if seen[next] || !dns.IsSubDomain(w.zone, next) {
+			return nil // the chain wrapped around to the apex
+		}
+		seen[next] = true
+		w.report(ctx, next)
+		current = next
+	}
+	return nil
+}
+
+// nextNSEC returns the next owner name after name. It asks for name's NSEC
+// record directly and, for servers that do not answer that, for a name
+// sorting right after it, whose denial carries the same NSEC record.
+func (w *zoneWalker) nextNSEC(ctx context.Context, name string) (string, error) {
+	resp, err := w.query(ctx, name, dns.TypeNSEC)
+	if err == nil {
+		if next, ok := nsecFor(resp.Answer, name); ok {
+			return next, nil
+		}
+	}
+
+	resp, err = w.query(ctx, `\000.`+name, dns.TypeA)
+	if err != nil {
+		return "", err
+	}
+	if next, ok := nsecFor(resp.Ns, name); ok {
+		return next, nil
+	}
+	return "", fmt.Errorf("no NSEC record for %s", name)
+}
+
+// nsecFor returns the next domain of the NSEC record owned by name in rrs.
+func nsecFor(rrs []dns.RR, name string) (string, bool) {
+	for _, rr := range rrs {
+		if nsec, ok := rr.(*dns.NSEC); ok && strings.EqualFold(nsec.Hdr.Name, name) {
+			return nsec.NextDomain, true
+		}
+	}
+	return "", false
+}
+
+// nsec3Chain holds the NSEC3 records collected for a zone, keyed by the
+// owner hash.
+type nsec3Chain struct {
+	records map[string]*dns.NSEC3
+}
+
+// add stores rr under its owner hash.
+func (c *nsec3Chain) add(rr *dns.NSEC3) {
+	hash := strings.ToUpper(strings.SplitN(rr.Hdr.Name, ".", 2)[0])
+	c.records[hash] = rr
+}
+
+// complete reports whether every collected record's next hash is also a
+// collected owner, i.e. the whole chain is known.
+func (c *nsec3Chain) complete() bool {
+	if len(c.records) == 0 {
+		return false
+	}
+	for _, rr := range c.records {
+		if _, ok := c.records[strings.ToUpper(rr.NextDomain)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// crackNSEC3 collects NSEC3 hashes from denials of random names until the
+// chain is complete or the probe budget runs out, then hashes every
+// wordlist candidate with the zone's parameters and emits the matches.
+func (w *zoneWalker) crackNSEC3(ctx context.Context, cfg *config.Config) error {
+	chain := &nsec3Chain{records: make(map[string]*dns.NSEC3)}
+	var params *dns.NSEC3
+	for range maxNSEC3Probes {
+		if ctx.Err() != nil || chain.complete() {
+			break
+		}
+		resp, err := w.query(ctx, randomLabel()+"."+w.zone, dns.TypeA)
+		if err != nil {
+			continue
+		}
+		for _, rr := range resp.Ns {
+			if nsec3, ok := rr.(*dns.NSEC3); ok {
+				chain.add(nsec3)
+				params = nsec3
+			}
+		}
+	}
+	if params == nil {
+		return fmt.Errorf("zonewalk: no NSEC3 records collected for %s", w.zone)
+	}
+
+	// Next hashes of the collected records name existing owners too
+	hashes := make(map[string]bool)
+	for hash, rr := range chain.records {
+		hashes[hash] = true
+		hashes[strings.ToUpper(rr.NextDomain)] = true
+	}
+	reportProgress(ctx, "running", 0)
+
+	words, err := loadBruteForceWords(cfg)
+	if err != nil {
+		return fmt.Errorf("zonewalk: %v", err)
+	}
+	for _, word := range words {
+		if ctx.Err() != nil {
+			return fmt.Errorf("zonewalk: %v", ctx.Err())
+		}
+		candidate := word + "." + w.zone
+		if hashes[dns.HashName(candidate, params.Hash, params.Iterations, params.Salt)] {
+			w.report(ctx, candidate)
+		}
+	}
+	return nil
+}
+
+func init() {
+	RegisterEnumerator(&ZoneWalkEnumerator{})
+}
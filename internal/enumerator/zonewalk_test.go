@@ -0,0 +1,185 @@
+package enumerator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/miekg/dns"
+)
+
+// zoneWalkNames are the owner names of the signed test zone in canonical
+// order.
+var zoneWalkNames = []string{"example.com.", "mail.example.com.", "ns1.example.com.", "www.example.com."}
+
+// nsecHandler serves an NSEC-signed zone made of zoneWalkNames. Unless
+// direct is set it does not answer NSEC queries, so walkers have to fall
+// back to probing the name right after each owner.
+type nsecHandler struct {
+	direct bool
+}
+
+func (h nsecHandler) nsec(i int) dns.RR {
+	next := zoneWalkNames[(i+1)%len(zoneWalkNames)]
+	rr, _ := dns.NewRR(zoneWalkNames[i] + " 60 IN NSEC " + next + " A NS RRSIG NSEC")
+	return rr
+}
+
+func (h nsecHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	q := r.Question[0]
+	name := strings.ToLower(q.Name)
+	i := slices.Index(zoneWalkNames, name)
+
+	switch {
+	case i >= 0 && q.Qtype == dns.TypeNSEC && h.direct:
+		m.Answer = append(m.Answer, h.nsec(i))
+	case name == "example.com." && q.Qtype == dns.TypeNS:
+		rr, _ := dns.NewRR("example.com. 60 IN NS ns1.example.com.")
+		m.Answer = append(m.Answer, rr)
+	case name == "ns1.example.com." && q.Qtype == dns.TypeA:
+		rr, _ := dns.NewRR("ns1.example.com. 60 IN A 127.0.0.1")
+		m.Answer = append(m.Answer, rr)
+	case i >= 0:
+		// NODATA
+	default:
+		// Deny with the NSEC of the closest owner before the name
+		m.Rcode = dns.RcodeNameError
+		owner := 0
+		for j, n := range zoneWalkNames {
+			if strings.HasSuffix(name, "."+n) {
+				owner = j
+			}
+		}
+		m.Ns = append(m.Ns, h.nsec(owner))
+	}
+	_ = w.WriteMsg(m)
+}
+
+// nsec3Handler serves zoneWalkNames as an NSEC3-signed zone, denying
+// unknown names with the NSEC3 record whose hash range covers them.
+type nsec3Handler struct {
+	hashes []string // sorted owner hashes
+}
+
+const (
+	nsec3Salt       = "AABB"
+	nsec3Iterations = 1
+)
+
+func newNSEC3Handler() nsec3Handler {
+	var hashes []string
+	for _, n := range zoneWalkNames {
+		hashes = append(hashes, dns.HashName(n, dns.SHA1, nsec3Iterations, nsec3Salt))
+	}
+	sort.Strings(hashes)
+	return nsec3Handler{hashes: hashes}
+}
+
+func (h nsec3Handler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	q := r.Question[0]
+	name := strings.ToLower(q.Name)
+
+	switch {
+	case name == "example.com." && q.Qtype == dns.TypeNS:
+		rr, _ := dns.NewRR("example.com. 60 IN NS ns1.example.com.")
+		m.Answer = append(m.Answer, rr)
+	case name == "ns1.example.com." && q.Qtype == dns.TypeA:
+		rr, _ := dns.NewRR("ns1.example.com. 60 IN A 127.0.0.1")
+		m.Answer = append(m.Answer, rr)
+	case slices.Contains(zoneWalkNames, name):
+		// NODATA
+	default:
+		m.Rcode = dns.RcodeNameError
+		hash := dns.HashName(name, dns.SHA1, nsec3Iterations, nsec3Salt)
+		i := len(h.hashes) - 1 // hashes before the first wrap around
+		for j, owner := range h.hashes {
+			if owner < hash {
+				i = j
+			}
+		}
+		next := h.hashes[(i+1)%len(h.hashes)]
+		rr, _ := dns.NewRR(h.hashes[i] + ".example.com. 60 IN NSEC3 1 0 1 " + nsec3Salt + " " + next + " A RRSIG")
+		m.Ns = append(m.Ns, rr)
+	}
+	_ = w.WriteMsg(m)
+}
+
+func TestZoneWalkNSEC(t *testing.T) {
+	want := []string{"mail.example.com", "ns1.example.com", "www.example.com"}
+	for _, direct := range []bool{true, false} {
+		ctx := nameserverContext(t, nsecHandler{direct: direct})
+
+		subdomains, err := (&ZoneWalkEnumerator{}).Enumerate(ctx, "example.com", &config.Config{})
+		if err != nil {
+			t.Fatalf("Enumerate returned error: %v", err)
+		}
+		sort.Strings(subdomains)
+		if !slices.Equal(subdomains, want) {
+			t.Errorf("direct=%v: expected %v, got %v", direct, want, subdomains)
+		}
+	}
+}
+
+func TestZoneWalkNSEC3(t *testing.T) {
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte("www\nmail\nftp\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+
+	var statuses []string
+	ctx := withProgress(nameserverContext(t, newNSEC3Handler()), func(status string, _ int) {
+		statuses = append(statuses, status)
+	})
+
+	subdomains, err := (&ZoneWalkEnumerator{}).Enumerate(ctx, "example.com", &config.Config{Wordlist: wordlist})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	sort.Strings(subdomains)
+	if want := []string{"mail.example.com", "www.example.com"}; !slices.Equal(subdomains, want) {
+		t.Errorf("Expected %v, got %v", want, subdomains)
+	}
+	if !slices.Contains(statuses, "running") {
+		t.Errorf("Expected a running progress report, got %v", statuses)
+	}
+}
+
+func TestZoneWalkUnsigned(t *testing.T) {
+	ctx := nameserverContext(t, testZone{
+		"example.com.":     {"example.com. 60 IN NS ns1.example.com."},
+		"ns1.example.com.": {"ns1.example.com. 60 IN A 127.0.0.1"},
+		"www.example.com.": {"www.example.com. 60 IN A 192.0.2.1"},
+	})
+
+	subdomains, err := (&ZoneWalkEnumerator{}).Enumerate(ctx, "example.com", &config.Config{})
+	if err != nil {
+		t.Fatalf("Enumerate returned error: %v", err)
+	}
+	if len(subdomains) != 0 {
+		t.Errorf("Expected nothing from an unsigned zone, got %v", subdomains)
+	}
+}
+
+func TestNSEC3ChainComplete(t *testing.T) {
+	chain := &nsec3Chain{records: make(map[string]*dns.NSEC3)}
+	add := func(owner, next string) {
+		rr, _ := dns.NewRR(owner + ".example.com. 60 IN NSEC3 1 0 1 - " + next + " A")
+		chain.add(rr.(*dns.NSEC3))
+	}
+	add("0P9MHAVEQVM6T7VBL5LOP2U3T2RP3TOM", "2VPTU5TIMAMQTTGL4LUU9KG21E0AOR3S")
+	if chain.complete() {
+		t.Error("Expected an open chain to be incomplete")
+	}
+	add("2VPTU5TIMAMQTTGL4LUU9KG21E0AOR3S", "0P9MHAVEQVM6T7VBL5LOP2U3T2RP3TOM")
+	if !chain.complete() {
+		t.Error("Expected a closed chain to be complete")
+	}
+}
@@ -0,0 +1,181 @@
+// Package importer reads the output of earlier recon runs, from subdomainx
+// or other tools, so it can be scanned without enumerating again. The format
+// of each file is detected from its content: subfinder -oJ and amass JSON
+// lines, massdns -o S answers, or plain lists of names (findomain, and most
+// other tools' text output).
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Detected formats.
+const (
+	FormatSubfinder = "subfinder-json"
+	FormatAmass     = "amass-json"
+	FormatMassDNS   = "massdns"
+	FormatText      = "text"
+)
+
+// DefaultTool is the provenance of names from plain lists whose file name
+// does not say which tool wrote them.
+const DefaultTool = "import"
+
+// textTools are tools whose plain-text output is recognised by file name,
+// so imported names keep the provenance of the tool that found them.
+var textTools = []string{"subfinder", "amass", "findomain", "assetfinder", "sublist3r", "knockpy", "waybackurls", "massdns", "puredns", "chaos", "gau"}
+
+// dnsTypes are the record types massdns prints in its simple (-o S) output.
+var dnsTypes = map[string]bool{"A": true, "AAAA": true, "CNAME": true, "NS": true, "MX": true, "TXT": true, "PTR": true, "SOA": true, "SRV": true, "CAA": true}
+
+// Result is the names read from one file, grouped by the tool that
+// reported them.
+type Result struct {
+	Format string
+	Names  map[string][]string // tool -> names, in file order
+}
+
+// Count returns the number of names in r.
+func (r *Result) Count() int {
+	n := 0
+	for _, names := range r.Names {
+		n += len(names)
+	}
+	return n
+}
+
+// ParseFile reads the recon output at path.
+func ParseFile(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %v", err)
+	}
+	result, err := Parse(data, filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return result, nil
+}
+
+// Parse reads recon output, detecting its format from the first non-empty
+// line. name is the file name, which names the tool behind a plain list.
+func Parse(data []byte, name string) (*Result, error) {
+	r := &Result{Format: detect(data), Names: make(map[string][]string)}
+	seen := make(map[string]bool)
+	add := func(tool, host string) {
+		if host = normalizeName(host); host != "" && !seen[tool+" "+host] {
+			seen[tool+" "+host] = true
+			r.Names[tool] = append(r.Names[tool], host)
+		}
+	}
+	textTool := toolFromName(name)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		switch r.Format {
+		case FormatSubfinder:
+			var rec struct {
+				Host string `json:"host"`
+			}
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				return nil, fmt.Errorf("line %d: invalid subfinder JSON: %v", n, err)
+			}
+			add("subfinder", rec.Host)
+		case FormatAmass:
+			var rec struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				return nil, fmt.Errorf("line %d: invalid amass JSON: %v", n, err)
+			}
+			add("amass", rec.Name)
+		case FormatMassDNS:
+			if fields := strings.Fields(line); len(fields) >= 3 && dnsTypes[fields[1]] {
+				add("massdns", fields[0])
+			}
+		default:
+			add(textTool, textName(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// detect returns the format of data from its first non-empty line.
+func detect(data []byte) string {
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			var probe map[string]json.RawMessage
+			if json.Unmarshal([]byte(line), &probe) == nil {
+				if _, ok := probe["host"]; ok {
+					return FormatSubfinder
+				}
+				if _, ok := probe["name"]; ok {
+					return FormatAmass
+				}
+			}
+		}
+		if fields := strings.Fields(line); len(fields) >= 3 && strings.HasSuffix(fields[0], ".") && dnsTypes[fields[1]] {
+			return FormatMassDNS
+		}
+		return FormatText
+	}
+	return FormatText
+}
+
+// toolFromName returns the tool a plain list came from, judged by its file
+// name ("findomain-example.com.txt"), or DefaultTool.
+func toolFromName(name string) string {
+	name = strings.ToLower(name)
+	for _, tool := range textTools {
+		if strings.Contains(name, tool) {
+			return tool
+		}
+	}
+	return DefaultTool
+}
+
+// textName returns the host name on one line of plain output: the first
+// field, which also covers "host,ip" CSV lines and amass's
+// "host (FQDN) --> ..." graph lines.
+func textName(line string) string {
+	field := strings.Fields(line)[0]
+	if i := strings.IndexAny(field, ",;"); i >= 0 {
+		field = field[:i]
+	}
+	return field
+}
+
+// normalizeName lower-cases a host name and strips a trailing dot and a
+// leading wildcard label. It returns "" for anything that is not a
+// dotted name, such as IP addresses or URLs.
+func normalizeName(host string) string {
+	host = strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), "."), "*.")
+	if !strings.Contains(host, ".") || net.ParseIP(host) != nil {
+		return ""
+	}
+	for _, c := range host {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '.' && c != '_' {
+			return ""
+		}
+	}
+	return host
+}
@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		data   string
+		format string
+		want   map[string][]string
+	}{
+		{
+			name:   "subfinder",
+			file:   "out.json",
+			data:   `{"host":"www.example.com","input":"example.com","source":"crtsh"}` + "\n" + `{"host":"API.example.com","input":"example.com","source":"anubis"}` + "\n",
+			format: FormatSubfinder,
+			want:   map[string][]string{"subfinder": {"www.example.com", "api.example.com"}},
+		},
+		{
+			name:   "amass",
+			file:   "amass.json",
+			data:   `{"name":"mail.example.com","domain":"example.com","addresses":[{"ip":"192.0.2.1"}],"sources":["DNS"]}` + "\n",
+			format: FormatAmass,
+			want:   map[string][]string{"amass": {"mail.example.com"}},
+		},
+		{
+			name:   "massdns",
+			file:   "resolved.txt",
+			data:   "dev.example.com. A 192.0.2.1\ndev.example.com. A 192.0.2.2\ncdn.example.com. CNAME edge.cdn.net.\n",
+			format: FormatMassDNS,
+			want:   map[string][]string{"massdns": {"dev.example.com", "cdn.example.com"}},
+		},
+		{
+			name:   "findomain by file name",
+			file:   "findomain-example.com.txt",
+			data:   "# findomain\nvpn.example.com\n*.shop.example.com\nftp.example.com,192.0.2.9\n192.0.2.10\nhttps://not-a-name\n",
+			format: FormatText,
+			want:   map[string][]string{"findomain": {"vpn.example.com", "shop.example.com", "ftp.example.com"}},
+		},
+		{
+			name:   "plain list",
+			file:   "names.txt",
+			data:   "blog.example.com\nblog.example.com\n",
+			format: FormatText,
+			want:   map[string][]string{DefaultTool: {"blog.example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse([]byte(tt.data), tt.file)
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if r.Format != tt.format {
+				t.Errorf("Expected format %s, got %s", tt.format, r.Format)
+			}
+			if len(r.Names) != len(tt.want) {
+				t.Errorf("Expected tools %v, got %v", tt.want, r.Names)
+			}
+			for tool, names := range tt.want {
+				if !slices.Equal(r.Names[tool], names) {
+					t.Errorf("Expected %s names %v, got %v", tool, names, r.Names[tool])
+				}
+			}
+		})
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	if _, err := ParseFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(path, []byte(`{"host":"a.example.com"}`+"\n{broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseFile(path); err == nil {
+		t.Error("Expected an error for a broken JSON line")
+	}
+}
@@ -0,0 +1,64 @@
+package input
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ripeStatURL lists the prefixes an autonomous system announces.
+var ripeStatURL = "https://stat.ripe.net/data/announced-prefixes/data.json?resource="
+
+// ASNPrefixes returns the prefixes announced by asn ("AS13335") according to
+// RIPEstat.
+func ASNPrefixes(ctx context.Context, client *http.Client, asn string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ripeStatURL+strings.ToUpper(asn), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "SubdomainX/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RIPEstat lookup for %s failed: %v", asn, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RIPEstat lookup for %s returned status %d", asn, resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Prefixes []struct {
+				Prefix string `json:"prefix"`
+			} `json:"prefixes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("RIPEstat response for %s: %v", asn, err)
+	}
+
+	var prefixes []string
+	for _, p := range result.Data.Prefixes {
+		if network := parseNetwork(p.Prefix); network != "" {
+			prefixes = append(prefixes, network)
+		}
+	}
+	return prefixes, nil
+}
+
+// ExpandASNs resolves every ASN into its announced prefixes and adds them to
+// Networks.
+func (t *Targets) ExpandASNs(ctx context.Context, client *http.Client) error {
+	for _, asn := range t.ASNs {
+		prefixes, err := ASNPrefixes(ctx, client, asn)
+		if err != nil {
+			return err
+		}
+		t.Networks = appendUnique(t.Networks, prefixes...)
+	}
+	return nil
+}
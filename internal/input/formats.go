@@ -0,0 +1,235 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// entryColumns are the CSV headers and JSON keys that hold a scope entry in
+// HackerOne, Bugcrowd and similar exports, in order of preference.
+var entryColumns = []string{"identifier", "asset_identifier", "target", "uri", "url", "endpoint", "host", "asset", "name"}
+
+// webAssetTypes are the scope asset types worth scanning; mobile apps,
+// source code, hardware and the like are skipped.
+var webAssetTypes = map[string]bool{
+	"":           true,
+	"url":        true,
+	"wildcard":   true,
+	"domain":     true,
+	"cidr":       true,
+	"ip_address": true,
+	"ip":         true,
+	"website":    true,
+	"web":        true,
+	"api":        true,
+	"network":    true,
+}
+
+// scope context while walking JSON exports.
+type scopeContext int
+
+const (
+	scopeNone scopeContext = iota
+	scopeIn
+	scopeOut
+)
+
+// parseJSON reads Burp target-scope JSON, or HackerOne / Bugcrowd scope
+// exports: any object with an asset_identifier, and the entries of
+// in_scope / out_of_scope (or targets) arrays.
+func parseJSON(data []byte, b *builder) error {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("invalid JSON target file: %v", err)
+	}
+
+	if obj, ok := root.(map[string]interface{}); ok {
+		if target, ok := obj["target"].(map[string]interface{}); ok {
+			if scope, ok := target["scope"].(map[string]interface{}); ok {
+				parseBurpScope(scope, b)
+				return nil
+			}
+		}
+	}
+
+	ctx := scopeNone
+	if _, ok := root.([]interface{}); ok {
+		ctx = scopeIn
+	}
+	walkJSON(root, ctx, b)
+	return nil
+}
+
+// parseBurpScope reads the include/exclude lists of a Burp project's
+// target.scope, in both simple ("url") and advanced ("host" regex) mode.
+func parseBurpScope(scope map[string]interface{}, b *builder) {
+	for _, list := range []struct {
+		key     string
+		inScope bool
+	}{{"include", true}, {"exclude", false}} {
+		inScope := list.inScope
+		items, _ := scope[list.key].([]interface{})
+		for _, item := range items {
+			rule, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if enabled, ok := rule["enabled"].(bool); ok && !enabled {
+				continue
+			}
+			if host, ok := rule["host"].(string); ok && host != "" {
+				b.add(host, kindHost, inScope)
+			} else if u, ok := rule["url"].(string); ok && u != "" {
+				b.add(u, kindHost, inScope)
+			}
+		}
+	}
+}
+
+func walkJSON(v interface{}, ctx scopeContext, b *builder) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			walkJSON(item, ctx, b)
+		}
+
+	case string:
+		if ctx != scopeNone {
+			b.add(v, kindHost, ctx == scopeIn)
+		}
+
+	case map[string]interface{}:
+		// HackerOne structured scope
+		if id, ok := v["asset_identifier"].(string); ok {
+			if webAssetTypes[strings.ToLower(stringField(v, "asset_type"))] {
+				inScope := ctx != scopeOut
+				if eligible, ok := v["eligible_for_submission"].(bool); ok && !eligible {
+					inScope = false
+				}
+				b.add(id, kindHost, inScope)
+			}
+			return
+		}
+
+		if ctx != scopeNone {
+			if entry := firstField(v, entryColumns); entry != "" {
+				typ := stringField(v, "type")
+				if typ == "" {
+					typ = stringField(v, "category")
+				}
+				if webAssetTypes[strings.ToLower(typ)] {
+					b.add(entry, kindHost, ctx == scopeIn)
+				}
+				return
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := v[key]
+			switch strings.ToLower(key) {
+			case "in_scope", "inscope", "targets", "scope":
+				walkJSON(child, scopeIn, b)
+			case "out_of_scope", "outscope", "out_scope", "excluded":
+				walkJSON(child, scopeOut, b)
+			default:
+				if _, ok := child.(string); !ok {
+					walkJSON(child, ctx, b)
+				}
+			}
+		}
+	}
+}
+
+// isScopeCSV reports whether data starts with a CSV header naming an entry
+// column.
+func isScopeCSV(data []byte) bool {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if !bytes.Contains(header, []byte(",")) {
+		return false
+	}
+	return entryColumn(splitHeader(string(header))) >= 0
+}
+
+// parseCSV reads HackerOne and Bugcrowd CSV scope exports. Rows marked not
+// eligible / not in scope become exclusions.
+func parseCSV(data []byte, b *builder) error {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return fmt.Errorf("invalid CSV target file: %v", err)
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, h := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(h))
+	}
+	entryCol := entryColumn(header)
+	typeCol := columnIndex(header, "asset_type", "type", "category")
+	scopeCol := columnIndex(header, "eligible_for_submission", "in_scope")
+
+	for _, row := range rows[1:] {
+		if entryCol >= len(row) {
+			continue
+		}
+		if typeCol >= 0 && typeCol < len(row) && !webAssetTypes[strings.ToLower(strings.TrimSpace(row[typeCol]))] {
+			continue
+		}
+		inScope := true
+		if scopeCol >= 0 && scopeCol < len(row) {
+			switch strings.ToLower(strings.TrimSpace(row[scopeCol])) {
+			case "false", "no", "0", "n":
+				inScope = false
+			}
+		}
+		b.add(row[entryCol], kindHost, inScope)
+	}
+	return nil
+}
+
+func splitHeader(line string) []string {
+	fields := strings.Split(strings.TrimSpace(line), ",")
+	for i, f := range fields {
+		fields[i] = strings.ToLower(strings.Trim(strings.TrimSpace(f), `"`))
+	}
+	return fields
+}
+
+func entryColumn(header []string) int {
+	return columnIndex(header, entryColumns...)
+}
+
+// columnIndex returns the index of the first of names present in header.
+func columnIndex(header []string, names ...string) int {
+	for _, name := range names {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func firstField(obj map[string]interface{}, keys []string) string {
+	for _, k := range keys {
+		if s := stringField(obj, k); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func stringField(obj map[string]interface{}, key string) string {
+	s, _ := obj[key].(string)
+	return s
+}
@@ -0,0 +1,283 @@
+// Package input turns scan targets into apex domains to enumerate, explicit
+// hosts to probe and IP ranges. It reads plain domain lists, URL lists,
+// CIDRs, IPs and ASNs as well as HackerOne and Bugcrowd scope exports (CSV or
+// JSON) and Burp Suite target-scope JSON, detecting the format from content.
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// Targets is the normalized result of parsing one or more inputs.
+type Targets struct {
+	Apexes           []string // domains to enumerate
+	Hosts            []string // hosts to probe as given, never enumerated
+	Networks         []string // CIDRs to probe; single IPs become /32 or /128
+	ASNs             []string // "AS13335"; ExpandASNs turns these into Networks
+	Excluded         []string // out-of-scope host patterns from scope files
+	ExcludedNetworks []string // out-of-scope CIDRs from scope files
+	Skipped          []string // entries that could not be understood
+}
+
+// kind says how an entry without a wildcard is read: plain domain lists name
+// apexes to enumerate, scope files and URLs name individual hosts.
+type kind int
+
+const (
+	kindApex kind = iota
+	kindHost
+)
+
+var (
+	asnPattern = regexp.MustCompile(`(?i)^AS(\d+)$`)
+
+	// hostRegexPrefixes are the Burp host-regex forms meaning "any subdomain of".
+	hostRegexPrefixes = []string{`(.*\.)?`, `(.*\.)*`, `([a-z0-9-]+\.)*`, `.*\.`, `.*`}
+)
+
+// ParseFile reads a target file in any supported format.
+func ParseFile(path string) (*Targets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read targets: %v", err)
+	}
+	return Parse(data)
+}
+
+// ParseReader reads targets from r, e.g. stdin.
+func ParseReader(r io.Reader) (*Targets, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read targets: %v", err)
+	}
+	return Parse(data)
+}
+
+// ParseArgs reads command-line targets; bare domains are apexes.
+func ParseArgs(args []string) *Targets {
+	b := newBuilder()
+	for _, arg := range args {
+		for _, entry := range strings.Split(arg, ",") {
+			b.add(entry, kindApex, true)
+		}
+	}
+	return b.targets()
+}
+
+// Parse detects the format of data and extracts the targets from it.
+func Parse(data []byte) (*Targets, error) {
+	b := newBuilder()
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+
+	switch {
+	case len(trimmed) == 0:
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		if err := parseJSON(trimmed, b); err != nil {
+			return nil, err
+		}
+	case isScopeCSV(trimmed):
+		if err := parseCSV(trimmed, b); err != nil {
+			return nil, err
+		}
+	default:
+		for _, line := range strings.Split(string(trimmed), "\n") {
+			b.add(line, kindApex, true)
+		}
+	}
+	return b.targets(), nil
+}
+
+// Merge adds the targets of other to t, keeping entries unique.
+func (t *Targets) Merge(other *Targets) {
+	t.Apexes = appendUnique(t.Apexes, other.Apexes...)
+	t.Hosts = appendUnique(t.Hosts, other.Hosts...)
+	t.Networks = appendUnique(t.Networks, other.Networks...)
+	t.ASNs = appendUnique(t.ASNs, other.ASNs...)
+	t.Excluded = appendUnique(t.Excluded, other.Excluded...)
+	t.ExcludedNetworks = appendUnique(t.ExcludedNetworks, other.ExcludedNetworks...)
+	t.Skipped = append(t.Skipped, other.Skipped...)
+}
+
+// Addresses expands Networks into individual addresses. It fails rather than
+// silently truncating when the ranges hold more than limit addresses.
+func (t *Targets) Addresses(limit int) ([]string, error) {
+	total := new(big.Int)
+	nets := make([]*net.IPNet, 0, len(t.Networks))
+	for _, cidr := range t.Networks {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %s: %v", cidr, err)
+		}
+		ones, bits := n.Mask.Size()
+		total.Add(total, new(big.Int).Lsh(big.NewInt(1), uint(bits-ones)))
+		nets = append(nets, n)
+	}
+	if total.Cmp(big.NewInt(int64(limit))) > 0 {
+		return nil, fmt.Errorf("IP ranges cover %s addresses, more than the limit of %d; narrow the ranges", total, limit)
+	}
+
+	var addrs []string
+	seen := make(map[string]bool)
+	for _, n := range nets {
+		for ip := n.IP.Mask(n.Mask); n.Contains(ip); ip = nextIP(ip) {
+			if s := ip.String(); !seen[s] {
+				seen[s] = true
+				addrs = append(addrs, s)
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// builder collects entries, keeping each list unique and in input order.
+type builder struct {
+	t    Targets
+	seen map[string]bool
+}
+
+func newBuilder() *builder {
+	return &builder{seen: make(map[string]bool)}
+}
+
+func (b *builder) targets() *Targets {
+	t := b.t
+	return &t
+}
+
+func (b *builder) push(list *[]string, class, value string) {
+	key := class + "\x00" + value
+	if !b.seen[key] {
+		b.seen[key] = true
+		*list = append(*list, value)
+	}
+}
+
+// add classifies a single entry. bare says how a plain domain is read;
+// inScope false turns the entry into an exclusion.
+func (b *builder) add(raw string, bare kind, inScope bool) {
+	entry := strings.TrimSpace(raw)
+	if entry == "" || strings.HasPrefix(entry, "#") {
+		return
+	}
+
+	if m := asnPattern.FindStringSubmatch(entry); m != nil {
+		if inScope {
+			b.push(&b.t.ASNs, "asn", "AS"+m[1])
+		}
+		return
+	}
+
+	host := entry
+	if strings.Contains(host, "://") {
+		u, err := url.Parse(host)
+		if err != nil || u.Hostname() == "" {
+			b.t.Skipped = append(b.t.Skipped, entry)
+			return
+		}
+		host = u.Hostname()
+		if bare == kindApex {
+			bare = kindHost
+		}
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if network := parseNetwork(host); network != "" {
+		if inScope {
+			b.push(&b.t.Networks, "net", network)
+		} else {
+			b.push(&b.t.ExcludedNetworks, "xnet", network)
+		}
+		return
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(unescapeHostRegex(host)), ".")
+
+	k := bare
+	if rest, ok := strings.CutPrefix(host, "*."); ok {
+		host, k = rest, kindApex
+	}
+	if strings.Contains(host, "*") || utils.ValidateDomain(host) != nil {
+		b.t.Skipped = append(b.t.Skipped, entry)
+		return
+	}
+
+	switch {
+	case !inScope && k == kindApex:
+		b.push(&b.t.Excluded, "x", "*."+host)
+	case !inScope:
+		b.push(&b.t.Excluded, "x", host)
+	case k == kindApex:
+		b.push(&b.t.Apexes, "apex", host)
+	default:
+		b.push(&b.t.Hosts, "host", host)
+	}
+}
+
+// parseNetwork returns s in CIDR notation when it is a CIDR or an IP.
+func parseNetwork(s string) string {
+	if _, n, err := net.ParseCIDR(s); err == nil {
+		return n.String()
+	}
+	if ip := net.ParseIP(strings.Trim(s, "[]")); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.String() + "/32"
+		}
+		return ip.String() + "/128"
+	}
+	return ""
+}
+
+// unescapeHostRegex turns Burp-style host regexes such as ^.*\.example\.com$
+// into a wildcard or plain host. Other strings are returned unchanged.
+func unescapeHostRegex(s string) string {
+	if !strings.HasPrefix(s, "^") && !strings.HasSuffix(s, "$") && !strings.Contains(s, `\.`) {
+		return s
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "^"), "$")
+	for _, p := range hostRegexPrefixes {
+		if rest, ok := strings.CutPrefix(s, p); ok {
+			s = "*." + strings.TrimPrefix(rest, `\.`)
+			break
+		}
+	}
+	return strings.ReplaceAll(s, `\.`, ".")
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func appendUnique(list []string, values ...string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		seen[v] = true
+	}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,168 @@
+package input
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParsePlainList(t *testing.T) {
+	data := []byte(`# targets
+example.com
+*.example.org
+https://app.example.net:8443/login
+203.0.113.0/30
+198.51.100.7
+as13335
+not a domain
+`)
+	targets, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := &Targets{
+		Apexes:   []string{"example.com", "example.org"},
+		Hosts:    []string{"app.example.net"},
+		Networks: []string{"203.0.113.0/30", "198.51.100.7/32"},
+		ASNs:     []string{"AS13335"},
+		Skipped:  []string{"not a domain"},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Errorf("Parse() = %+v, want %+v", targets, want)
+	}
+}
+
+func TestParseHackerOneCSV(t *testing.T) {
+	data := []byte(`identifier,asset_type,instruction,eligible_for_bounty,eligible_for_submission
+*.example.com,WILDCARD,,true,true
+api.example.com,URL,,true,true
+com.example.app,GOOGLE_PLAY_APP_ID,,true,true
+legacy.example.com,URL,"old, unmaintained",false,false
+`)
+	targets, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(targets.Apexes, []string{"example.com"}) {
+		t.Errorf("Apexes = %v", targets.Apexes)
+	}
+	if !reflect.DeepEqual(targets.Hosts, []string{"api.example.com"}) {
+		t.Errorf("Hosts = %v", targets.Hosts)
+	}
+	if !reflect.DeepEqual(targets.Excluded, []string{"legacy.example.com"}) {
+		t.Errorf("Excluded = %v", targets.Excluded)
+	}
+}
+
+func TestParseHackerOneJSON(t *testing.T) {
+	data := []byte(`{"relationships":{"structured_scopes":{"data":[
+		{"attributes":{"asset_identifier":"*.example.com","asset_type":"WILDCARD","eligible_for_submission":true}},
+		{"attributes":{"asset_identifier":"10.0.0.0/24","asset_type":"CIDR","eligible_for_submission":true}},
+		{"attributes":{"asset_identifier":"*.corp.example.com","asset_type":"WILDCARD","eligible_for_submission":false}}
+	]}}}`)
+	targets, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(targets.Apexes, []string{"example.com"}) {
+		t.Errorf("Apexes = %v", targets.Apexes)
+	}
+	if !reflect.DeepEqual(targets.Networks, []string{"10.0.0.0/24"}) {
+		t.Errorf("Networks = %v", targets.Networks)
+	}
+	if !reflect.DeepEqual(targets.Excluded, []string{"*.corp.example.com"}) {
+		t.Errorf("Excluded = %v", targets.Excluded)
+	}
+}
+
+func TestParseBugcrowdJSON(t *testing.T) {
+	data := []byte(`{"target_groups":[
+		{"in_scope":[{"name":"Main site","uri":"https://www.example.com","category":"website"},
+		             {"name":"*.example.io","category":"website"},
+		             {"name":"iOS app","uri":"https://apps.apple.com/app/id1","category":"ios"}]},
+		{"out_of_scope":[{"name":"blog.example.com","category":"website"}]}
+	]}`)
+	targets, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(targets.Hosts, []string{"www.example.com"}) {
+		t.Errorf("Hosts = %v", targets.Hosts)
+	}
+	if !reflect.DeepEqual(targets.Apexes, []string{"example.io"}) {
+		t.Errorf("Apexes = %v", targets.Apexes)
+	}
+	if !reflect.DeepEqual(targets.Excluded, []string{"blog.example.com"}) {
+		t.Errorf("Excluded = %v", targets.Excluded)
+	}
+}
+
+func TestParseBurpScope(t *testing.T) {
+	data := []byte(`{"target":{"scope":{"advanced_mode":true,
+		"include":[
+			{"enabled":true,"host":"^.*\\.example\\.com$","protocol":"any"},
+			{"enabled":true,"host":"^shop\\.example\\.net$","protocol":"https"},
+			{"enabled":false,"host":"^disabled\\.example\\.org$"}
+		],
+		"exclude":[{"enabled":true,"host":"^status\\.example\\.com$"}]}}}`)
+	targets, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(targets.Apexes, []string{"example.com"}) {
+		t.Errorf("Apexes = %v", targets.Apexes)
+	}
+	if !reflect.DeepEqual(targets.Hosts, []string{"shop.example.net"}) {
+		t.Errorf("Hosts = %v", targets.Hosts)
+	}
+	if !reflect.DeepEqual(targets.Excluded, []string{"status.example.com"}) {
+		t.Errorf("Excluded = %v", targets.Excluded)
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	targets := &Targets{Networks: []string{"192.0.2.0/30", "192.0.2.2/32", "2001:db8::/127"}}
+
+	addrs, err := targets.Addresses(10)
+	if err != nil {
+		t.Fatalf("Addresses failed: %v", err)
+	}
+	want := []string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3", "2001:db8::", "2001:db8::1"}
+	if !reflect.DeepEqual(addrs, want) {
+		t.Errorf("Addresses = %v, want %v", addrs, want)
+	}
+
+	if _, err := targets.Addresses(4); err == nil {
+		t.Error("Expected error when ranges exceed the limit")
+	}
+}
+
+func TestExpandASNs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("resource") != "AS64500" {
+			http.Error(w, "unexpected resource", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"prefixes":[{"prefix":"192.0.2.0/24"},{"prefix":"2001:db8::/32"}]}}`))
+	}))
+	defer srv.Close()
+
+	orig := ripeStatURL
+	ripeStatURL = srv.URL + "/?resource="
+	defer func() { ripeStatURL = orig }()
+
+	targets := &Targets{ASNs: []string{"AS64500"}}
+	if err := targets.ExpandASNs(context.Background(), srv.Client()); err != nil {
+		t.Fatalf("ExpandASNs failed: %v", err)
+	}
+	if !reflect.DeepEqual(targets.Networks, []string{"192.0.2.0/24", "2001:db8::/32"}) {
+		t.Errorf("Networks = %v", targets.Networks)
+	}
+}
@@ -0,0 +1,217 @@
+package ipinfo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cloudRange is a published address range of a cloud or CDN provider.
+type cloudRange struct {
+	provider string
+	service  string
+	generic  bool // an aggregate such as AWS's "AMAZON", replaced by a more specific entry for the same prefix
+}
+
+// cloudParsers read the range files published by each provider, keyed by
+// the file's base name under the ranges directory.
+var cloudParsers = map[string]func(data []byte, add func(prefix string, r cloudRange)) error{
+	"aws":        parseAWSRanges,
+	"gcp":        parseGCPRanges,
+	"azure":      parseAzureRanges,
+	"cloudflare": parseCloudflareRanges,
+}
+
+// asnProviders names the provider behind well-known ASNs, for addresses
+// outside every published range file (Akamai publishes none).
+var asnProviders = map[uint32]string{
+	16509: "aws", 14618: "aws", 8987: "aws",
+	396982: "gcp", 15169: "google", 19527: "google",
+	8075: "microsoft", 8068: "microsoft", 8069: "microsoft",
+	13335: "cloudflare", 209242: "cloudflare",
+	20940: "akamai", 16625: "akamai", 16702: "akamai", 21342: "akamai", 12222: "akamai",
+	32787: "akamai", 33905: "akamai", 34164: "akamai", 35994: "akamai",
+	54113: "fastly",
+}
+
+// loadRanges reads every range file in dir into db. Files named after a
+// provider in cloudParsers use that provider's JSON format; any other
+// <provider>.txt file lists one CIDR per line.
+func (db *DB) loadRanges(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		ext := filepath.Ext(name)
+		provider := strings.ToLower(strings.TrimSuffix(name, ext))
+		path := filepath.Join(dir, name)
+
+		var err error
+		switch {
+		case ext == ".json" && cloudParsers[provider] != nil:
+			var data []byte
+			if data, err = os.ReadFile(path); err == nil {
+				err = cloudParsers[provider](data, db.addRange)
+			}
+		case ext == ".txt":
+			err = parseCIDRList(path, provider, db.addRange)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// addRange records r for prefix. A prefix already known keeps its first
+// entry unless that entry is a generic aggregate.
+func (db *DB) addRange(prefix string, r cloudRange) {
+	p, err := netip.ParsePrefix(strings.TrimSpace(prefix))
+	if err != nil {
+		return // providers occasionally publish malformed entries
+	}
+	p = p.Masked()
+	if db.clouds == nil {
+		db.clouds = make(map[netip.Prefix]cloudRange)
+	}
+	if old, ok := db.clouds[p]; ok && !(old.generic && !r.generic) {
+		return
+	}
+	db.clouds[p] = r
+}
+
+// findCloud returns the range of the longest published prefix holding addr.
+func (db *DB) findCloud(addr netip.Addr) (cloudRange, bool) {
+	for bits := addr.BitLen(); bits >= 0 && len(db.clouds) > 0; bits-- {
+		p, err := addr.Prefix(bits)
+		if err != nil {
+			break
+		}
+		if r, ok := db.clouds[p]; ok {
+			return r, true
+		}
+	}
+	return cloudRange{}, false
+}
+
+// parseAWSRanges reads https://ip-ranges.amazonaws.com/ip-ranges.json.
+func parseAWSRanges(data []byte, add func(string, cloudRange)) error {
+	var doc struct {
+		Prefixes []struct {
+			Prefix  string `json:"ip_prefix"`
+			Region  string `json:"region"`
+			Service string `json:"service"`
+		} `json:"prefixes"`
+		IPv6Prefixes []struct {
+			Prefix  string `json:"ipv6_prefix"`
+			Region  string `json:"region"`
+			Service string `json:"service"`
+		} `json:"ipv6_prefixes"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	for _, p := range doc.Prefixes {
+		add(p.Prefix, cloudRange{provider: "aws", service: p.Service, generic: p.Service == "AMAZON"})
+	}
+	for _, p := range doc.IPv6Prefixes {
+		add(p.Prefix, cloudRange{provider: "aws", service: p.Service, generic: p.Service == "AMAZON"})
+	}
+	return nil
+}
+
+// parseGCPRanges reads https://www.gstatic.com/ipranges/cloud.json.
+func parseGCPRanges(data []byte, add func(string, cloudRange)) error {
+	var doc struct {
+		Prefixes []struct {
+			IPv4  string `json:"ipv4Prefix"`
+			IPv6  string `json:"ipv6Prefix"`
+			Scope string `json:"scope"`
+		} `json:"prefixes"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	for _, p := range doc.Prefixes {
+		prefix := p.IPv4
+		if prefix == "" {
+			prefix = p.IPv6
+		}
+		add(prefix, cloudRange{provider: "gcp", service: p.Scope})
+	}
+	return nil
+}
+
+// parseAzureRanges reads the weekly ServiceTags_Public JSON file.
+func parseAzureRanges(data []byte, add func(string, cloudRange)) error {
+	var doc struct {
+		Values []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				AddressPrefixes []string `json:"addressPrefixes"`
+			} `json:"properties"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	for _, v := range doc.Values {
+		for _, prefix := range v.Properties.AddressPrefixes {
+			add(prefix, cloudRange{provider: "azure", service: v.Name, generic: v.Name == "AzureCloud"})
+		}
+	}
+	return nil
+}
+
+// parseCloudflareRanges reads https://api.cloudflare.com/client/v4/ips.
+func parseCloudflareRanges(data []byte, add func(string, cloudRange)) error {
+	var doc struct {
+		Result struct {
+			IPv4 []string `json:"ipv4_cidrs"`
+			IPv6 []string `json:"ipv6_cidrs"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	for _, prefix := range append(doc.Result.IPv4, doc.Result.IPv6...) {
+		add(prefix, cloudRange{provider: "cloudflare"})
+	}
+	return nil
+}
+
+// parseCIDRList reads a file of CIDRs or single IPs, one per line, all
+// operated by provider. Blank lines and # comments are skipped.
+func parseCIDRList(path, provider string, add func(string, cloudRange)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.Contains(line, "/") {
+			if addr, err := netip.ParseAddr(line); err == nil {
+				line = netip.PrefixFrom(addr, addr.BitLen()).String()
+			}
+		}
+		add(line, cloudRange{provider: provider})
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,126 @@
+package ipinfo
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// Filter selects addresses by provider, ASN and country. Within each list
+// a value prefixed with "!" excludes matching addresses and the others
+// require one of them to match; an address must pass every list.
+type Filter struct {
+	providers valueSet
+	asns      valueSet
+	countries valueSet
+}
+
+// valueSet holds the wanted and excluded values of one filter list.
+type valueSet struct {
+	include map[string]bool
+	exclude map[string]bool
+}
+
+// ParseFilter parses the comma-separated provider ("aws,!cloudflare"),
+// ASN ("AS13335,16509") and country ("US,!CN") lists. It returns nil when
+// all lists are empty.
+func ParseFilter(providers, asns, countries string) (*Filter, error) {
+	f := &Filter{}
+	f.providers = parseValues(providers, strings.ToLower)
+	f.countries = parseValues(countries, strings.ToUpper)
+
+	var err error
+	f.asns = parseValues(asns, func(s string) string {
+		n, e := ParseASN(s)
+		if e != nil && err == nil {
+			err = e
+		}
+		return strconv.FormatUint(uint64(n), 10)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if f.providers.empty() && f.asns.empty() && f.countries.empty() {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Match reports whether info passes the filter.
+func (f *Filter) Match(info types.IPInfo) bool {
+	asn := ""
+	if info.ASN != 0 {
+		asn = strconv.FormatUint(uint64(info.ASN), 10)
+	}
+	return f.providers.match(info.Provider) && f.asns.match(asn) && f.countries.match(info.Country)
+}
+
+// MatchAny reports whether any of infos passes the filter. Hosts without
+// tagged addresses only pass a filter made of exclusions.
+func (f *Filter) MatchAny(infos []types.IPInfo) bool {
+	for _, info := range infos {
+		if f.Match(info) {
+			return true
+		}
+	}
+	return len(infos) == 0 && len(f.providers.include) == 0 && len(f.asns.include) == 0 && len(f.countries.include) == 0
+}
+
+// FilterSubdomains returns the subdomains with an address that passes f.
+func (f *Filter) FilterSubdomains(results []types.SubdomainResult) []types.SubdomainResult {
+	var kept []types.SubdomainResult
+	for _, r := range results {
+		if f.MatchAny(r.IPInfo) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// FilterPorts returns the port results whose address passes f.
+func (f *Filter) FilterPorts(results []types.PortResult) []types.PortResult {
+	var kept []types.PortResult
+	for _, r := range results {
+		var infos []types.IPInfo
+		if r.IPInfo != nil {
+			infos = append(infos, *r.IPInfo)
+		}
+		if f.MatchAny(infos) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// parseValues splits a comma-separated list into a valueSet, normalising
+// each value with norm.
+func parseValues(list string, norm func(string) string) valueSet {
+	v := valueSet{include: make(map[string]bool), exclude: make(map[string]bool)}
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(s, "!"); ok {
+			v.exclude[norm(strings.TrimSpace(rest))] = true
+		} else {
+			v.include[norm(s)] = true
+		}
+	}
+	return v
+}
+
+func (v valueSet) empty() bool {
+	return len(v.include) == 0 && len(v.exclude) == 0
+}
+
+// match reports whether value passes v. An empty value only fails a set
+// with wanted values.
+func (v valueSet) match(value string) bool {
+	if v.exclude[value] {
+		return false
+	}
+	return len(v.include) == 0 || v.include[value]
+}
@@ -0,0 +1,91 @@
+package ipinfo
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// asnRange is one row of an ip2asn table: the addresses from start to end
+// inclusive are announced by asn.
+type asnRange struct {
+	start, end netip.Addr
+	asn        uint32
+	country    string
+	org        string
+}
+
+// loadIP2ASN reads an ip2asn TSV file, as published by iptoasn.com, and
+// returns its routed ranges sorted by start address. Files ending in .gz
+// are decompressed. Each line holds the range start, range end, AS number,
+// country code and AS description separated by tabs.
+func loadIP2ASN(path string) ([]asnRange, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+
+	var ranges []asnRange
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		start, err1 := netip.ParseAddr(fields[0])
+		end, err2 := netip.ParseAddr(fields[1])
+		asn, err3 := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(fields[2]), "AS"), 10, 32)
+		if err1 != nil || err2 != nil || err3 != nil {
+			return nil, fmt.Errorf("%s:%d: invalid ip2asn line", path, line)
+		}
+		if asn == 0 {
+			continue // "Not routed"
+		}
+		rng := asnRange{start: start.Unmap(), end: end.Unmap(), asn: uint32(asn)}
+		if len(fields) > 3 && fields[3] != "None" {
+			rng.country = strings.ToUpper(fields[3])
+		}
+		if len(fields) > 4 {
+			rng.org = strings.TrimSpace(fields[4])
+		}
+		ranges = append(ranges, rng)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start.Less(ranges[j].start) })
+	return ranges, nil
+}
+
+// findRange returns the range of ranges (sorted by start) that holds addr.
+func findRange(ranges []asnRange, addr netip.Addr) (asnRange, bool) {
+	i := sort.Search(len(ranges), func(i int) bool { return addr.Less(ranges[i].start) })
+	if i == 0 {
+		return asnRange{}, false
+	}
+	r := ranges[i-1]
+	if addr.BitLen() != r.start.BitLen() || r.end.Less(addr) {
+		return asnRange{}, false
+	}
+	return r, true
+}
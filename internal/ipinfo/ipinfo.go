@@ -0,0 +1,191 @@
+// Package ipinfo tags addresses with their origin network (ASN,
+// organisation and country) and the cloud or CDN provider operating them.
+// All lookups use data files kept on disk, so enriching a scan sends no
+// traffic; Update refreshes the files.
+//
+// The data directory holds an ip2asn table (ip2asn-*.tsv or .tsv.gz), any
+// MaxMind-format databases (*.mmdb, e.g. GeoLite2-ASN and GeoLite2-Country)
+// and a ranges/ directory with the providers' published range files.
+package ipinfo
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// DB answers lookups from the loaded data files.
+type DB struct {
+	tables     [][]asnRange // ip2asn tables, searched in order
+	mmdbs      []*mmdb
+	clouds     map[netip.Prefix]cloudRange
+	clientASNs map[uint32]bool
+}
+
+// DefaultDir returns the data directory used when none is configured.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(".", "ipdata")
+	}
+	return filepath.Join(dir, "subdomainx", "ipdata")
+}
+
+// Open loads the data files in dir and the extra databases in files, each
+// an ip2asn TSV or an .mmdb file. Addresses announced by one of clientASNs
+// ("AS64500" or "64500") are tagged with the provider "client" when no
+// cloud range holds them. It fails when no data is found at all.
+func Open(dir string, files, clientASNs []string) (*DB, error) {
+	db := &DB{clientASNs: make(map[uint32]bool)}
+	for _, asn := range clientASNs {
+		n, err := ParseASN(asn)
+		if err != nil {
+			return nil, err
+		}
+		db.clientASNs[n] = true
+	}
+
+	var paths []string
+	for _, pattern := range []string{"ip2asn*.tsv", "ip2asn*.tsv.gz", "*.mmdb"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		paths = append(paths, matches...)
+	}
+	paths = append(paths, files...)
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".mmdb") {
+			m, err := openMMDB(path)
+			if err != nil {
+				return nil, err
+			}
+			db.mmdbs = append(db.mmdbs, m)
+			continue
+		}
+		ranges, err := loadIP2ASN(path)
+		if err != nil {
+			return nil, err
+		}
+		db.tables = append(db.tables, ranges)
+	}
+	if err := db.loadRanges(filepath.Join(dir, "ranges")); err != nil {
+		return nil, err
+	}
+
+	if len(db.tables) == 0 && len(db.mmdbs) == 0 && len(db.clouds) == 0 {
+		return nil, fmt.Errorf("no IP data found in %s; run subdomainx --update-ip-data first", dir)
+	}
+	return db, nil
+}
+
+// Lookup returns what the data knows about ip. ok is false when ip is not
+// an address or no data file covers it.
+func (db *DB) Lookup(ip string) (info types.IPInfo, ok bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return types.IPInfo{}, false
+	}
+	addr = addr.Unmap()
+	info.IP = ip
+
+	for _, table := range db.tables {
+		if r, found := findRange(table, addr); found {
+			info.ASN, info.Org, info.Country = r.asn, r.org, r.country
+			break
+		}
+	}
+	for _, m := range db.mmdbs {
+		if rec, err := m.lookup(addr); err == nil && rec != nil {
+			fillFromMMDB(&info, rec)
+		}
+	}
+
+	if r, found := db.findCloud(addr); found {
+		info.Provider, info.Service = r.provider, r.service
+	} else if p := asnProviders[info.ASN]; p != "" {
+		info.Provider = p
+	} else if db.clientASNs[info.ASN] {
+		info.Provider = "client"
+	}
+
+	return info, len(info.Tags()) > 0
+}
+
+// EnrichSubdomains sets IPInfo of every subdomain from its IPs, in the same
+// order, leaving out addresses no data covers. It returns the number of
+// subdomains with at least one tagged address.
+func (db *DB) EnrichSubdomains(results []types.SubdomainResult) int {
+	tagged := 0
+	for i := range results {
+		results[i].IPInfo = nil
+		for _, ip := range results[i].IPs {
+			if info, ok := db.Lookup(ip); ok {
+				results[i].IPInfo = append(results[i].IPInfo, info)
+			}
+		}
+		if len(results[i].IPInfo) > 0 {
+			tagged++
+		}
+	}
+	return tagged
+}
+
+// EnrichPorts sets IPInfo of every port result from its IP.
+func (db *DB) EnrichPorts(results []types.PortResult) {
+	for i := range results {
+		results[i].IPInfo = nil
+		if info, ok := db.Lookup(results[i].IP); ok {
+			results[i].IPInfo = &info
+		}
+	}
+}
+
+// ParseASN parses "AS13335" or "13335".
+func ParseASN(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	n, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(s), "AS"), 10, 32)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid ASN %q", s)
+	}
+	return uint32(n), nil
+}
+
+// fillFromMMDB copies the fields of an MMDB record into the empty fields
+// of info. It understands the GeoLite2/DB-IP ASN and country layouts and
+// the flat IPinfo layout.
+func fillFromMMDB(info *types.IPInfo, rec map[string]any) {
+	if info.ASN == 0 {
+		if n := toUint(rec["autonomous_system_number"]); n != 0 {
+			info.ASN = uint32(n)
+		} else if s, ok := rec["asn"].(string); ok {
+			info.ASN, _ = ParseASN(s)
+		}
+	}
+	if info.Org == "" {
+		for _, key := range []string{"autonomous_system_organization", "as_name"} {
+			if s, ok := rec[key].(string); ok && s != "" {
+				info.Org = s
+				break
+			}
+		}
+	}
+	if info.Country == "" {
+		if s, ok := rec["country_code"].(string); ok {
+			info.Country = strings.ToUpper(s)
+		}
+		for _, key := range []string{"country", "registered_country"} {
+			if info.Country != "" {
+				break
+			}
+			if m, ok := rec[key].(map[string]any); ok {
+				if s, ok := m["iso_code"].(string); ok {
+					info.Country = strings.ToUpper(s)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package ipinfo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// mmdbPtr is a pointer to an offset in the data section, for buildMMDB.
+type mmdbPtr uint
+
+// encodeMMDB encodes strings, uint32s, maps and pointers in the MaxMind DB
+// data format.
+func encodeMMDB(v any) []byte {
+	header := func(typ, size int) []byte {
+		var b []byte
+		ctrl := byte(min(size, 29))
+		if typ <= 7 {
+			b = append(b, byte(typ<<5)|ctrl)
+		} else {
+			b = append(b, ctrl, byte(typ-7))
+		}
+		if size >= 29 {
+			b = append(b, byte(size-29))
+		}
+		return b
+	}
+	switch v := v.(type) {
+	case string:
+		return append(header(mmdbString, len(v)), v...)
+	case uint32:
+		b := binary.BigEndian.AppendUint32(nil, v)
+		for len(b) > 0 && b[0] == 0 {
+			b = b[1:]
+		}
+		return append(header(mmdbUint32, len(b)), b...)
+	case mmdbPtr:
+		return []byte{byte(mmdbPointer<<5) | byte(v>>8&0x7), byte(v)}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b := header(mmdbMap, len(v))
+		for _, k := range keys {
+			b = append(b, encodeMMDB(k)...)
+			b = append(b, encodeMMDB(v[k])...)
+		}
+		return b
+	}
+	panic("unsupported value")
+}
+
+// buildMMDB returns an IPv4 database with 24-bit records holding the data
+// at the given offsets (encoded in data) for each prefix.
+func buildMMDB(prefixes map[string]uint, data []byte) []byte {
+	// Children: 0 is empty, > 0 a node, < 0 the data offset -(offset+1)
+	nodes := [][2]int{{}}
+	for cidr, offset := range prefixes {
+		p := netip.MustParsePrefix(cidr)
+		ip := p.Addr().As4()
+		node := 0
+		for i := 0; i < p.Bits(); i++ {
+			bit := int(ip[i/8]>>(7-i%8)) & 1
+			if i == p.Bits()-1 {
+				nodes[node][bit] = -int(offset) - 1
+				break
+			}
+			if nodes[node][bit] <= 0 {
+				nodes = append(nodes, [2]int{})
+				nodes[node][bit] = len(nodes) - 1
+			}
+			node = nodes[node][bit]
+		}
+	}
+
+	count := len(nodes)
+	var out []byte
+	for _, n := range nodes {
+		for _, child := range n {
+			v := count // no data
+			if child > 0 {
+				v = child
+			} else if child < 0 {
+				v = count + 16 + (-child - 1)
+			}
+			out = append(out, byte(v>>16), byte(v>>8), byte(v))
+		}
+	}
+	out = append(out, make([]byte, 16)...)
+	out = append(out, data...)
+	out = append(out, mmdbMetadataMarker...)
+	return append(out, encodeMMDB(map[string]any{
+		"node_count":    uint32(count),
+		"record_size":   uint32(24),
+		"ip_version":    uint32(4),
+		"database_type": "Test-Country",
+	})...)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const (
+	testIP2ASN = "1.0.0.0\t1.0.0.255\t13335\tUS\tCLOUDFLARENET\n" +
+		"3.0.0.0\t3.127.255.255\t16509\tUS\tAMAZON-02\n" +
+		"23.0.0.0\t23.0.255.255\t20940\tNL\tAKAMAI-ASN1\n" +
+		"198.51.100.0\t198.51.100.255\t64500\tDE\tEXAMPLE-CORP\n" +
+		"203.0.113.0\t203.0.113.255\t0\tNone\tNot routed\n" +
+		"2001:db8::\t2001:db8:ffff:ffff:ffff:ffff:ffff:ffff\t64500\tDE\tEXAMPLE-CORP\n"
+
+	testAWSRanges = `{"prefixes": [
+		{"ip_prefix": "3.5.0.0/16", "region": "eu-west-1", "service": "AMAZON"},
+		{"ip_prefix": "3.5.0.0/16", "region": "eu-west-1", "service": "CLOUDFRONT"},
+		{"ip_prefix": "3.0.0.0/9", "region": "GLOBAL", "service": "AMAZON"}
+	], "ipv6_prefixes": []}`
+
+	testCloudflareRanges = `{"result": {"ipv4_cidrs": ["1.0.0.0/24"], "ipv6_cidrs": []}, "success": true}`
+)
+
+func TestOpenLookup(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ip2asn-combined.tsv"), testIP2ASN)
+	writeFile(t, filepath.Join(dir, "ranges", "aws.json"), testAWSRanges)
+	writeFile(t, filepath.Join(dir, "ranges", "cloudflare.json"), testCloudflareRanges)
+	writeFile(t, filepath.Join(dir, "ranges", "corp-vpn.txt"), "# office egress\n198.51.100.7\n")
+
+	// A country database for 203.0.113.0/24 and 198.51.100.0/24, whose
+	// second record points back at the first one's key.
+	first := encodeMMDB(map[string]any{"country": map[string]any{"iso_code": "jp"}})
+	keyAt := uint(bytes.Index(first, encodeMMDB("country")))
+	second := append([]byte{byte(mmdbMap<<5) | 1}, encodeMMDB(mmdbPtr(keyAt))...)
+	second = append(second, encodeMMDB(map[string]any{"iso_code": "FR"})...)
+	data := append(first, second...)
+	db := buildMMDB(map[string]uint{"203.0.113.0/24": 0, "198.51.100.0/24": uint(len(first))}, data)
+	writeFile(t, filepath.Join(dir, "country.mmdb"), string(db))
+
+	ipdb, err := Open(dir, nil, []string{"AS64500"})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"1.0.0.1", "1.0.0.1 (AS13335 CLOUDFLARENET, US, cloudflare)"},
+		{"3.5.1.1", "3.5.1.1 (AS16509 AMAZON-02, US, aws/CLOUDFRONT)"}, // specific service wins over AMAZON
+		{"3.1.1.1", "3.1.1.1 (AS16509 AMAZON-02, US, aws/AMAZON)"},     // longest prefix
+		{"23.0.0.1", "23.0.0.1 (AS20940 AKAMAI-ASN1, NL, akamai)"},     // provider from the ASN
+		{"198.51.100.1", "198.51.100.1 (AS64500 EXAMPLE-CORP, DE, client)"},
+		{"198.51.100.7", "198.51.100.7 (AS64500 EXAMPLE-CORP, DE, corp-vpn)"},
+		{"203.0.113.9", "203.0.113.9 (JP)"}, // not routed, country from the MMDB
+		{"2001:db8::1", "2001:db8::1 (AS64500 EXAMPLE-CORP, DE, client)"},
+	}
+	for _, tt := range tests {
+		info, ok := ipdb.Lookup(tt.ip)
+		if !ok || info.String() != tt.want {
+			t.Errorf("Lookup(%s) = %q, %v, want %q", tt.ip, info.String(), ok, tt.want)
+		}
+	}
+	if _, ok := ipdb.Lookup("192.0.2.1"); ok {
+		t.Error("Expected no data for an address outside every file")
+	}
+
+	subdomains := []types.SubdomainResult{
+		{Subdomain: "www.example.com", IPs: []string{"192.0.2.1", "1.0.0.1"}},
+		{Subdomain: "old.example.com"},
+	}
+	if n := ipdb.EnrichSubdomains(subdomains); n != 1 {
+		t.Errorf("Expected one tagged subdomain, got %d", n)
+	}
+	if got := types.FormatIPInfo(subdomains[0].IPInfo); got != "1.0.0.1 (AS13335 CLOUDFLARENET, US, cloudflare)" {
+		t.Errorf("Unexpected subdomain tags %q", got)
+	}
+
+	// The second record of the database resolves its key through the pointer
+	m, err := ipdb.mmdbs[0].lookup(netip.MustParseAddr("198.51.100.1"))
+	if err != nil || m["country"].(map[string]any)["iso_code"] != "FR" {
+		t.Errorf("Unexpected MMDB record %v, %v", m, err)
+	}
+}
+
+func TestOpenWithoutData(t *testing.T) {
+	if _, err := Open(t.TempDir(), nil, nil); err == nil || !strings.Contains(err.Error(), "--update-ip-data") {
+		t.Errorf("Expected an error pointing at --update-ip-data, got %v", err)
+	}
+	if _, err := Open(t.TempDir(), nil, []string{"ASX"}); err == nil {
+		t.Error("Expected an error for an invalid client ASN")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	cloudflare := types.IPInfo{IP: "1.0.0.1", ASN: 13335, Country: "US", Provider: "cloudflare"}
+	aws := types.IPInfo{IP: "3.5.1.1", ASN: 16509, Country: "US", Provider: "aws"}
+	corp := types.IPInfo{IP: "198.51.100.1", ASN: 64500, Country: "DE", Provider: "client"}
+
+	tests := []struct {
+		providers, asns, countries string
+		want                       []bool // cloudflare, aws, corp, no tags
+	}{
+		{"aws", "", "", []bool{false, true, false, false}},
+		{"!Cloudflare", "", "", []bool{false, true, true, true}},
+		{"", "AS64500,13335", "", []bool{true, false, true, false}},
+		{"", "", "us", []bool{true, true, false, false}},
+		{"!cloudflare", "", "US", []bool{false, true, false, false}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.providers, tt.asns, tt.countries)
+		if err != nil {
+			t.Fatalf("ParseFilter failed: %v", err)
+		}
+		var got []bool
+		for _, infos := range [][]types.IPInfo{{cloudflare}, {aws}, {corp}, nil} {
+			got = append(got, f.MatchAny(infos))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseFilter(%q, %q, %q) matches %v, want %v", tt.providers, tt.asns, tt.countries, got, tt.want)
+		}
+	}
+
+	if f, err := ParseFilter("", " ", ""); f != nil || err != nil {
+		t.Errorf("Expected no filter for empty lists, got %v, %v", f, err)
+	}
+	if _, err := ParseFilter("", "ASN1", ""); err == nil {
+		t.Error("Expected an error for an invalid ASN")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write([]byte(testIP2ASN))
+	_ = w.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ip2asn.tsv.gz", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(gz.Bytes()) })
+	mux.HandleFunc("/aws.json", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(testAWSRanges)) })
+	mux.HandleFunc("/cloudflare.json", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("<html>maintenance</html>")) })
+	mux.HandleFunc("/details", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<a href="https://download.microsoft.com/download/7/1/D/71D86715/ServiceTags_Public_20261012.json">`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	oldSources, oldPage := updateSources, azureDownloadPage
+	defer func() { updateSources, azureDownloadPage = oldSources, oldPage }()
+	updateSources = []struct {
+		file string
+		url  string
+	}{
+		{"ip2asn-combined.tsv.gz", srv.URL + "/ip2asn.tsv.gz"},
+		{"ranges/aws.json", srv.URL + "/aws.json"},
+		{"ranges/cloudflare.json", srv.URL + "/cloudflare.json"},
+	}
+	azureDownloadPage = srv.URL + "/details"
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ranges", "cloudflare.json"), testCloudflareRanges)
+
+	var logged []string
+	err := Update(context.Background(), srv.Client(), dir, func(format string, args ...any) {
+		logged = append(logged, format)
+	})
+	if err == nil || !strings.Contains(err.Error(), "ranges/cloudflare.json") {
+		t.Errorf("Expected the invalid Cloudflare download to be reported, got %v", err)
+	}
+	if len(logged) != 2 {
+		t.Errorf("Expected two updated files, got %d", len(logged))
+	}
+
+	// The bad download must not replace the previous copy
+	ipdb, err := Open(dir, nil, nil)
+	if err != nil {
+		t.Fatalf("Open after Update failed: %v", err)
+	}
+	if info, _ := ipdb.Lookup("1.0.0.1"); info.Provider != "cloudflare" {
+		t.Errorf("Expected the previous Cloudflare ranges to be kept, got %+v", info)
+	}
+	if info, _ := ipdb.Lookup("3.5.1.1"); info.Service != "CLOUDFRONT" || info.ASN != 16509 {
+		t.Errorf("Expected the downloaded ranges and table to be used, got %+v", info)
+	}
+
+	if url, err := azureFileURL(context.Background(), srv.Client()); err != nil || !strings.HasSuffix(url, "ServiceTags_Public_20261012.json") {
+		t.Errorf("azureFileURL = %q, %v", url, err)
+	}
+}
@@ -0,0 +1,304 @@
+package ipinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+)
+
+// mmdbMetadataMarker precedes the metadata map at the end of every
+// MaxMind DB file.
+var mmdbMetadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// mmdb is a reader for the MaxMind DB format, used by GeoLite2, DB-IP and
+// IPinfo databases. It decodes just enough of the format for lookups: the
+// search tree and the data types found in ASN and country databases.
+type mmdb struct {
+	data       []byte // search tree followed by the data section
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	dbType     string
+	ipv4Start  uint // node reached after the 96 leading zero bits of an IPv4-mapped address
+}
+
+// openMMDB reads the database at path into memory.
+func openMMDB(path string) (*mmdb, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	start := bytes.LastIndex(data, mmdbMetadataMarker)
+	if start < 0 {
+		return nil, fmt.Errorf("%s: not a MaxMind DB file", path)
+	}
+	start += len(mmdbMetadataMarker)
+
+	meta, _, err := (&decoder{data: data[start:]}).decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid metadata: %v", path, err)
+	}
+	m, _ := meta.(map[string]any)
+	db := &mmdb{
+		nodeCount:  uint(toUint(m["node_count"])),
+		recordSize: uint(toUint(m["record_size"])),
+		ipVersion:  uint(toUint(m["ip_version"])),
+	}
+	db.dbType, _ = m["database_type"].(string)
+	switch db.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("%s: unsupported record size %d", path, db.recordSize)
+	}
+	if db.ipVersion != 4 && db.ipVersion != 6 {
+		return nil, fmt.Errorf("%s: unsupported IP version %d", path, db.ipVersion)
+	}
+	treeSize := db.recordSize * 2 / 8 * db.nodeCount
+	if treeSize+16 > uint(start) {
+		return nil, fmt.Errorf("%s: search tree exceeds file size", path)
+	}
+	db.data = data[:start-len(mmdbMetadataMarker)]
+
+	if db.ipVersion == 6 {
+		for i := 0; i < 96 && db.ipv4Start < db.nodeCount; i++ {
+			db.ipv4Start = db.readNode(db.ipv4Start, 0)
+		}
+	}
+	return db, nil
+}
+
+// lookup returns the record for addr, or nil when the database has none.
+func (db *mmdb) lookup(addr netip.Addr) (map[string]any, error) {
+	addr = addr.Unmap()
+	var (
+		node uint
+		bits []byte
+	)
+	if addr.Is4() {
+		if db.ipVersion == 6 {
+			node = db.ipv4Start
+		}
+		b := addr.As4()
+		bits = b[:]
+	} else {
+		if db.ipVersion == 4 {
+			return nil, nil
+		}
+		b := addr.As16()
+		bits = b[:]
+	}
+
+	for i := 0; i < len(bits)*8 && node < db.nodeCount; i++ {
+		bit := uint(bits[i/8]>>(7-i%8)) & 1
+		node = db.readNode(node, bit)
+	}
+	if node <= db.nodeCount {
+		return nil, nil // node_count itself marks "no data"
+	}
+
+	treeSize := db.recordSize * 2 / 8 * db.nodeCount
+	offset := node - db.nodeCount - 16
+	d := &decoder{data: db.data[treeSize+16:]}
+	v, _, err := d.decode(offset)
+	if err != nil {
+		return nil, err
+	}
+	m, _ := v.(map[string]any)
+	return m, nil
+}
+
+// readNode returns the left (bit 0) or right (bit 1) record of node.
+func (db *mmdb) readNode(node, bit uint) uint {
+	size := db.recordSize * 2 / 8
+	b := db.data[node*size : (node+1)*size]
+	switch db.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]>>4)<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default: // 32
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+// decoder decodes values of the MaxMind DB data section. Pointers are
+// offsets into data.
+type decoder struct {
+	data []byte
+}
+
+// Data section field types.
+const (
+	mmdbExtended = iota
+	mmdbPointer
+	mmdbString
+	mmdbDouble
+	mmdbBytes
+	mmdbUint16
+	mmdbUint32
+	mmdbMap
+	mmdbInt32
+	mmdbUint64
+	mmdbUint128
+	mmdbArray
+	mmdbContainer
+	mmdbEndMarker
+	mmdbBool
+	mmdbFloat
+)
+
+// decode returns the value at offset and the offset just after it.
+func (d *decoder) decode(offset uint) (any, uint, error) {
+	if offset >= uint(len(d.data)) {
+		return nil, 0, fmt.Errorf("offset %d out of range", offset)
+	}
+	ctrl := d.data[offset]
+	offset++
+	typ := uint(ctrl >> 5)
+
+	if typ == mmdbPointer {
+		ptr, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		v, _, err := d.decode(ptr)
+		return v, next, err
+	}
+	if typ == mmdbExtended {
+		if offset >= uint(len(d.data)) {
+			return nil, 0, fmt.Errorf("truncated extended type")
+		}
+		typ = 7 + uint(d.data[offset])
+		offset++
+	}
+
+	size := uint(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(d.data)) {
+			return nil, 0, fmt.Errorf("truncated size")
+		}
+		extra := uint(0)
+		for _, b := range d.data[offset : offset+n] {
+			extra = extra<<8 | uint(b)
+		}
+		offset += n
+		switch n {
+		case 1:
+			size = 29 + extra
+		case 2:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+
+	switch typ {
+	case mmdbMap:
+		m := make(map[string]any, size)
+		for i := uint(0); i < size; i++ {
+			k, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			v, next, err := d.decode(next)
+			if err != nil {
+				return nil, 0, err
+			}
+			key, _ := k.(string)
+			m[key] = v
+			offset = next
+		}
+		return m, offset, nil
+	case mmdbArray:
+		a := make([]any, 0, size)
+		for i := uint(0); i < size; i++ {
+			v, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, v)
+			offset = next
+		}
+		return a, offset, nil
+	case mmdbBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.data)) {
+		return nil, 0, fmt.Errorf("value at %d exceeds data section", offset)
+	}
+	b := d.data[offset : offset+size]
+	offset += size
+	switch typ {
+	case mmdbString:
+		return string(b), offset, nil
+	case mmdbBytes, mmdbUint128:
+		return b, offset, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), offset, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), offset, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, offset, nil
+	case mmdbInt32:
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return int64(int32(n)), offset, nil
+	}
+	return nil, offset, nil // containers and end markers carry no lookup data
+}
+
+// pointer returns the target of the pointer whose control byte is ctrl
+// and the offset just after it.
+func (d *decoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	n := uint(ctrl>>3&0x3) + 1
+	if offset+n > uint(len(d.data)) {
+		return 0, 0, fmt.Errorf("truncated pointer")
+	}
+	b := d.data[offset : offset+n]
+	v := uint(ctrl & 0x7)
+	switch n {
+	case 1:
+		v = v<<8 | uint(b[0])
+	case 2:
+		v = (v<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		v = (v<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		v = uint(binary.BigEndian.Uint32(b))
+	}
+	return v, offset + n, nil
+}
+
+// toUint returns v as an unsigned integer, or 0 when it is not one.
+func toUint(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		if n > 0 {
+			return uint64(n)
+		}
+	}
+	return 0
+}
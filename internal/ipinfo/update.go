@@ -0,0 +1,137 @@
+package ipinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// updateSources are the files Update downloads, by path under the data
+// directory. Variables so tests can point them at a local server.
+var updateSources = []struct {
+	file string
+	url  string
+}{
+	{"ip2asn-combined.tsv.gz", "https://iptoasn.com/data/ip2asn-combined.tsv.gz"},
+	{"ranges/aws.json", "https://ip-ranges.amazonaws.com/ip-ranges.json"},
+	{"ranges/gcp.json", "https://www.gstatic.com/ipranges/cloud.json"},
+	{"ranges/cloudflare.json", "https://api.cloudflare.com/client/v4/ips"},
+	{"ranges/azure.json", ""}, // the URL changes weekly, see azureDownloadPage
+}
+
+// azureDownloadPage links to the current Azure service tags file.
+var azureDownloadPage = "https://www.microsoft.com/en-us/download/details.aspx?id=56519"
+
+var azureFileRe = regexp.MustCompile(`https://download\.microsoft\.com/download/[^"'\s]+/ServiceTags_Public_\d+\.json`)
+
+// Update downloads the ip2asn table and the published cloud ranges into
+// dir, replacing each file only once its new copy has been read back
+// successfully. It reports progress through logf and carries on past
+// failed downloads, returning their errors together. MMDB files are left
+// alone, as their licences require an account to download them.
+func Update(ctx context.Context, client *http.Client, dir string, logf func(format string, args ...any)) error {
+	var errs []error
+	for _, src := range updateSources {
+		url := src.url
+		if url == "" {
+			var err error
+			if url, err = azureFileURL(ctx, client); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", src.file, err))
+				continue
+			}
+		}
+		path := filepath.Join(dir, filepath.FromSlash(src.file))
+		if err := download(ctx, client, url, path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", src.file, err))
+			continue
+		}
+		logf("Updated %s from %s", path, url)
+	}
+	return errors.Join(errs...)
+}
+
+// azureFileURL finds the service tags file linked from azureDownloadPage.
+func azureFileURL(ctx context.Context, client *http.Client) (string, error) {
+	body, err := fetch(ctx, client, azureDownloadPage)
+	if err != nil {
+		return "", err
+	}
+	url := azureFileRe.FindString(string(body))
+	if url == "" {
+		return "", fmt.Errorf("no service tags link on %s", azureDownloadPage)
+	}
+	return url, nil
+}
+
+// download fetches url into path through a temporary file, which is
+// validated with the same loader scans use before it replaces path.
+func download(ctx context.Context, client *http.Client, url, path string) error {
+	body, err := fetch(ctx, client, url)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Keep the suffix, which tells the loaders how to read the file
+	tmp := filepath.Join(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err := os.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+	if err := validate(tmp, filepath.Base(path)); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("downloaded file is invalid: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// validate loads the downloaded file at path as the data file name.
+func validate(path, name string) error {
+	if strings.HasPrefix(name, "ip2asn") {
+		ranges, err := loadIP2ASN(path)
+		if err == nil && len(ranges) == 0 {
+			err = errors.New("no ranges")
+		}
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	count := 0
+	parse := cloudParsers[strings.TrimSuffix(name, filepath.Ext(name))]
+	if err := parse(data, func(string, cloudRange) { count++ }); err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("no ranges")
+	}
+	return nil
+}
+
+// fetch returns the body of a successful GET of url.
+func fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "SubdomainX/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
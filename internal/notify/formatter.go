@@ -2,11 +2,18 @@ package notify
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
 )
 
 const maxListItems = 10
 
+// severityOrder lists finding severities from most to least urgent.
+var severityOrder = []string{types.SeverityHigh, types.SeverityMedium, types.SeverityLow, types.SeverityInfo}
+
 // FormatMarkdown formats a scan summary as Markdown (for Slack/Discord).
 func FormatMarkdown(s ScanSummary) string {
 	var b strings.Builder
@@ -28,6 +35,11 @@ func FormatMarkdown(s ScanSummary) string {
 	}
 	b.WriteString("\n")
 
+	if len(s.Findings) > 0 {
+		b.WriteString("\n")
+		formatFindings(&b, s.Findings, "**Findings:** %s\n", "%s `%s` %s\n")
+	}
+
 	if s.Diff != nil {
 		b.WriteString("\n")
 		formatDiffMarkdown(&b, s)
@@ -57,6 +69,11 @@ func FormatPlainText(s ScanSummary) string {
 	}
 	b.WriteString("\n")
 
+	if len(s.Findings) > 0 {
+		b.WriteString("\n")
+		formatFindings(&b, s.Findings, "Findings: %s\n", "  [%s] %s: %s\n")
+	}
+
 	if s.Diff != nil {
 		b.WriteString("\n")
 		formatDiffPlainText(&b, s)
@@ -87,6 +104,16 @@ func formatDiffMarkdown(b *strings.Builder, s ScanSummary) {
 			fmt.Fprintf(b, "~ `%s` (%s -> %s)\n", c.Subdomain, strings.Join(c.OldIPs, ","), strings.Join(c.NewIPs, ","))
 		}
 	}
+	if len(d.DNSChanges) > 0 {
+		b.WriteString("\nDNS record changes:\n")
+		for i, c := range d.DNSChanges {
+			if i >= maxListItems {
+				fmt.Fprintf(b, "  ...and %d more\n", len(d.DNSChanges)-maxListItems)
+				break
+			}
+			fmt.Fprintf(b, "~ `%s` %s (%s -> %s)\n", c.Subdomain, c.Type, strings.Join(c.Old, ","), strings.Join(c.New, ","))
+		}
+	}
 }
 
 func formatDiffPlainText(b *strings.Builder, s ScanSummary) {
@@ -111,6 +138,47 @@ func formatDiffPlainText(b *strings.Builder, s ScanSummary) {
 			fmt.Fprintf(b, "  ~ %s (%s -> %s)\n", c.Subdomain, strings.Join(c.OldIPs, ","), strings.Join(c.NewIPs, ","))
 		}
 	}
+	if len(d.DNSChanges) > 0 {
+		b.WriteString("\nDNS record changes:\n")
+		for i, c := range d.DNSChanges {
+			if i >= maxListItems {
+				fmt.Fprintf(b, "  ...and %d more\n", len(d.DNSChanges)-maxListItems)
+				break
+			}
+			fmt.Fprintf(b, "  ~ %s %s (%s -> %s)\n", c.Subdomain, c.Type, strings.Join(c.Old, ","), strings.Join(c.New, ","))
+		}
+	}
+}
+
+// formatFindings writes the number of findings per severity, then the
+// findings above info severity, most urgent first.
+func formatFindings(b *strings.Builder, findings []types.Finding, header, item string) {
+	counts := make(map[string]int)
+	var listed []types.Finding
+	for _, f := range findings {
+		counts[f.Severity]++
+		if f.Severity != types.SeverityInfo {
+			listed = append(listed, f)
+		}
+	}
+	var parts []string
+	for _, sev := range severityOrder {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+		}
+	}
+	fmt.Fprintf(b, header, strings.Join(parts, ", "))
+
+	sort.SliceStable(listed, func(i, j int) bool {
+		return slices.Index(severityOrder, listed[i].Severity) < slices.Index(severityOrder, listed[j].Severity)
+	})
+	for i, f := range listed {
+		if i >= maxListItems {
+			fmt.Fprintf(b, "  ...and %d more\n", len(listed)-maxListItems)
+			break
+		}
+		fmt.Fprintf(b, item, f.Severity, f.Target, f.Title)
+	}
 }
 
 func writeList(b *strings.Builder, items []string, format string) {
@@ -2,10 +2,12 @@ package notify
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/diff"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
 )
 
 // ScanSummary is the payload delivered to every notifier.
@@ -18,6 +20,7 @@ type ScanSummary struct {
 	Duration        time.Duration
 	Error           string           // non-empty if scan failed
 	Diff            *diff.DiffResult // nil when --diff not used
+	Findings        []types.Finding
 }
 
 // Notifier sends a scan summary to one notification channel.
@@ -33,12 +36,13 @@ func Register(n Notifier) {
 	notifiers[n.Name()] = n
 }
 
-// ValidChannels returns the list of supported channel names.
+// ValidChannels returns the sorted list of supported channel names.
 func ValidChannels() []string {
 	channels := make([]string, 0, len(notifiers))
 	for name := range notifiers {
 		channels = append(channels, name)
 	}
+	sort.Strings(channels)
 	return channels
 }
 
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/plugin"
+)
+
+// pluginSendTimeout bounds how long a notifier plugin may take.
+const pluginSendTimeout = 30 * time.Second
+
+// PluginNotifier delivers scan summaries through an out-of-process plugin
+// that declared the notifier capability.
+type PluginNotifier struct {
+	plugin *plugin.Plugin
+}
+
+// RegisterPlugin registers p as a notification channel named after it.
+func RegisterPlugin(p *plugin.Plugin) {
+	Register(&PluginNotifier{plugin: p})
+}
+
+func (n *PluginNotifier) Name() string {
+	return n.plugin.Name
+}
+
+func (n *PluginNotifier) Send(summary ScanSummary) error {
+	payload := &plugin.Summary{
+		ScanID:          summary.ScanID,
+		Domain:          summary.Domain,
+		TotalSubdomains: summary.TotalSubdomains,
+		TotalHTTP:       summary.TotalHTTP,
+		TotalPorts:      summary.TotalPorts,
+		DurationSeconds: summary.Duration.Seconds(),
+		Error:           summary.Error,
+		Findings:        summary.Findings,
+	}
+	if summary.Diff != nil {
+		payload.Added = summary.Diff.Added
+		payload.Removed = summary.Diff.Removed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginSendTimeout)
+	defer cancel()
+	if err := n.plugin.Call(ctx, plugin.Message{Type: plugin.TypeNotify, Summary: payload}, func(plugin.Message) {}); err != nil {
+		return fmt.Errorf("%s plugin failed: %v", n.plugin.Name, err)
+	}
+	return nil
+}
@@ -36,6 +36,8 @@ type BurpReport struct {
 // WriteBurp creates a Burp Suite-compatible XML file
 func WriteBurp(filename string, results *types.ScanResults) error {
 	var burpItems []BurpItem
+	records := dnsRecordsByHost(results.Subdomains)
+	ipInfo := ipInfoByHost(results.Subdomains)
 
 	// Convert HTTP results to Burp items
 	for _, http := range results.HTTP {
@@ -54,7 +56,7 @@ func WriteBurp(filename string, results *types.ScanResults) error {
 			Status:      fmt.Sprintf("%d", http.StatusCode),
 			Response:    generateResponse(http),
 			ResponseURL: http.URL,
-			Comments:    generateComments(http),
+			Comments:    generateComments(http, records[host], ipInfo[host]),
 		}
 		burpItems = append(burpItems, item)
 	}
@@ -146,8 +148,9 @@ func generateResponse(http types.HTTPResult) string {
 	return response
 }
 
-// generateComments creates comments from HTTP result metadata
-func generateComments(http types.HTTPResult) string {
+// generateComments creates comments from HTTP result metadata and the DNS
+// records and IP tags of its host, which may be nil
+func generateComments(http types.HTTPResult, records *types.DNSRecords, ipInfo []types.IPInfo) string {
 	var comments []string
 
 	if http.Title != "" {
@@ -162,6 +165,14 @@ func generateComments(http types.HTTPResult) string {
 		comments = append(comments, fmt.Sprintf("Content-Length: %d", http.ContentLength))
 	}
 
+	if records != nil {
+		comments = append(comments, "DNS: "+records.String())
+	}
+
+	if len(ipInfo) > 0 {
+		comments = append(comments, "IP: "+types.FormatIPInfo(ipInfo))
+	}
+
 	if len(comments) > 0 {
 		return fmt.Sprintf("SubdomainX: %s", fmt.Sprintf("%s", comments))
 	}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/types"
 )
@@ -37,6 +38,12 @@ func WriteCSV(filename string, results *types.ScanResults) error {
 		"Service",
 		"State",
 		"Version",
+		"Source Types",
+		"Confidence",
+		"First Seen",
+		"Wildcard",
+		"DNS Records",
+		"IP Info",
 	}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %v", err)
@@ -59,6 +66,12 @@ func WriteCSV(filename string, results *types.ScanResults) error {
 			"", // Service
 			"", // State
 			"", // Version
+			strings.Join(subdomain.SourceTypes(), ","),
+			formatConfidence(subdomain),
+			formatFirstSeen(subdomain),
+			formatWildcard(subdomain),
+			subdomain.DNS.String(),
+			types.FormatIPInfo(subdomain.IPInfo),
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write subdomain row: %v", err)
@@ -84,6 +97,12 @@ func WriteCSV(filename string, results *types.ScanResults) error {
 			"http",
 			"open",
 			"", // Version
+			"", // Source Types
+			"", // Confidence
+			"", // First Seen
+			"", // Wildcard
+			"", // DNS Records
+			"", // IP Info
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write HTTP row: %v", err)
@@ -108,6 +127,12 @@ func WriteCSV(filename string, results *types.ScanResults) error {
 				port.Service,
 				port.State,
 				port.Version,
+				"", // Source Types
+				"", // Confidence
+				"", // First Seen
+				"", // Wildcard
+				"", // DNS Records
+				portIPInfo(portResult),
 			}
 			if err := writer.Write(row); err != nil {
 				return fmt.Errorf("failed to write port row: %v", err)
@@ -132,15 +157,79 @@ func WriteCSV(filename string, results *types.ScanResults) error {
 			t.Service,
 			t.Evidence, // State (reuse column for Evidence)
 			"",         // Version
+			"",         // Source Types
+			"",         // Confidence
+			"",         // First Seen
+			"",         // Wildcard
+			"",         // DNS Records
+			"",         // IP Info
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write takeover row: %v", err)
 		}
 	}
 
+	// Write findings
+	for _, f := range results.Findings {
+		row := []string{
+			"Finding",
+			f.Target,
+			"",         // URL
+			"",         // IP
+			"",         // Port
+			"",         // Protocol
+			"",         // Status Code
+			f.Title,    // Title
+			"",         // Technologies
+			"",         // Content Length
+			f.Severity, // Source (reuse column for Severity)
+			f.Type,     // Service (reuse column for Type)
+			f.Evidence, // State (reuse column for Evidence)
+			"",         // Version
+			"",         // Source Types
+			"",         // Confidence
+			"",         // First Seen
+			"",         // Wildcard
+			"",         // DNS Records
+			"",         // IP Info
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write finding row: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// formatConfidence returns the result's confidence score, scoring results
+// that were never scored (e.g. loaded from an older checkpoint).
+func formatConfidence(r types.SubdomainResult) string {
+	if r.Confidence == 0 {
+		r.Score()
+	}
+	return strconv.FormatFloat(r.Confidence, 'f', 2, 64)
+}
+
+// formatFirstSeen returns when the result was first reported, or "" if unknown.
+func formatFirstSeen(r types.SubdomainResult) string {
+	if t := r.FirstSeen(); !t.IsZero() {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return ""
+}
+
+// formatWildcard returns the zone whose wildcard record the result matched
+// (flag mode), or "" for a genuine host.
+func formatWildcard(r types.SubdomainResult) string {
+	if !r.Wildcard {
+		return ""
+	}
+	if r.WildcardZone != "" {
+		return "*." + r.WildcardZone
+	}
+	return "true"
+}
+
 // joinStrings joins a slice of strings with commas
 func joinStrings(strs []string) string {
 	return strings.Join(strs, ", ")
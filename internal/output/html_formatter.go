@@ -69,6 +69,10 @@ type reportData struct {
 	TakeoverData  template.JS // [{subdomain, cname, risk, service, evidence}]
 	TakeoverCount int
 	HasTakeover   bool
+	// Findings data
+	FindingsData  template.JS // [{type, severity, target, title, evidence, details}]
+	FindingsCount int
+	HasFindings   bool
 	// Logo
 	LogoDataURI template.URL // base64 data URI or empty
 }
@@ -134,6 +138,9 @@ func WriteHTML(filename string, cfg *config.Config, results *types.ScanResults,
 		TakeoverData:    marshalJS(results.Takeover),
 		TakeoverCount:   len(results.Takeover),
 		HasTakeover:     len(results.Takeover) > 0,
+		FindingsData:    marshalJS(results.Findings),
+		FindingsCount:   len(results.Findings),
+		HasFindings:     len(results.Findings) > 0,
 		LogoDataURI:     logoDataURI,
 	}
 
@@ -190,10 +197,17 @@ func extractParent(subdomain string) string {
 // embedding inside a <script> block.
 func buildSubdomainRows(subdomains []types.SubdomainResult) template.JS {
 	type row struct {
-		Subdomain string `json:"subdomain"`
-		Parent    string `json:"parent"`
-		Source    string `json:"source"`
-		IPs       string `json:"ips"`
+		Subdomain   string   `json:"subdomain"`
+		Parent      string   `json:"parent"`
+		Source      string   `json:"source"`
+		SourceTypes string   `json:"sourceTypes"`
+		Confidence  float64  `json:"confidence"`
+		IPs         string   `json:"ips"`
+		Wildcard    string   `json:"wildcard"` // matched wildcard zone, flag mode only
+		DNS         []string `json:"dns"`      // "TYPE value" lines, with --dns-records
+		IPInfo      []string `json:"ipInfo"`   // one line per tagged IP, with --ip-enrich
+		Providers   []string `json:"providers"`
+		Countries   []string `json:"countries"`
 	}
 	rows := make([]row, 0, len(subdomains))
 	for _, s := range subdomains {
@@ -201,11 +215,25 @@ func buildSubdomainRows(subdomains []types.SubdomainResult) template.JS {
 		if ips == "" {
 			ips = "N/A"
 		}
+		if s.Confidence == 0 {
+			s.Score()
+		}
+		wildcard := ""
+		if s.Wildcard {
+			wildcard = "*." + s.WildcardZone
+		}
 		rows = append(rows, row{
-			Subdomain: s.Subdomain,
-			Parent:    extractParent(s.Subdomain),
-			Source:    s.Source,
-			IPs:       ips,
+			Subdomain:   s.Subdomain,
+			Parent:      extractParent(s.Subdomain),
+			Source:      s.Source,
+			SourceTypes: strings.Join(s.SourceTypes(), ", "),
+			Confidence:  s.Confidence,
+			IPs:         ips,
+			Wildcard:    wildcard,
+			DNS:         s.DNS.Lines(),
+			IPInfo:      ipInfoLines(s.IPInfo),
+			Providers:   types.Providers(s.IPInfo),
+			Countries:   types.Countries(s.IPInfo),
 		})
 	}
 	return marshalJS(rows)
@@ -263,31 +291,51 @@ func buildHTTPRows(httpResults []types.HTTPResult) template.JS {
 // inside a <script> block.
 func buildPortRows(portResults []types.PortResult) template.JS {
 	type row struct {
-		Host     string `json:"host"`
-		IP       string `json:"ip"`
-		Port     int    `json:"port"`
-		Protocol string `json:"protocol"`
-		State    string `json:"state"`
-		Service  string `json:"service"`
-		Version  string `json:"version"`
+		Host      string   `json:"host"`
+		IP        string   `json:"ip"`
+		Port      int      `json:"port"`
+		Protocol  string   `json:"protocol"`
+		State     string   `json:"state"`
+		Service   string   `json:"service"`
+		Version   string   `json:"version"`
+		IPInfo    string   `json:"ipInfo"`
+		Providers []string `json:"providers"`
+		Countries []string `json:"countries"`
 	}
 	var rows []row
 	for _, pr := range portResults {
+		var infos []types.IPInfo
+		if pr.IPInfo != nil {
+			infos = append(infos, *pr.IPInfo)
+		}
 		for _, p := range pr.Ports {
 			rows = append(rows, row{
-				Host:     pr.Host,
-				IP:       pr.IP,
-				Port:     p.Number,
-				Protocol: p.Protocol,
-				State:    p.State,
-				Service:  p.Service,
-				Version:  p.Version,
+				Host:      pr.Host,
+				IP:        pr.IP,
+				Port:      p.Number,
+				Protocol:  p.Protocol,
+				State:     p.State,
+				Service:   p.Service,
+				Version:   p.Version,
+				IPInfo:    strings.Join(ipInfoLines(infos), ""),
+				Providers: types.Providers(infos),
+				Countries: types.Countries(infos),
 			})
 		}
 	}
 	return marshalJS(rows)
 }
 
+// ipInfoLines returns the tags of each address as one line, e.g.
+// "1.0.0.1: AS13335 CLOUDFLARENET, US, cloudflare".
+func ipInfoLines(infos []types.IPInfo) []string {
+	var lines []string
+	for _, info := range infos {
+		lines = append(lines, info.IP+": "+strings.Join(info.Tags(), ", "))
+	}
+	return lines
+}
+
 // marshalJS marshals v to JSON and returns it as template.JS, escaping any
 // "</script>" sequence to prevent breaking out of the enclosing script block.
 func marshalJS(v any) template.JS {
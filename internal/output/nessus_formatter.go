@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/types"
 )
@@ -87,6 +88,13 @@ func WriteNessus(filename string, results *types.ScanResults) error {
 		host := extractHost(http.URL)
 		hostGroups[host] = append(hostGroups[host], http)
 	}
+	records := dnsRecordsByHost(results.Subdomains)
+	ipInfo := ipInfoByHost(results.Subdomains)
+	for _, s := range results.Subdomains {
+		if _, ok := hostGroups[s.Subdomain]; !ok && (records[s.Subdomain] != nil || ipInfo[s.Subdomain] != nil) {
+			hostGroups[s.Subdomain] = nil // hosts without web services still report their records
+		}
+	}
 
 	// Create Nessus hosts
 	var nessusHosts []NessusHost
@@ -116,6 +124,14 @@ func WriteNessus(filename string, results *types.ScanResults) error {
 			items = append(items, item)
 		}
 
+		if r := records[host]; r != nil {
+			items = append(items, dnsRecordsItem(host, r))
+		}
+
+		if infos := ipInfo[host]; infos != nil {
+			items = append(items, ipInfoItem(host, infos))
+		}
+
 		host := NessusHost{
 			Name:  host,
 			Items: items,
@@ -154,6 +170,53 @@ func WriteNessus(filename string, results *types.ScanResults) error {
 	return nil
 }
 
+// dnsRecordsItem creates the informational item listing a host's DNS records
+func dnsRecordsItem(host string, records *types.DNSRecords) NessusItem {
+	return NessusItem{
+		Port:          "53",
+		SvcName:       "dns",
+		Protocol:      "udp",
+		Severity:      "Info",
+		PluginID:      "99998", // Custom plugin ID for SubdomainX DNS records
+		PluginName:    "SubdomainX - DNS Records",
+		PluginFamily:  "SubdomainX",
+		PluginType:    "remote",
+		PluginVersion: "1.0",
+		RiskFactor:    "None",
+		Synopsis:      fmt.Sprintf("DNS records collected for %s", host),
+		Description:   fmt.Sprintf("The DNS record set of %s (A, AAAA, CNAME chain, MX, TXT, NS, SOA, CAA) as resolved during the scan.", host),
+		Solution:      "Review the records for stale aliases, unexpected mail servers and exposed TXT content",
+		SeeAlso:       "https://github.com/itszeeshan/subdomainx",
+		PluginOutput:  strings.Join(records.Lines(), "\n") + "\n",
+	}
+}
+
+// ipInfoItem creates the informational item listing who operates a host's
+// addresses
+func ipInfoItem(host string, infos []types.IPInfo) NessusItem {
+	var lines []string
+	for _, info := range infos {
+		lines = append(lines, info.String())
+	}
+	return NessusItem{
+		Port:          "0",
+		SvcName:       "general",
+		Protocol:      "tcp",
+		Severity:      "Info",
+		PluginID:      "99997", // Custom plugin ID for SubdomainX IP enrichment
+		PluginName:    "SubdomainX - IP Ownership",
+		PluginFamily:  "SubdomainX",
+		PluginType:    "local",
+		PluginVersion: "1.0",
+		RiskFactor:    "None",
+		Synopsis:      fmt.Sprintf("Network owner and hosting provider of %s", host),
+		Description:   fmt.Sprintf("The ASN, organisation, country and cloud or CDN provider of each address of %s, from offline IP data.", host),
+		Solution:      "Confirm that every address belongs to an expected provider and is covered by the engagement scope",
+		SeeAlso:       "https://github.com/itszeeshan/subdomainx",
+		PluginOutput:  strings.Join(lines, "\n") + "\n",
+	}
+}
+
 // extractHost extracts host from URL (reuse from zap_formatter.go)
 // This function is already defined in zap_formatter.go
 
@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
 	"github.com/itszeeshan/subdomainx/v2/internal/diff"
@@ -11,7 +12,7 @@ import (
 
 // Generate creates output files based on the configuration and results.
 // diffResult may be nil when diff is not enabled.
-func Generate(cfg *config.Config, subdomainResults []types.SubdomainResult, httpResults []types.HTTPResult, portResults []types.PortResult, waybackResults []types.WaybackEntry, takeoverResults []types.TakeoverResult, diffResult *diff.DiffResult) error {
+func Generate(cfg *config.Config, subdomainResults []types.SubdomainResult, httpResults []types.HTTPResult, portResults []types.PortResult, waybackResults []types.WaybackEntry, takeoverResults []types.TakeoverResult, findings []types.Finding, diffResult *diff.DiffResult) error {
 	// Create scan results structure
 	results := &types.ScanResults{
 		Subdomains: subdomainResults,
@@ -19,6 +20,7 @@ func Generate(cfg *config.Config, subdomainResults []types.SubdomainResult, http
 		Ports:      portResults,
 		Wayback:    waybackResults,
 		Takeover:   takeoverResults,
+		Findings:   findings,
 	}
 
 	// Store diff result for HTML report generation
@@ -83,6 +85,14 @@ func generateJSON(cfg *config.Config, results *types.ScanResults) error {
 		}
 	}
 
+	// Findings file
+	if len(results.Findings) > 0 {
+		findingsFile := filepath.Join(cfg.OutputDir, fmt.Sprintf("%s_findings.json", cfg.UniqueName))
+		if err := WriteJSON(findingsFile, results.Findings); err != nil {
+			return fmt.Errorf("failed to write findings JSON file: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -94,6 +104,22 @@ func generateTXT(cfg *config.Config, results *types.ScanResults) error {
 		return fmt.Errorf("failed to write subdomains TXT file: %v", err)
 	}
 
+	// DNS record sets file
+	if hasDNSRecords(results.Subdomains) {
+		dnsFile := filepath.Join(cfg.OutputDir, fmt.Sprintf("%s_dns.txt", cfg.UniqueName))
+		if err := WriteDNSTXT(dnsFile, results.Subdomains); err != nil {
+			return fmt.Errorf("failed to write DNS TXT file: %v", err)
+		}
+	}
+
+	// IP enrichment file
+	if hasIPInfo(results.Subdomains) {
+		ipInfoFile := filepath.Join(cfg.OutputDir, fmt.Sprintf("%s_ipinfo.txt", cfg.UniqueName))
+		if err := WriteIPInfoTXT(ipInfoFile, results.Subdomains); err != nil {
+			return fmt.Errorf("failed to write IP info TXT file: %v", err)
+		}
+	}
+
 	// HTTP results file
 	if len(results.HTTP) > 0 {
 		httpFile := filepath.Join(cfg.OutputDir, fmt.Sprintf("%s_http.txt", cfg.UniqueName))
@@ -118,6 +144,14 @@ func generateTXT(cfg *config.Config, results *types.ScanResults) error {
 		}
 	}
 
+	// Findings file
+	if len(results.Findings) > 0 {
+		findingsFile := filepath.Join(cfg.OutputDir, fmt.Sprintf("%s_findings.txt", cfg.UniqueName))
+		if err := WriteFindingsTXT(findingsFile, results.Findings); err != nil {
+			return fmt.Errorf("failed to write findings TXT file: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -178,3 +212,56 @@ func generateCSV(cfg *config.Config, results *types.ScanResults) error {
 
 	return nil
 }
+
+// hasDNSRecords reports whether any subdomain carries a DNS record set.
+func hasDNSRecords(subdomains []types.SubdomainResult) bool {
+	for _, s := range subdomains {
+		if s.DNS != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// dnsRecordsByHost maps each subdomain with a DNS record set to it, for the
+// formatters that list hosts by their HTTP results.
+func dnsRecordsByHost(subdomains []types.SubdomainResult) map[string]*types.DNSRecords {
+	m := make(map[string]*types.DNSRecords)
+	for _, s := range subdomains {
+		if s.DNS != nil {
+			m[s.Subdomain] = s.DNS
+		}
+	}
+	return m
+}
+
+// ipInfoByHost maps each subdomain with tagged addresses to their tags, for
+// the formatters that list hosts by their HTTP results.
+func ipInfoByHost(subdomains []types.SubdomainResult) map[string][]types.IPInfo {
+	m := make(map[string][]types.IPInfo)
+	for _, s := range subdomains {
+		if len(s.IPInfo) > 0 {
+			m[s.Subdomain] = s.IPInfo
+		}
+	}
+	return m
+}
+
+// hasIPInfo reports whether any subdomain carries tagged addresses.
+func hasIPInfo(subdomains []types.SubdomainResult) bool {
+	for _, s := range subdomains {
+		if len(s.IPInfo) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// portIPInfo returns the tags of a port result's address, e.g.
+// "AS13335 CLOUDFLARENET, US, cloudflare", or "" when it has none.
+func portIPInfo(r types.PortResult) string {
+	if r.IPInfo == nil {
+		return ""
+	}
+	return strings.Join(r.IPInfo.Tags(), ", ")
+}
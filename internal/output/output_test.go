@@ -3,6 +3,7 @@ package output
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
@@ -37,7 +38,7 @@ func TestGenerate(t *testing.T) {
 		OutputFormat: "json",
 	}
 
-	err = Generate(cfg, subdomainResults, httpResults, portResults, nil, nil, nil)
+	err = Generate(cfg, subdomainResults, httpResults, portResults, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Generate JSON failed: %v", err)
 	}
@@ -50,7 +51,7 @@ func TestGenerate(t *testing.T) {
 
 	// Test TXT format
 	cfg.OutputFormat = "txt"
-	err = Generate(cfg, subdomainResults, httpResults, portResults, nil, nil, nil)
+	err = Generate(cfg, subdomainResults, httpResults, portResults, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Generate TXT failed: %v", err)
 	}
@@ -63,7 +64,7 @@ func TestGenerate(t *testing.T) {
 
 	// Test HTML format
 	cfg.OutputFormat = "html"
-	err = Generate(cfg, subdomainResults, httpResults, portResults, nil, nil, nil)
+	err = Generate(cfg, subdomainResults, httpResults, portResults, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Generate HTML failed: %v", err)
 	}
@@ -90,7 +91,7 @@ func TestGenerateInvalidFormat(t *testing.T) {
 	}
 
 	// Test invalid format
-	err = Generate(cfg, []types.SubdomainResult{}, []types.HTTPResult{}, []types.PortResult{}, nil, nil, nil)
+	err = Generate(cfg, []types.SubdomainResult{}, []types.HTTPResult{}, []types.PortResult{}, nil, nil, nil, nil)
 	if err == nil {
 		t.Error("Expected error for invalid format")
 	}
@@ -116,7 +117,7 @@ func TestGenerateEmptyResults(t *testing.T) {
 	}
 
 	// Test with empty results
-	err = Generate(cfg, []types.SubdomainResult{}, []types.HTTPResult{}, []types.PortResult{}, nil, nil, nil)
+	err = Generate(cfg, []types.SubdomainResult{}, []types.HTTPResult{}, []types.PortResult{}, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Generate JSON with empty results failed: %v", err)
 	}
@@ -127,3 +128,116 @@ func TestGenerateEmptyResults(t *testing.T) {
 		t.Error("JSON file was not created for empty results")
 	}
 }
+
+func TestGenerateFindings(t *testing.T) {
+	tmpDir := t.TempDir()
+	findings := []types.Finding{{
+		Type:     "axfr-open",
+		Severity: types.SeverityMedium,
+		Target:   "example.com",
+		Title:    "Zone transfer allowed by ns1.example.com",
+		Details:  []string{"www.example.com A"},
+	}}
+	subdomains := []types.SubdomainResult{{Subdomain: "www.example.com", Source: "axfr"}}
+
+	for format, file := range map[string]string{
+		"json": "test_scan_findings.json",
+		"txt":  "test_scan_findings.txt",
+		"csv":  "test_scan_results.csv",
+		"html": "test_scan_report.html",
+	} {
+		cfg := &config.Config{UniqueName: "test_scan", OutputDir: tmpDir, OutputFormat: format}
+		if err := Generate(cfg, subdomains, nil, nil, nil, nil, findings, nil); err != nil {
+			t.Fatalf("Generate %s failed: %v", format, err)
+		}
+		data, err := os.ReadFile(filepath.Join(tmpDir, file))
+		if err != nil {
+			t.Fatalf("%s output was not created: %v", format, err)
+		}
+		if !strings.Contains(string(data), "Zone transfer allowed by ns1.example.com") {
+			t.Errorf("%s output does not mention the finding", format)
+		}
+	}
+}
+
+func TestGenerateDNSRecords(t *testing.T) {
+	tmpDir := t.TempDir()
+	subdomains := []types.SubdomainResult{{
+		Subdomain: "www.example.com",
+		Source:    "subfinder",
+		IPs:       []string{"192.0.2.1"},
+		DNS: &types.DNSRecords{
+			A:     []string{"192.0.2.1"},
+			CNAME: []string{"edge.example.net"},
+			MX:    []string{"10 mail.example.com"},
+		},
+	}}
+	httpResults := []types.HTTPResult{{URL: "https://www.example.com", StatusCode: 200}}
+
+	for format, file := range map[string]string{
+		"json":   "test_scan_results.json",
+		"txt":    "test_scan_dns.txt",
+		"csv":    "test_scan_results.csv",
+		"html":   "test_scan_report.html",
+		"zap":    "test_scan_zap.xml",
+		"burp":   "test_scan_burp.xml",
+		"nessus": "test_scan_nessus.xml",
+	} {
+		cfg := &config.Config{UniqueName: "test_scan", OutputDir: tmpDir, OutputFormat: format}
+		if err := Generate(cfg, subdomains, httpResults, nil, nil, nil, nil, nil); err != nil {
+			t.Fatalf("Generate %s failed: %v", format, err)
+		}
+		data, err := os.ReadFile(filepath.Join(tmpDir, file))
+		if err != nil {
+			t.Fatalf("%s output was not created: %v", format, err)
+		}
+		if !strings.Contains(string(data), "10 mail.example.com") || !strings.Contains(string(data), "edge.example.net") {
+			t.Errorf("%s output does not show the DNS records", format)
+		}
+	}
+}
+
+func TestGenerateIPInfo(t *testing.T) {
+	tmpDir := t.TempDir()
+	cloudflare := types.IPInfo{IP: "104.16.1.1", ASN: 13335, Org: "CLOUDFLARENET", Country: "US", Provider: "cloudflare"}
+	subdomains := []types.SubdomainResult{{
+		Subdomain: "www.example.com",
+		Source:    "subfinder",
+		IPs:       []string{"104.16.1.1"},
+		IPInfo:    []types.IPInfo{cloudflare},
+	}}
+	httpResults := []types.HTTPResult{{URL: "https://www.example.com", StatusCode: 200}}
+	portResults := []types.PortResult{{
+		Host:   "www.example.com",
+		IP:     "104.16.1.1",
+		IPInfo: &cloudflare,
+		Ports:  []types.Port{{Number: 443, Protocol: "tcp", State: "open"}},
+	}}
+
+	for format, file := range map[string]string{
+		"json":   "test_scan_results.json",
+		"txt":    "test_scan_ipinfo.txt",
+		"csv":    "test_scan_results.csv",
+		"html":   "test_scan_report.html",
+		"zap":    "test_scan_zap.xml",
+		"burp":   "test_scan_burp.xml",
+		"nessus": "test_scan_nessus.xml",
+	} {
+		cfg := &config.Config{UniqueName: "test_scan", OutputDir: tmpDir, OutputFormat: format}
+		if err := Generate(cfg, subdomains, httpResults, portResults, nil, nil, nil, nil); err != nil {
+			t.Fatalf("Generate %s failed: %v", format, err)
+		}
+		data, err := os.ReadFile(filepath.Join(tmpDir, file))
+		if err != nil {
+			t.Fatalf("%s output was not created: %v", format, err)
+		}
+		if !strings.Contains(string(data), "CLOUDFLARENET") || !strings.Contains(string(data), "cloudflare") {
+			t.Errorf("%s output does not show the IP tags", format)
+		}
+	}
+
+	ports, err := os.ReadFile(filepath.Join(tmpDir, "test_scan_ports.txt"))
+	if err != nil || !strings.Contains(string(ports), "AS13335 CLOUDFLARENET, US, cloudflare") {
+		t.Errorf("Expected the port TXT output to show the IP tags, got %q", ports)
+	}
+}
@@ -25,6 +25,61 @@ func WriteTXT(filename string, subdomains []types.SubdomainResult) error {
 	return nil
 }
 
+// WriteDNSTXT writes the record sets of subdomains to a text file, one
+// record per line.
+func WriteDNSTXT(filename string, subdomains []types.SubdomainResult) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err := fmt.Fprintln(file, "Subdomain\tType\tValue"); err != nil {
+		return err
+	}
+
+	for _, s := range subdomains {
+		for _, rtype := range types.DNSRecordTypes {
+			for _, v := range s.DNS.Values(rtype) {
+				if _, err := fmt.Fprintf(file, "%s\t%s\t%s\n", s.Subdomain, rtype, v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteIPInfoTXT writes the tags of every subdomain address to a text
+// file, one address per line.
+func WriteIPInfoTXT(filename string, subdomains []types.SubdomainResult) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err := fmt.Fprintln(file, "Subdomain\tIP\tASN\tOrganisation\tCountry\tProvider\tService"); err != nil {
+		return err
+	}
+
+	for _, s := range subdomains {
+		for _, info := range s.IPInfo {
+			asn := ""
+			if info.ASN != 0 {
+				asn = fmt.Sprintf("AS%d", info.ASN)
+			}
+			if _, err := fmt.Fprintf(file, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				s.Subdomain, info.IP, asn, info.Org, info.Country, info.Provider, info.Service); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // WriteHTTPTXT writes HTTP results to a text file
 func WriteHTTPTXT(filename string, httpResults []types.HTTPResult) error {
 	file, err := os.Create(filename)
@@ -58,14 +113,14 @@ func WritePortsTXT(filename string, portResults []types.PortResult) error {
 	defer func() { _ = file.Close() }()
 
 	// Write header
-	if _, err := fmt.Fprintln(file, "Host\tIP\tPort\tProtocol\tState\tService\tVersion"); err != nil {
+	if _, err := fmt.Fprintln(file, "Host\tIP\tPort\tProtocol\tState\tService\tVersion\tIP Info"); err != nil {
 		return err
 	}
 
 	for _, result := range portResults {
 		for _, port := range result.Ports {
-			if _, err := fmt.Fprintf(file, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
-				result.Host, result.IP, port.Number, port.Protocol, port.State, port.Service, port.Version); err != nil {
+			if _, err := fmt.Fprintf(file, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+				result.Host, result.IP, port.Number, port.Protocol, port.State, port.Service, port.Version, portIPInfo(result)); err != nil {
 				return err
 			}
 		}
@@ -96,6 +151,34 @@ func WriteTakeoverTXT(filename string, results []types.TakeoverResult) error {
 	return nil
 }
 
+// WriteFindingsTXT writes findings to a text file, one per line followed by
+// their indented details.
+func WriteFindingsTXT(filename string, findings []types.Finding) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err := fmt.Fprintln(file, "Severity\tType\tTarget\tTitle\tEvidence"); err != nil {
+		return err
+	}
+
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(file, "%s\t%s\t%s\t%s\t%s\n",
+			f.Severity, f.Type, f.Target, f.Title, f.Evidence); err != nil {
+			return err
+		}
+		for _, d := range f.Details {
+			if _, err := fmt.Fprintf(file, "\t%s\n", d); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // WriteSubdomainsOnly writes just the subdomain names to a text file
 func WriteSubdomainsOnly(filename string, subdomains []types.SubdomainResult) error {
 	file, err := os.Create(filename)
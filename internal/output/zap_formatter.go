@@ -11,12 +11,30 @@ import (
 
 // ZAPSite represents a site in ZAP XML format
 type ZAPSite struct {
-	XMLName xml.Name `xml:"site"`
-	Name    string   `xml:"name,attr"`
-	Host    string   `xml:"host,attr"`
-	Port    string   `xml:"port,attr"`
-	SSL     string   `xml:"ssl,attr"`
-	URLs    []ZAPURL `xml:"urls>url"`
+	XMLName xml.Name       `xml:"site"`
+	Name    string         `xml:"name,attr"`
+	Host    string         `xml:"host,attr"`
+	Port    string         `xml:"port,attr"`
+	SSL     string         `xml:"ssl,attr"`
+	URLs    []ZAPURL       `xml:"urls>url"`
+	DNS     []ZAPDNSRecord `xml:"dns>record,omitempty"`
+	IPs     []ZAPIPInfo    `xml:"ips>ip,omitempty"`
+}
+
+// ZAPDNSRecord represents one DNS record of a site's host
+type ZAPDNSRecord struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ZAPIPInfo represents one tagged address of a site's host
+type ZAPIPInfo struct {
+	ASN      string `xml:"asn,attr,omitempty"`
+	Org      string `xml:"org,attr,omitempty"`
+	Country  string `xml:"country,attr,omitempty"`
+	Provider string `xml:"provider,attr,omitempty"`
+	Service  string `xml:"service,attr,omitempty"`
+	IP       string `xml:",chardata"`
 }
 
 // ZAPURL represents a URL in ZAP XML format
@@ -52,6 +70,8 @@ func WriteZAP(filename string, results *types.ScanResults) error {
 		host := extractHost(http.URL)
 		hostGroups[host] = append(hostGroups[host], http)
 	}
+	records := dnsRecordsByHost(results.Subdomains)
+	ipInfo := ipInfoByHost(results.Subdomains)
 
 	// Create ZAP sites
 	var zapSites []ZAPSite
@@ -79,6 +99,18 @@ func WriteZAP(filename string, results *types.ScanResults) error {
 			SSL:  ssl,
 			URLs: urls,
 		}
+		for _, rtype := range types.DNSRecordTypes {
+			for _, v := range records[host].Values(rtype) {
+				site.DNS = append(site.DNS, ZAPDNSRecord{Type: rtype, Value: v})
+			}
+		}
+		for _, info := range ipInfo[host] {
+			ip := ZAPIPInfo{Org: info.Org, Country: info.Country, Provider: info.Provider, Service: info.Service, IP: info.IP}
+			if info.ASN != 0 {
+				ip.ASN = fmt.Sprintf("AS%d", info.ASN)
+			}
+			site.IPs = append(site.IPs, ip)
+		}
 		zapSites = append(zapSites, site)
 	}
 
@@ -0,0 +1,474 @@
+// Package plugin runs out-of-process plugins that speak a JSON-over-stdio
+// protocol, so enumerators, scanners and notifiers can be written in any
+// language.
+//
+// Every message is one JSON object per line. The host starts the plugin,
+// sends a handshake and reads the plugin's handshake back, which names the
+// plugin and declares its capabilities. It then sends one request and reads
+// messages until "done" or "error":
+//
+//	host:   {"type":"handshake","protocol":1}
+//	plugin: {"type":"handshake","protocol":1,"name":"otx","capabilities":["enumerator"]}
+//	host:   {"type":"enumerate","domain":"example.com","options":{...}}
+//	plugin: {"type":"progress","status":"page 2","found":40}
+//	plugin: {"type":"subdomain","subdomain":"www.example.com"}
+//	plugin: {"type":"done"}
+//
+// To cancel, the host sends {"type":"cancel"} and closes stdin; a plugin
+// that has not exited within a grace period is killed. During discovery
+// the host closes stdin right after the handshake.
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// ProtocolVersion is the protocol version the host speaks.
+const ProtocolVersion = 1
+
+// errUnexpectedExit is returned when a plugin stops before finishing.
+var errUnexpectedExit = errors.New("plugin exited unexpectedly")
+
+// Capabilities a plugin can declare.
+const (
+	CapEnumerator  = "enumerator"
+	CapScanner     = "scanner"      // HTTP scanner: gets URLs, returns http_result
+	CapPortScanner = "port-scanner" // gets hosts, returns port_result
+	CapNotifier    = "notifier"
+)
+
+// Message types. Requests flow from host to plugin, the rest back.
+const (
+	TypeHandshake  = "handshake"
+	TypeEnumerate  = "enumerate"
+	TypeScan       = "scan"
+	TypePortScan   = "port_scan"
+	TypeNotify     = "notify"
+	TypeCancel     = "cancel"
+	TypeSubdomain  = "subdomain"
+	TypeHTTPResult = "http_result"
+	TypePortResult = "port_result"
+	TypeProgress   = "progress"
+	TypeLog        = "log"
+	TypeDone       = "done"
+	TypeError      = "error"
+)
+
+const (
+	// handshakeTimeout bounds how long a plugin may take to answer the
+	// handshake.
+	handshakeTimeout = 10 * time.Second
+	// cancelGrace is how long a cancelled plugin may take to exit.
+	cancelGrace = 5 * time.Second
+	// maxMessage bounds a single message line.
+	maxMessage = 16 << 20
+)
+
+// Message is one line of the protocol. Only the fields of its type are set.
+type Message struct {
+	Type string `json:"type"`
+
+	// handshake
+	Protocol     int      `json:"protocol,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	SourceType   string   `json:"source_type,omitempty"` // provenance type of an enumerator's names
+
+	// requests
+	Domain  string   `json:"domain,omitempty"`
+	Targets []string `json:"targets,omitempty"`
+	Options *Options `json:"options,omitempty"`
+	Summary *Summary `json:"summary,omitempty"`
+
+	// results
+	Subdomain  string            `json:"subdomain,omitempty"`
+	HTTPResult *types.HTTPResult `json:"http_result,omitempty"`
+	PortResult *types.PortResult `json:"port_result,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Found      int               `json:"found,omitempty"`
+	Level      string            `json:"level,omitempty"`
+	Message    string            `json:"message,omitempty"` // log text or error
+}
+
+// Options carries the scan settings a plugin may honour.
+type Options struct {
+	Threads   int    `json:"threads,omitempty"`
+	Timeout   int    `json:"timeout,omitempty"`
+	Retries   int    `json:"retries,omitempty"`
+	RateLimit int    `json:"rate_limit,omitempty"`
+	Wordlist  string `json:"wordlist,omitempty"`
+}
+
+// OptionsFrom returns the settings of cfg passed to plugins.
+func OptionsFrom(cfg *config.Config) *Options {
+	return &Options{
+		Threads:   cfg.Threads,
+		Timeout:   cfg.Timeout,
+		Retries:   cfg.Retries,
+		RateLimit: cfg.RateLimit,
+		Wordlist:  cfg.Wordlist,
+	}
+}
+
+// Summary is the scan summary sent to notifier plugins.
+type Summary struct {
+	ScanID          string          `json:"scan_id"`
+	Domain          string          `json:"domain"`
+	TotalSubdomains int             `json:"total_subdomains"`
+	TotalHTTP       int             `json:"total_http"`
+	TotalPorts      int             `json:"total_ports"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	Error           string          `json:"error,omitempty"`
+	Added           []string        `json:"added,omitempty"`
+	Removed         []string        `json:"removed,omitempty"`
+	Findings        []types.Finding `json:"findings,omitempty"`
+}
+
+// Plugin is a discovered plugin executable and what its handshake declared.
+type Plugin struct {
+	Path         string
+	Name         string
+	Version      string
+	Description  string
+	Capabilities []string
+	SourceType   string
+}
+
+// Has reports whether the plugin declared capability.
+func (p *Plugin) Has(capability string) bool {
+	return slices.Contains(p.Capabilities, capability)
+}
+
+// DefaultDir returns the plugin directory used when none is given:
+// "subdomainx/plugins" in the user config directory, or "" when there is
+// none. Discovery runs every executable in the directory, so the default
+// is never relative to the working directory.
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "subdomainx", "plugins")
+}
+
+// Discover handshakes with every executable in dir and returns the plugins
+// that answered, sorted by name. Plugins that fail the handshake are
+// skipped and reported in the returned error; a missing directory, or
+// none at all, has no plugins.
+func Discover(dir string) ([]*Plugin, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var plugins []*Plugin
+	var errs []error
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !isExecutable(entry) {
+			continue
+		}
+		p, err := handshake(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %v", path, err))
+			continue
+		}
+		if other, dup := seen[p.Name]; dup {
+			errs = append(errs, fmt.Errorf("plugin %s: name %q already used by %s", path, p.Name, other))
+			continue
+		}
+		seen[p.Name] = path
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, errors.Join(errs...)
+}
+
+// isExecutable reports whether a directory entry looks like a runnable
+// plugin.
+func isExecutable(entry os.DirEntry) bool {
+	info, err := entry.Info()
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(entry.Name()), ".exe")
+	}
+	return info.Mode()&0111 != 0
+}
+
+// handshake starts the plugin at path, reads its declaration and lets it
+// exit.
+func handshake(path string) (*Plugin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	s, err := start(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+
+	hs, err := s.handshake()
+	if err != nil {
+		return nil, s.exitError(err)
+	}
+	p := &Plugin{
+		Path:         path,
+		Name:         hs.Name,
+		Version:      hs.Version,
+		Description:  hs.Description,
+		Capabilities: hs.Capabilities,
+		SourceType:   hs.SourceType,
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("handshake has no name")
+	}
+	for _, c := range p.Capabilities {
+		if c != CapEnumerator && c != CapScanner && c != CapPortScanner && c != CapNotifier {
+			return nil, fmt.Errorf("unknown capability %q", c)
+		}
+	}
+	if len(p.Capabilities) == 0 {
+		return nil, fmt.Errorf("handshake declares no capabilities")
+	}
+	return p, nil
+}
+
+// Call runs the plugin for one request and passes every result, progress
+// and log message to onMessage until the plugin reports done. Cancelling
+// ctx cancels the plugin; results it sent before are kept by the caller.
+func (p *Plugin) Call(ctx context.Context, req Message, onMessage func(Message)) error {
+	s, err := start(ctx, p.Path)
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	if _, err := s.handshake(); err != nil {
+		return s.exitError(err)
+	}
+	if err := s.send(req); err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+
+	for {
+		msg, err := s.read()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return s.exitError(err)
+		}
+		switch msg.Type {
+		case TypeDone:
+			return nil
+		case TypeError:
+			return fmt.Errorf("%s", msg.Message)
+		default:
+			onMessage(msg)
+		}
+	}
+}
+
+// session is one running plugin process.
+type session struct {
+	cmd    *exec.Cmd
+	out    io.Reader
+	stdout *bufio.Scanner
+	stderr *tailBuffer
+	done   chan struct{}
+
+	closeOnce sync.Once
+
+	mu    sync.Mutex // guards stdin, written by the caller and the canceller
+	stdin io.WriteCloser
+}
+
+// start launches the plugin at path. When ctx ends the plugin is asked to
+// cancel and killed after cancelGrace.
+func start(ctx context.Context, path string) (*session, error) {
+	cmd := exec.Command(path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &tailBuffer{}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start: %v", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessage)
+	s := &session{cmd: cmd, stdin: stdin, out: stdout, stdout: scanner, stderr: stderr, done: make(chan struct{})}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = s.send(Message{Type: TypeCancel})
+			s.closeStdin()
+			select {
+			case <-time.After(cancelGrace):
+				_ = cmd.Process.Kill()
+			case <-s.done:
+			}
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// handshake exchanges handshakes and checks the protocol version.
+func (s *session) handshake() (Message, error) {
+	if err := s.send(Message{Type: TypeHandshake, Protocol: ProtocolVersion}); err != nil {
+		return Message{}, fmt.Errorf("handshake failed: %v", err)
+	}
+	msg, err := s.read()
+	if err != nil {
+		return Message{}, fmt.Errorf("handshake failed: %v", err)
+	}
+	if msg.Type != TypeHandshake {
+		return Message{}, fmt.Errorf("handshake failed: got %q message", msg.Type)
+	}
+	if msg.Protocol != ProtocolVersion {
+		return Message{}, fmt.Errorf("unsupported protocol version %d (host speaks %d)", msg.Protocol, ProtocolVersion)
+	}
+	return msg, nil
+}
+
+func (s *session) send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stdin == nil {
+		return io.ErrClosedPipe
+	}
+	_, err = s.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// read returns the next message, skipping blank lines.
+func (s *session) read() (Message, error) {
+	for s.stdout.Scan() {
+		line := strings.TrimSpace(s.stdout.Text())
+		if line == "" {
+			continue
+		}
+		var msg Message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return Message{}, fmt.Errorf("invalid message %q: %v", truncate(line, 80), err)
+		}
+		return msg, nil
+	}
+	if err := s.stdout.Err(); err != nil {
+		return Message{}, err
+	}
+	return Message{}, errUnexpectedExit
+}
+
+// exitError reaps the plugin after err and adds what it printed to stderr.
+func (s *session) exitError(err error) error {
+	s.close()
+	if tail := strings.TrimSpace(s.stderr.String()); tail != "" {
+		return fmt.Errorf("%v: %s", err, tail)
+	}
+	return err
+}
+
+func (s *session) closeStdin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stdin != nil {
+		_ = s.stdin.Close()
+		s.stdin = nil
+	}
+}
+
+// close lets the plugin exit by closing its stdin, kills it if it does not
+// within cancelGrace, and reaps it. Only the first call has an effect.
+func (s *session) close() {
+	s.closeOnce.Do(s.shutdown)
+}
+
+func (s *session) shutdown() {
+	s.closeStdin()
+	exited := make(chan struct{})
+	go func() {
+		// Unblock a plugin still writing so it can see stdin close
+		_, _ = io.Copy(io.Discard, s.out)
+		_ = s.cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(cancelGrace):
+		_ = s.cmd.Process.Kill()
+		<-exited
+	}
+	close(s.done)
+}
+
+// tailBuffer keeps the last 4 KiB written to it, enough for the error a
+// failing plugin prints.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - 4096; over > 0 {
+		b.buf = b.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
+
+// truncate shortens s to max characters, appending "..." if truncated.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPlugin answers the handshake, then serves an enumerate request with
+// two names and a scan request with one HTTP result.
+const testPlugin = `#!/bin/sh
+read hs
+echo '{"type":"handshake","protocol":1,"name":"testplug","version":"1.0","capabilities":["enumerator","scanner"],"source_type":"passive-dns"}'
+read req || exit 0
+case "$req" in
+*'"type":"enumerate"'*)
+  echo '{"type":"progress","status":"querying","found":0}'
+  echo '{"type":"subdomain","subdomain":"a.example.com"}'
+  echo
+  echo '{"type":"subdomain","subdomain":"b.example.com"}'
+  echo '{"type":"done"}' ;;
+*'"type":"scan"'*)
+  echo '{"type":"http_result","http_result":{"url":"https://a.example.com","status_code":200}}'
+  echo '{"type":"done"}' ;;
+*)
+  echo '{"type":"error","message":"unsupported request"}' ;;
+esac
+`
+
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "testplug", testPlugin)
+	writePlugin(t, dir, "oldproto", "#!/bin/sh\nread hs\necho '{\"type\":\"handshake\",\"protocol\":99,\"name\":\"old\",\"capabilities\":[\"enumerator\"]}'\n")
+	writePlugin(t, dir, "silent", "#!/bin/sh\nexit 1\n")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := Discover(dir)
+	if err == nil || !strings.Contains(err.Error(), "unsupported protocol version 99") || !strings.Contains(err.Error(), "silent") {
+		t.Errorf("Expected errors for the broken plugins, got %v", err)
+	}
+	if len(plugins) != 1 {
+		t.Fatalf("Expected 1 plugin, got %d", len(plugins))
+	}
+	p := plugins[0]
+	if p.Name != "testplug" || p.Version != "1.0" || p.SourceType != "passive-dns" {
+		t.Errorf("Unexpected plugin %+v", p)
+	}
+	if !p.Has(CapEnumerator) || !p.Has(CapScanner) || p.Has(CapNotifier) {
+		t.Errorf("Unexpected capabilities %v", p.Capabilities)
+	}
+
+	if plugins, err := Discover(filepath.Join(dir, "missing")); err != nil || len(plugins) != 0 {
+		t.Errorf("Expected a missing directory to have no plugins, got %v, %v", plugins, err)
+	}
+}
+
+func TestCall(t *testing.T) {
+	p := &Plugin{Path: writePlugin(t, t.TempDir(), "testplug", testPlugin)}
+
+	var subdomains, statuses []string
+	err := p.Call(context.Background(), Message{Type: TypeEnumerate, Domain: "example.com"}, func(msg Message) {
+		switch msg.Type {
+		case TypeSubdomain:
+			subdomains = append(subdomains, msg.Subdomain)
+		case TypeProgress:
+			statuses = append(statuses, msg.Status)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Call returned error: %v", err)
+	}
+	if !slices.Equal(subdomains, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("Unexpected subdomains %v", subdomains)
+	}
+	if !slices.Equal(statuses, []string{"querying"}) {
+		t.Errorf("Unexpected progress %v", statuses)
+	}
+
+	var status int
+	err = p.Call(context.Background(), Message{Type: TypeScan, Targets: []string{"https://a.example.com"}}, func(msg Message) {
+		if msg.Type == TypeHTTPResult {
+			status = msg.HTTPResult.StatusCode
+		}
+	})
+	if err != nil || status != 200 {
+		t.Errorf("Expected one 200 result, got %d, %v", status, err)
+	}
+
+	err = p.Call(context.Background(), Message{Type: TypeNotify}, func(Message) {})
+	if err == nil || err.Error() != "unsupported request" {
+		t.Errorf("Expected the plugin's error, got %v", err)
+	}
+}
+
+func TestCallCancel(t *testing.T) {
+	// The plugin streams one name, then exits once it reads the cancel
+	p := &Plugin{Path: writePlugin(t, t.TempDir(), "slow", `#!/bin/sh
+read hs
+echo '{"type":"handshake","protocol":1,"name":"slow","capabilities":["enumerator"]}'
+read req
+echo '{"type":"subdomain","subdomain":"a.example.com"}'
+read cancel
+case "$cancel" in *'"cancel"'*) exit 0 ;; esac
+sleep 60
+`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []string
+	start := time.Now()
+	err := p.Call(ctx, Message{Type: TypeEnumerate, Domain: "example.com"}, func(msg Message) {
+		got = append(got, msg.Subdomain)
+		cancel()
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if !slices.Equal(got, []string{"a.example.com"}) {
+		t.Errorf("Expected the name sent before cancelling, got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed >= cancelGrace {
+		t.Errorf("Expected the plugin to exit on cancel, took %v", elapsed)
+	}
+}
+
+func TestCallUnexpectedExit(t *testing.T) {
+	p := &Plugin{Path: writePlugin(t, t.TempDir(), "crash", `#!/bin/sh
+read hs
+echo '{"type":"handshake","protocol":1,"name":"crash","capabilities":["enumerator"]}'
+read req
+echo "boom" >&2
+exit 2
+`)}
+
+	err := p.Call(context.Background(), Message{Type: TypeEnumerate}, func(Message) {})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected an error carrying the plugin's stderr, got %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+const (
+	// maxAttempts is the number of upstreams tried for one query.
+	maxAttempts = 3
+	// maxConsecutiveFailures drops an upstream after this many transport
+	// errors or refusals in a row.
+	maxConsecutiveFailures = 10
+	// sentinelInterval is the number of queries between lying-resolver
+	// checks of an upstream. The first query always triggers one.
+	sentinelInterval = 1000
+)
+
+// ErrNoUpstreams is returned when every upstream has been dropped.
+var ErrNoUpstreams = errors.New("no healthy resolvers left")
+
+// upstream is one nameserver in a Pool along with its health state.
+type upstream struct {
+	addr     string
+	exchange exchangeFunc
+
+	queries  atomic.Uint64
+	failures atomic.Int32
+	dropped  atomic.Bool
+	probing  atomic.Bool
+	reason   string
+}
+
+// UpstreamHealth is a snapshot of one upstream's state.
+type UpstreamHealth struct {
+	Addr       string `json:"addr"`
+	Queries    uint64 `json:"queries"`
+	Failures   int    `json:"failures"`
+	Dropped    bool   `json:"dropped"`
+	DropReason string `json:"drop_reason,omitempty"`
+}
+
+// Pool spreads queries round-robin over a set of upstream resolvers. An
+// upstream that keeps failing, or that answers names which cannot exist, is
+// dropped from rotation; the last healthy upstream is never dropped.
+type Pool struct {
+	upstreams []*upstream
+	next      atomic.Uint32
+	mu        sync.Mutex
+
+	// OnDrop is called when an upstream is removed from rotation.
+	OnDrop func(upstream, reason string)
+}
+
+// NewPool creates a pool from resolver specs as accepted by
+// utils.NormalizeResolver (plain addresses, tcp://, tls:// or https://).
+func NewPool(servers []string) (*Pool, error) {
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no resolvers given")
+	}
+
+	p := &Pool{}
+	for _, server := range servers {
+		spec, err := utils.NormalizeResolver(server)
+		if err != nil {
+			return nil, err
+		}
+		exchange, err := newExchange(spec)
+		if err != nil {
+			return nil, err
+		}
+		p.upstreams = append(p.upstreams, &upstream{addr: spec, exchange: exchange})
+	}
+	return p, nil
+}
+
+// Health returns the current state of every upstream.
+func (p *Pool) Health() []UpstreamHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	health := make([]UpstreamHealth, 0, len(p.upstreams))
+	for _, u := range p.upstreams {
+		health = append(health, UpstreamHealth{
+			Addr:       u.addr,
+			Queries:    u.queries.Load(),
+			Failures:   int(u.failures.Load()),
+			Dropped:    u.dropped.Load(),
+			DropReason: u.reason,
+		})
+	}
+	return health
+}
+
+func (p *Pool) LookupHost(ctx context.Context, host string) ([]string, error) {
+	name := dns.Fqdn(host)
+
+	resp, err := p.Query(ctx, name, dns.TypeA)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode == dns.RcodeNameError {
+		return nil, notFoundError(host)
+	}
+	addrs := extractAddrs(resp)
+
+	// AAAA failures are not fatal once the A query succeeded
+	if resp, err := p.Query(ctx, name, dns.TypeAAAA); err == nil {
+		addrs = append(addrs, extractAddrs(resp)...)
+	}
+
+	if len(addrs) == 0 {
+		return nil, notFoundError(host)
+	}
+	return addrs, nil
+}
+
+func (p *Pool) LookupCNAME(ctx context.Context, host string) (string, error) {
+	name := dns.Fqdn(host)
+
+	resp, err := p.Query(ctx, name, dns.TypeA)
+	if err != nil {
+		return "", err
+	}
+	if resp.Rcode == dns.RcodeNameError {
+		return "", notFoundError(host)
+	}
+	return followCNAMEs(name, resp.Answer), nil
+}
+
+func (p *Pool) Query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qtype)
+	m.RecursionDesired = true
+	return p.exchange(ctx, m)
+}
+
+// exchange sends m to up to maxAttempts healthy upstreams, recording their
+// health, and returns the first usable answer.
+func (p *Pool) exchange(ctx context.Context, m *dns.Msg) (*dns.Msg, error) {
+	name := m.Question[0].Name
+	var lastErr error
+
+	for i := 0; i < maxAttempts; i++ {
+		u := p.pick()
+		if u == nil {
+			return nil, ErrNoUpstreams
+		}
+
+		resp, err := u.exchange(ctx, m)
+		if ctx.Err() != nil {
+			// Cancellation is not the upstream's fault
+			return nil, ctx.Err()
+		}
+
+		switch {
+		case err != nil:
+			p.recordFailure(u, err.Error())
+			lastErr = err
+		case resp.Rcode == dns.RcodeRefused:
+			p.recordFailure(u, "refused query")
+			lastErr = rcodeError(name, u.addr, resp.Rcode)
+		case resp.Rcode == dns.RcodeServerFailure:
+			// SERVFAIL is often the zone's fault, so retry without
+			// counting it against the upstream
+			lastErr = rcodeError(name, u.addr, resp.Rcode)
+		default:
+			p.recordSuccess(u)
+			return resp, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// pick returns the next upstream in rotation, skipping dropped ones.
+func (p *Pool) pick() *upstream {
+	n := len(p.upstreams)
+	start := int(p.next.Add(1))
+	for i := 0; i < n; i++ {
+		u := p.upstreams[(start+i)%n]
+		if !u.dropped.Load() {
+			return u
+		}
+	}
+	return nil
+}
+
+func (p *Pool) recordSuccess(u *upstream) {
+	u.failures.Store(0)
+	if u.queries.Add(1)%sentinelInterval == 1 {
+		go p.checkSentinel(u)
+	}
+}
+
+func (p *Pool) recordFailure(u *upstream, reason string) {
+	u.queries.Add(1)
+	if u.failures.Add(1) >= maxConsecutiveFailures {
+		p.drop(u, fmt.Sprintf("%d consecutive failures (last: %s)", maxConsecutiveFailures, reason))
+	}
+}
+
+// checkSentinel asks u for a random name that cannot exist. A resolver that
+// returns records for it hijacks NXDOMAIN and would poison every result.
+func (p *Pool) checkSentinel(u *upstream) {
+	if !u.probing.CompareAndSwap(false, true) {
+		return
+	}
+	defer u.probing.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), utils.DNSQueryTimeout)
+	defer cancel()
+
+	m := new(dns.Msg)
+	m.SetQuestion(sentinelName(), dns.TypeA)
+	m.RecursionDesired = true
+
+	resp, err := u.exchange(ctx, m)
+	if err != nil {
+		return // transport errors are tracked by regular queries
+	}
+	if resp.Rcode == dns.RcodeSuccess && len(extractAddrs(resp)) > 0 {
+		p.drop(u, "answered a non-existent name (NXDOMAIN hijacking)")
+	}
+}
+
+// drop removes u from rotation unless it is the last healthy upstream.
+func (p *Pool) drop(u *upstream, reason string) {
+	p.mu.Lock()
+	if u.dropped.Load() {
+		p.mu.Unlock()
+		return
+	}
+	healthy := 0
+	for _, other := range p.upstreams {
+		if !other.dropped.Load() {
+			healthy++
+		}
+	}
+	if healthy <= 1 {
+		p.mu.Unlock()
+		return
+	}
+	u.reason = reason
+	u.dropped.Store(true)
+	p.mu.Unlock()
+
+	if p.OnDrop != nil {
+		p.OnDrop(u.addr, reason)
+	}
+}
+
+// extractAddrs returns the A and AAAA records in a response.
+func extractAddrs(resp *dns.Msg) []string {
+	var addrs []string
+	for _, rr := range resp.Answer {
+		switch r := rr.(type) {
+		case *dns.A:
+			addrs = append(addrs, r.A.String())
+		case *dns.AAAA:
+			addrs = append(addrs, r.AAAA.String())
+		}
+	}
+	return addrs
+}
+
+// followCNAMEs walks the CNAME chain for name within an answer section and
+// returns the final target.
+func followCNAMEs(name string, answer []dns.RR) string {
+	target := name
+	for hops := 0; hops < len(answer); hops++ {
+		next := ""
+		for _, rr := range answer {
+			if c, ok := rr.(*dns.CNAME); ok && strings.EqualFold(c.Hdr.Name, target) {
+				next = c.Target
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		target = next
+	}
+	return target
+}
+
+// sentinelName returns a random name under .com that should never exist.
+func sentinelName() string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, 20)
+	for i := range b {
+		b[i] = alphabet[rand.IntN(len(alphabet))]
+	}
+	return string(b) + ".com."
+}
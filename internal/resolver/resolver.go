@@ -0,0 +1,116 @@
+// Package resolver provides the DNS resolution layer shared by every package:
+// plain UDP/TCP nameserver pools, DNS-over-TLS and DNS-over-HTTPS upstreams,
+// per-upstream health tracking and detection of lying resolvers.
+package resolver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/miekg/dns"
+)
+
+// Resolver is the DNS lookup interface used throughout the pipeline.
+// Errors follow the net package conventions: a name that does not exist
+// yields a *net.DNSError with IsNotFound set.
+type Resolver interface {
+	// LookupHost returns the A and AAAA addresses of host.
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	// LookupCNAME returns the canonical name of host (rooted), or host
+	// itself when it is not an alias.
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	// Query sends a raw question of type qtype and returns the response,
+	// including NXDOMAIN responses.
+	Query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error)
+}
+
+// system is the fallback used when no scan resolver is in the context.
+var system = NewSystem()
+
+// Default returns the system resolver, used whenever a context carries no
+// scan resolver.
+func Default() Resolver {
+	return system
+}
+
+// New builds the resolver for one scan: a pool over cfg.Resolvers, or the
+// system resolver (honouring /etc/hosts) when none are configured. onDrop,
+// when non-nil, is called whenever a pool upstream is removed from rotation.
+func New(cfg *config.Config, onDrop func(upstream, reason string)) (Resolver, error) {
+	if len(cfg.Resolvers) == 0 {
+		return Default(), nil
+	}
+	pool, err := NewPool(cfg.Resolvers)
+	if err != nil {
+		return nil, err
+	}
+	pool.OnDrop = onDrop
+	return pool, nil
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries r. Each scan puts its own
+// resolver in its context so concurrent scans never share pool state.
+func NewContext(ctx context.Context, r Resolver) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the resolver carried by ctx, or Default.
+func FromContext(ctx context.Context) Resolver {
+	if r, ok := ctx.Value(contextKey{}).(Resolver); ok && r != nil {
+		return r
+	}
+	return Default()
+}
+
+// NewHTTPTransport returns a clone of the default HTTP transport whose host
+// lookups go through the resolver in each request's context.
+func NewHTTPTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = DialContext
+	return transport
+}
+
+// DialContext dials addr like net.Dialer, resolving its host through the
+// resolver in ctx. It is meant for http.Transport.DialContext.
+func DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var dialer net.Dialer
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	ips, err := FromContext(ctx).LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// notFoundError mirrors the error net returns for non-existent names.
+func notFoundError(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+// rcodeError describes a response that carries a failure rcode.
+func rcodeError(name, server string, rcode int) error {
+	msg, ok := dns.RcodeToString[rcode]
+	if !ok {
+		msg = "rcode " + strconv.Itoa(rcode)
+	}
+	return &net.DNSError{Err: "server misbehaving: " + msg, Name: name, Server: server}
+}
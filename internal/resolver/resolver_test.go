@@ -0,0 +1,241 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/miekg/dns"
+)
+
+// startServer runs a local DNS stand-in on a random UDP port and returns its
+// address. handler answers every query.
+func startServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	<-started
+	return pc.LocalAddr().String()
+}
+
+// zoneHandler serves a tiny example.com zone and NXDOMAIN for anything else.
+func zoneHandler(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	q := r.Question[0]
+
+	switch q.Name {
+	case "www.example.com.":
+		if q.Qtype == dns.TypeA {
+			rr, _ := dns.NewRR("www.example.com. 60 IN A 192.0.2.10")
+			m.Answer = append(m.Answer, rr)
+		}
+	case "alias.example.com.":
+		cname, _ := dns.NewRR("alias.example.com. 60 IN CNAME www.example.com.")
+		m.Answer = append(m.Answer, cname)
+		if q.Qtype == dns.TypeA {
+			rr, _ := dns.NewRR("www.example.com. 60 IN A 192.0.2.10")
+			m.Answer = append(m.Answer, rr)
+		}
+	default:
+		m.Rcode = dns.RcodeNameError
+	}
+	_ = w.WriteMsg(m)
+}
+
+// lyingHandler answers every A query, including names that cannot exist.
+func lyingHandler(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	if r.Question[0].Qtype == dns.TypeA {
+		rr, _ := dns.NewRR(r.Question[0].Name + " 60 IN A 198.51.100.1")
+		m.Answer = append(m.Answer, rr)
+	}
+	_ = w.WriteMsg(m)
+}
+
+// refusingHandler refuses every query.
+func refusingHandler(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetRcode(r, dns.RcodeRefused)
+	_ = w.WriteMsg(m)
+}
+
+func TestPoolLookups(t *testing.T) {
+	pool, err := NewPool([]string{startServer(t, zoneHandler)})
+	if err != nil {
+		t.Fatalf("NewPool returned error: %v", err)
+	}
+	ctx := context.Background()
+
+	addrs, err := pool.LookupHost(ctx, "www.example.com")
+	if err != nil {
+		t.Fatalf("LookupHost returned error: %v", err)
+	}
+	if len(addrs) != 1 || addrs[0] != "192.0.2.10" {
+		t.Errorf("Expected [192.0.2.10], got %v", addrs)
+	}
+
+	cname, err := pool.LookupCNAME(ctx, "alias.example.com")
+	if err != nil {
+		t.Fatalf("LookupCNAME returned error: %v", err)
+	}
+	if cname != "www.example.com." {
+		t.Errorf("Expected CNAME www.example.com., got %s", cname)
+	}
+
+	cname, err = pool.LookupCNAME(ctx, "www.example.com")
+	if err != nil || cname != "www.example.com." {
+		t.Errorf("Expected non-alias to return itself, got %s (err %v)", cname, err)
+	}
+
+	_, err = pool.LookupHost(ctx, "missing.example.com")
+	dnsErr, ok := err.(*net.DNSError)
+	if !ok || !dnsErr.IsNotFound {
+		t.Errorf("Expected not-found DNSError, got %v", err)
+	}
+}
+
+func TestPoolDropsLyingResolver(t *testing.T) {
+	honest := startServer(t, zoneHandler)
+	liar := startServer(t, lyingHandler)
+
+	pool, err := NewPool([]string{honest, liar})
+	if err != nil {
+		t.Fatalf("NewPool returned error: %v", err)
+	}
+
+	var mu sync.Mutex
+	var dropped []string
+	pool.OnDrop = func(upstream, reason string) {
+		mu.Lock()
+		dropped = append(dropped, upstream)
+		mu.Unlock()
+	}
+
+	// The first queries trigger the sentinel checks on both upstreams
+	for i := 0; i < 4; i++ {
+		_, _ = pool.LookupHost(context.Background(), "www.example.com")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(dropped)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dropped) != 1 || dropped[0] != liar {
+		t.Fatalf("Expected lying resolver %s to be dropped, got %v", liar, dropped)
+	}
+	for _, h := range pool.Health() {
+		if h.Addr == honest && h.Dropped {
+			t.Error("Honest resolver should not be dropped")
+		}
+	}
+}
+
+func TestPoolDropsFailingResolver(t *testing.T) {
+	honest := startServer(t, zoneHandler)
+	refusing := startServer(t, refusingHandler)
+
+	pool, err := NewPool([]string{honest, refusing})
+	if err != nil {
+		t.Fatalf("NewPool returned error: %v", err)
+	}
+
+	// Every query still succeeds because failed attempts move on to the
+	// next upstream
+	for i := 0; i < 3*maxConsecutiveFailures; i++ {
+		if _, err := pool.LookupHost(context.Background(), "www.example.com"); err != nil {
+			t.Fatalf("LookupHost returned error: %v", err)
+		}
+	}
+
+	for _, h := range pool.Health() {
+		if h.Addr == refusing && !h.Dropped {
+			t.Errorf("Expected refusing resolver to be dropped, health: %+v", h)
+		}
+	}
+}
+
+func TestPoolKeepsLastResolver(t *testing.T) {
+	pool, err := NewPool([]string{startServer(t, refusingHandler)})
+	if err != nil {
+		t.Fatalf("NewPool returned error: %v", err)
+	}
+
+	for i := 0; i < 2*maxConsecutiveFailures; i++ {
+		_, _ = pool.LookupHost(context.Background(), "www.example.com")
+	}
+	if pool.Health()[0].Dropped {
+		t.Error("The last healthy resolver must never be dropped")
+	}
+}
+
+func TestNewPerScan(t *testing.T) {
+	r, err := New(&config.Config{}, nil)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if _, ok := r.(*System); !ok {
+		t.Errorf("Expected the system resolver without configured resolvers, got %T", r)
+	}
+
+	a, err := New(&config.Config{Resolvers: []string{startServer(t, zoneHandler)}}, nil)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	b, err := New(&config.Config{Resolvers: []string{startServer(t, refusingHandler)}}, nil)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	// Each scan's context keeps its own resolver.
+	ctxA := NewContext(context.Background(), a)
+	ctxB := NewContext(context.Background(), b)
+	if FromContext(ctxA) != a || FromContext(ctxB) != b {
+		t.Error("Expected each context to carry its own resolver")
+	}
+	if FromContext(context.Background()) != Default() {
+		t.Error("Expected the default resolver for a bare context")
+	}
+	if addrs, err := FromContext(ctxA).LookupHost(ctxA, "www.example.com"); err != nil || len(addrs) != 1 {
+		t.Errorf("Expected lookup through the scan resolver, got %v (err %v)", addrs, err)
+	}
+}
+
+func TestNewPoolInvalid(t *testing.T) {
+	if _, err := NewPool(nil); err == nil {
+		t.Error("Expected error for empty resolver list")
+	}
+	if _, err := NewPool([]string{"not-a-resolver"}); err == nil {
+		t.Error("Expected error for invalid resolver")
+	}
+}
+
+func TestNewExchangeSchemes(t *testing.T) {
+	for _, spec := range []string{"1.1.1.1:53", "tcp://1.1.1.1:53", "tls://dns.google:853", "https://dns.google/dns-query"} {
+		if _, err := newExchange(spec); err != nil {
+			t.Errorf("newExchange(%q) returned error: %v", spec, err)
+		}
+	}
+}
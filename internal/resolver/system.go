@@ -0,0 +1,57 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+// System resolves through the operating system: host lookups honour
+// /etc/hosts and the system configuration, while raw queries go to the
+// nameservers listed in /etc/resolv.conf.
+type System struct {
+	resolver *net.Resolver
+
+	once     sync.Once
+	pool     *Pool
+	err      error
+	confPath string
+}
+
+// NewSystem creates a resolver backed by the system configuration.
+func NewSystem() *System {
+	return &System{resolver: net.DefaultResolver, confPath: "/etc/resolv.conf"}
+}
+
+func (s *System) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return s.resolver.LookupHost(ctx, host)
+}
+
+func (s *System) LookupCNAME(ctx context.Context, host string) (string, error) {
+	return s.resolver.LookupCNAME(ctx, host)
+}
+
+func (s *System) Query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	s.once.Do(func() {
+		var servers []string
+		if conf, err := dns.ClientConfigFromFile(s.confPath); err == nil {
+			for _, server := range conf.Servers {
+				// Skip entries such as link-local addresses with a zone
+				if spec, err := utils.NormalizeResolver(net.JoinHostPort(server, conf.Port)); err == nil {
+					servers = append(servers, spec)
+				}
+			}
+		}
+		if len(servers) == 0 {
+			servers = utils.DefaultResolvers
+		}
+		s.pool, s.err = NewPool(servers)
+	})
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.pool.Query(ctx, name, qtype)
+}
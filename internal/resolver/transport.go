@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+// exchangeFunc sends a DNS message to one upstream and returns its answer.
+type exchangeFunc func(ctx context.Context, m *dns.Msg) (*dns.Msg, error)
+
+// newExchange returns the transport for a normalized resolver spec:
+// host:port (UDP, with TCP fallback on truncation), tcp://host:port,
+// tls://host:port (DoT) or an https:// URL (DoH).
+func newExchange(spec string) (exchangeFunc, error) {
+	switch {
+	case strings.HasPrefix(spec, "https://"):
+		return dohExchange(spec), nil
+	case strings.HasPrefix(spec, "tls://"):
+		addr := strings.TrimPrefix(spec, "tls://")
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DoT resolver %s: %v", spec, err)
+		}
+		client := &dns.Client{
+			Net:       "tcp-tls",
+			Timeout:   utils.DNSQueryTimeout,
+			TLSConfig: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12},
+		}
+		return clientExchange(client, addr), nil
+	case strings.HasPrefix(spec, "tcp://"):
+		client := &dns.Client{Net: "tcp", Timeout: utils.DNSQueryTimeout}
+		return clientExchange(client, strings.TrimPrefix(spec, "tcp://")), nil
+	default:
+		addr := strings.TrimPrefix(spec, "udp://")
+		udp := &dns.Client{Net: "udp", Timeout: utils.DNSQueryTimeout}
+		tcp := &dns.Client{Net: "tcp", Timeout: utils.DNSQueryTimeout}
+		return func(ctx context.Context, m *dns.Msg) (*dns.Msg, error) {
+			resp, _, err := udp.ExchangeContext(ctx, m, addr)
+			if err == nil && resp.Truncated {
+				resp, _, err = tcp.ExchangeContext(ctx, m, addr)
+			}
+			return resp, err
+		}, nil
+	}
+}
+
+func clientExchange(client *dns.Client, addr string) exchangeFunc {
+	return func(ctx context.Context, m *dns.Msg) (*dns.Msg, error) {
+		resp, _, err := client.ExchangeContext(ctx, m, addr)
+		return resp, err
+	}
+}
+
+// dohExchange implements RFC 8484 DNS-over-HTTPS using POST requests.
+func dohExchange(endpoint string) exchangeFunc {
+	client := &http.Client{Timeout: utils.DNSQueryTimeout}
+
+	return func(ctx context.Context, m *dns.Msg) (*dns.Msg, error) {
+		// RFC 8484 recommends ID 0 for cache friendliness
+		query := m.Copy()
+		query.Id = 0
+		packed, err := query.Pack()
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(packed))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/dns-message")
+		req.Header.Set("Accept", "application/dns-message")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("DoH %s returned HTTP %d", endpoint, resp.StatusCode)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+		if err != nil {
+			return nil, err
+		}
+
+		answer := new(dns.Msg)
+		if err := answer.Unpack(body); err != nil {
+			return nil, fmt.Errorf("invalid DoH response from %s: %v", endpoint, err)
+		}
+		answer.Id = m.Id
+		return answer, nil
+	}
+}
@@ -0,0 +1,233 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// maxCrawlBody is how much of a page or script the crawler reads; bundled
+// JavaScript is often far larger than the 64 KB read for titles.
+const maxCrawlBody = 2 << 20
+
+var (
+	// hostnamePattern matches anything shaped like a host name. Matches are
+	// only kept when they are in scope, which weeds out file names and
+	// property paths.
+	hostnamePattern = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}\b`)
+	// linkPattern matches the targets of href and src attributes.
+	linkPattern = regexp.MustCompile(`(?i)\b(?:href|src)\s*=\s*["']([^"'#\s]+)`)
+	// staticExtensions are linked files never worth a page of the budget.
+	staticExtensions = map[string]bool{
+		".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true,
+		".woff": true, ".woff2": true, ".ttf": true, ".eot": true,
+		".mp4": true, ".webm": true, ".mp3": true, ".pdf": true, ".zip": true,
+	}
+)
+
+// RunCrawl fetches the live HTTP results and follows their links and
+// scripts up to cfg.CrawlDepth levels, fetching at most cfg.CrawlPages
+// pages in all. Only hosts that fetchable accepts are requested, redirects
+// included, so the caller can hold the crawl to hosts whose addresses were
+// checked against the scope. It returns, sorted, every host name
+// referenced in the bodies and response headers that inScope accepts.
+func RunCrawl(ctx context.Context, cfg *config.Config, httpResults []types.HTTPResult, inScope, fetchable func(host string) bool, sink tui.EventSink) []string {
+	budget := cfg.CrawlPages
+	if budget <= 0 {
+		budget = 500
+	}
+
+	c := &crawler{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout:   time.Duration(cfg.Timeout) * time.Second,
+			Transport: probeTransport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				// Stop at the redirect; its Location header is still recorded
+				if len(via) >= 10 || !fetchable(req.URL.Hostname()) {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		},
+		inScope:   inScope,
+		fetchable: fetchable,
+		visited:   make(map[string]bool),
+		hosts:     make(map[string]bool),
+		budget:    budget,
+	}
+
+	var frontier []string
+	for _, r := range httpResults {
+		frontier = append(frontier, r.URL)
+	}
+	for depth := 0; depth <= cfg.CrawlDepth && len(frontier) > 0 && ctx.Err() == nil; depth++ {
+		frontier = c.crawlLevel(ctx, frontier, depth < cfg.CrawlDepth)
+	}
+	sink.Log("info", fmt.Sprintf("Crawled %d pages", len(c.visited)))
+
+	var hosts []string
+	for host := range c.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// crawler holds the state shared by the workers of one crawl.
+type crawler struct {
+	cfg       *config.Config
+	client    *http.Client
+	inScope   func(host string) bool // names worth recording
+	fetchable func(host string) bool // hosts that may be requested
+
+	mu      sync.Mutex
+	visited map[string]bool
+	hosts   map[string]bool
+	budget  int // pages left to fetch
+}
+
+// crawlLevel fetches every URL in urls that was not fetched before, while
+// the page budget lasts, and returns the links found on them when follow
+// is set.
+func (c *crawler) crawlLevel(ctx context.Context, urls []string, follow bool) []string {
+	threads := c.cfg.Threads
+	if threads <= 0 {
+		threads = 10
+	}
+	pool := utils.NewWorkerPool(threads, c.cfg.RateLimit)
+	defer pool.Stop()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		next []string
+	)
+	for _, u := range urls {
+		if !c.claim(u) {
+			continue
+		}
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+			links := c.fetch(ctx, u)
+			if follow {
+				mu.Lock()
+				next = append(next, links...)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return next
+}
+
+// claim marks u as visited and takes one page from the budget, reporting
+// whether u should be fetched.
+func (c *crawler) claim(u string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.visited[u] || c.budget <= 0 {
+		return false
+	}
+	c.visited[u] = true
+	c.budget--
+	return true
+}
+
+// fetch downloads pageURL, records the host names it references and
+// returns the in-scope pages and scripts it links to.
+func (c *crawler) fetch(ctx context.Context, pageURL string) []string {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil || !c.fetchable(req.URL.Hostname()) {
+		return nil
+	}
+	req.Header.Set("User-Agent", "SubdomainX/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Headers such as Location, Content-Security-Policy and
+	// Access-Control-Allow-Origin name other hosts
+	for _, values := range resp.Header {
+		for _, v := range values {
+			c.record(v)
+		}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !isCrawlable(contentType) {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxCrawlBody))
+	c.record(string(body))
+
+	if !strings.Contains(contentType, "html") {
+		return nil
+	}
+	return c.links(resp.Request.URL, body)
+}
+
+// record keeps the in-scope host names mentioned in text.
+func (c *crawler) record(text string) {
+	// JSON and JS strings escape slashes ("https:\/\/api.example.com")
+	text = strings.ReplaceAll(text, `\/`, "/")
+	for _, match := range hostnamePattern.FindAllString(text, -1) {
+		host := strings.ToLower(match)
+		if !c.inScope(host) {
+			continue
+		}
+		c.mu.Lock()
+		c.hosts[host] = true
+		c.mu.Unlock()
+	}
+}
+
+// links returns the absolute http(s) URLs of the fetchable links and
+// scripts in an HTML body.
+func (c *crawler) links(base *url.URL, body []byte) []string {
+	var links []string
+	for _, match := range linkPattern.FindAllSubmatch(body, -1) {
+		ref, err := url.Parse(string(match[1]))
+		if err != nil {
+			continue
+		}
+		u := base.ResolveReference(ref)
+		if (u.Scheme != "http" && u.Scheme != "https") || !c.fetchable(u.Hostname()) || staticExtensions[strings.ToLower(path.Ext(u.Path))] {
+			continue
+		}
+		u.Fragment = ""
+		links = append(links, u.String())
+	}
+	return links
+}
+
+// isCrawlable reports whether a response of contentType is text worth
+// searching for host names.
+func isCrawlable(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	for _, t := range []string{"html", "javascript", "json", "text/", "xml"} {
+		if strings.Contains(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// crawlSite serves a small site whose pages, scripts and headers mention
+// hosts under example.com, and counts requests for the logo.
+func crawlSite(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var logoHits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Security-Policy", "script-src 'self' https://scripts.example.com")
+		_, _ = w.Write([]byte(`<html><a href="/about">About</a><script src="/static/app.js"></script>
+<img src="/logo.png"><a href="https://other.org/">x</a><p>Served by cdn.example.com</p></html>`))
+	})
+	mux.HandleFunc("/static/app.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/javascript")
+		_, _ = w.Write([]byte(`window.cfg={"api":"https:\/\/api.example.com\/v1","file":"bundle.main.js"};`))
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<a href="/deep">Deeper</a> mail us at team@about.example.com`))
+	})
+	mux.HandleFunc("/deep", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`deep.example.com`))
+	})
+	mux.HandleFunc("/logo.png", func(w http.ResponseWriter, r *http.Request) {
+		logoHits.Add(1)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &logoHits
+}
+
+func crawlScope(host string) bool {
+	return host == "127.0.0.1" || strings.HasSuffix(host, ".example.com")
+}
+
+func TestRunCrawl(t *testing.T) {
+	server, logoHits := crawlSite(t)
+	cfg := &config.Config{Threads: 2, Timeout: 5, CrawlDepth: 1, CrawlPages: 50}
+
+	hosts := RunCrawl(context.Background(), cfg, []types.HTTPResult{{URL: server.URL + "/"}}, crawlScope, crawlScope, tui.NewCLIEventSink())
+
+	want := []string{"about.example.com", "api.example.com", "cdn.example.com", "scripts.example.com"}
+	if !slices.Equal(hosts, want) {
+		t.Errorf("Expected %v, got %v", want, hosts)
+	}
+	if logoHits.Load() != 0 {
+		t.Error("Expected images to be skipped")
+	}
+}
+
+func TestRunCrawlPageBudget(t *testing.T) {
+	server, _ := crawlSite(t)
+	cfg := &config.Config{Threads: 2, Timeout: 5, CrawlDepth: 3, CrawlPages: 1}
+
+	hosts := RunCrawl(context.Background(), cfg, []types.HTTPResult{{URL: server.URL + "/"}}, crawlScope, crawlScope, tui.NewCLIEventSink())
+
+	want := []string{"cdn.example.com", "scripts.example.com"}
+	if !slices.Equal(hosts, want) {
+		t.Errorf("Expected only the seed page's hosts %v, got %v", want, hosts)
+	}
+}
+
+func TestRunCrawlStaysOnFetchableHosts(t *testing.T) {
+	var outsideHits atomic.Int32
+	outside := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outsideHits.Add(1)
+	}))
+	t.Cleanup(outside.Close)
+	// Reachable, but through a name the crawl may not fetch
+	outsideURL := strings.Replace(outside.URL, "127.0.0.1", "localhost", 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<a href="/moved">x</a><a href="` + outsideURL + `/page">y</a>`))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, outsideURL+"/landing", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{Threads: 2, Timeout: 5, CrawlDepth: 2, CrawlPages: 50}
+	fetchable := func(host string) bool { return host == "127.0.0.1" }
+	seeds := []types.HTTPResult{{URL: server.URL + "/"}, {URL: outsideURL + "/seed"}}
+
+	hosts := RunCrawl(context.Background(), cfg, seeds, crawlScope, fetchable, tui.NewCLIEventSink())
+
+	if outsideHits.Load() != 0 {
+		t.Errorf("Expected no requests to a host that is not fetchable, got %d", outsideHits.Load())
+	}
+	if len(hosts) != 0 {
+		t.Errorf("Expected no hosts, got %v", hosts)
+	}
+}
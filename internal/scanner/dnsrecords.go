@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+// recordQueries are the questions asked for each name. The CNAME chain is
+// read from the A answer, so it needs no question of its own.
+var recordQueries = []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeMX, dns.TypeTXT, dns.TypeNS, dns.TypeSOA, dns.TypeCAA}
+
+// RunDNSRecords fills in SubdomainResult.DNS in place with the full record
+// set of every subdomain. Subdomains that already carry records (e.g. from
+// a resumed checkpoint) are skipped, and names that do not exist get none.
+// checkpoint, when non-nil, is called periodically and once at the end, as
+// in RunResolution. Queries go through the resolver carried by ctx.
+func RunDNSRecords(scanCtx context.Context, cfg *config.Config, subdomains []types.SubdomainResult, sink tui.EventSink, checkpoint func()) {
+	var pending []int
+	for i := range subdomains {
+		if subdomains[i].DNS == nil {
+			pending = append(pending, i)
+		}
+	}
+
+	total := len(subdomains)
+	done := total - len(pending)
+	sink.StageProgress("dns", done, total)
+	if len(pending) == 0 {
+		return
+	}
+
+	threads := cfg.DNSThreads
+	if threads <= 0 {
+		threads = 50
+	}
+	pool := utils.NewWorkerPool(threads, cfg.DNSRateLimit)
+	defer pool.Stop()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, i := range pending {
+		host := subdomains[i].Subdomain
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+
+			records := lookupRecords(scanCtx, host)
+
+			mu.Lock()
+			defer mu.Unlock()
+			subdomains[i].DNS = records
+			done++
+			sink.StageProgress("dns", done, total)
+			if checkpoint != nil && done%resolveCheckpointInterval == 0 {
+				checkpoint()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if checkpoint != nil {
+		checkpoint()
+	}
+}
+
+// lookupRecords queries every record type of host. It returns nil when the
+// name does not exist, unless it is an alias to a missing target, or when
+// no query was answered with records.
+func lookupRecords(scanCtx context.Context, host string) *types.DNSRecords {
+	r := resolver.FromContext(scanCtx)
+	name := dns.Fqdn(host)
+	records := &types.DNSRecords{}
+	for _, qtype := range recordQueries {
+		ctx, cancel := context.WithTimeout(scanCtx, utils.DNSQueryTimeout)
+		resp, err := r.Query(ctx, name, qtype)
+		cancel()
+		if err != nil || resp == nil {
+			continue
+		}
+		if qtype == dns.TypeA {
+			records.Set("CNAME", cnameChain(name, resp.Answer))
+		}
+		if resp.Rcode == dns.RcodeNameError {
+			break // a dangling alias still keeps its chain
+		}
+		records.Set(dns.TypeToString[qtype], recordValues(qtype, resp.Answer))
+	}
+	if records.Empty() {
+		return nil
+	}
+	return records
+}
+
+// cnameChain returns the alias targets from name onwards within an answer
+// section, in resolution order.
+func cnameChain(name string, answer []dns.RR) []string {
+	var chain []string
+	target := name
+	for hops := 0; hops < len(answer); hops++ {
+		next := ""
+		for _, rr := range answer {
+			if c, ok := rr.(*dns.CNAME); ok && strings.EqualFold(c.Hdr.Name, target) {
+				next = c.Target
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		chain = append(chain, presentName(next))
+		target = next
+	}
+	return chain
+}
+
+// recordValues returns the presentation form of the answers of type qtype.
+// Answers for the end of a CNAME chain count as the name's own.
+func recordValues(qtype uint16, answer []dns.RR) []string {
+	var values []string
+	for _, rr := range answer {
+		switch r := rr.(type) {
+		case *dns.A:
+			if qtype == dns.TypeA {
+				values = append(values, r.A.String())
+			}
+		case *dns.AAAA:
+			if qtype == dns.TypeAAAA {
+				values = append(values, r.AAAA.String())
+			}
+		case *dns.MX:
+			values = append(values, fmt.Sprintf("%d %s", r.Preference, presentName(r.Mx)))
+		case *dns.TXT:
+			values = append(values, strings.Join(r.Txt, ""))
+		case *dns.NS:
+			values = append(values, presentName(r.Ns))
+		case *dns.SOA:
+			values = append(values, fmt.Sprintf("%s %s %d %d %d %d %d",
+				presentName(r.Ns), presentName(r.Mbox), r.Serial, r.Refresh, r.Retry, r.Expire, r.Minttl))
+		case *dns.CAA:
+			values = append(values, fmt.Sprintf("%d %s %q", r.Flag, r.Tag, r.Value))
+		}
+	}
+	return values
+}
+
+// presentName lower-cases a domain name and strips its trailing dot.
+func presentName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/miekg/dns"
+)
+
+// zoneResolver answers Query from records in zone file syntax, following
+// CNAMEs the way a recursive resolver does.
+type zoneResolver struct {
+	fakeResolver
+	records []dns.RR
+}
+
+func newZoneResolver(t *testing.T, lines ...string) *zoneResolver {
+	z := &zoneResolver{}
+	for _, line := range lines {
+		rr, err := dns.NewRR(line)
+		if err != nil {
+			t.Fatalf("bad record %q: %v", line, err)
+		}
+		z.records = append(z.records, rr)
+	}
+	return z
+}
+
+func (z *zoneResolver) Query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	resp := new(dns.Msg)
+	exists := false
+	for hops := 0; hops < 8; hops++ {
+		exists = false
+		next := ""
+		for _, rr := range z.records {
+			if rr.Header().Name != name {
+				continue
+			}
+			exists = true
+			if rr.Header().Rrtype == qtype {
+				resp.Answer = append(resp.Answer, rr)
+			} else if c, ok := rr.(*dns.CNAME); ok {
+				resp.Answer = append(resp.Answer, rr)
+				next = c.Target
+			}
+		}
+		if next == "" {
+			break
+		}
+		name = next
+	}
+	if !exists {
+		resp.Rcode = dns.RcodeNameError
+	}
+	return resp, nil
+}
+
+func TestRunDNSRecords(t *testing.T) {
+	zone := newZoneResolver(t,
+		"www.example.com. 300 IN CNAME edge.example.net.",
+		"edge.example.net. 300 IN CNAME lb.example.org.",
+		"lb.example.org. 300 IN A 192.0.2.1",
+		"lb.example.org. 300 IN AAAA 2001:db8::1",
+		"example.com. 300 IN A 192.0.2.10",
+		"example.com. 300 IN MX 20 Mail2.example.com.",
+		"example.com. 300 IN MX 10 mail1.example.com.",
+		`example.com. 300 IN TXT "v=spf1 " "-all"`,
+		"example.com. 300 IN NS ns1.example.com.",
+		"example.com. 300 IN SOA ns1.example.com. hostmaster.example.com. 7 7200 3600 1209600 300",
+		`example.com. 300 IN CAA 0 issue "letsencrypt.org"`,
+		"shop.example.com. 300 IN CNAME example-shop.myshopify.com.",
+	)
+	ctx := resolver.NewContext(context.Background(), zone)
+
+	kept := &types.DNSRecords{A: []string{"198.51.100.1"}}
+	subdomains := []types.SubdomainResult{
+		{Subdomain: "www.example.com"},
+		{Subdomain: "example.com"},
+		{Subdomain: "gone.example.com"},
+		{Subdomain: "shop.example.com"},
+		{Subdomain: "kept.example.com", DNS: kept}, // resumed from a checkpoint
+	}
+	checkpoints := 0
+	RunDNSRecords(ctx, &config.Config{DNSThreads: 2}, subdomains, tui.NewCLIEventSink(), func() { checkpoints++ })
+
+	want := map[string][]string{
+		"www.example.com": {
+			"A 192.0.2.1",
+			"AAAA 2001:db8::1",
+			"CNAME edge.example.net",
+			"CNAME lb.example.org",
+		},
+		"example.com": {
+			"A 192.0.2.10",
+			"MX 10 mail1.example.com",
+			"MX 20 mail2.example.com",
+			"TXT v=spf1 -all",
+			"NS ns1.example.com",
+			"SOA ns1.example.com hostmaster.example.com 7 7200 3600 1209600 300",
+			`CAA 0 issue "letsencrypt.org"`,
+		},
+		"shop.example.com": {"CNAME example-shop.myshopify.com"}, // dangling alias
+		"kept.example.com": {"A 198.51.100.1"},
+	}
+	for _, s := range subdomains {
+		if !equalStrings(s.DNS.Lines(), want[s.Subdomain]) {
+			t.Errorf("%s: records = %v, want %v", s.Subdomain, s.DNS.Lines(), want[s.Subdomain])
+		}
+	}
+	if subdomains[2].DNS != nil {
+		t.Errorf("Expected no records for a name that does not exist, got %v", subdomains[2].DNS)
+	}
+	if subdomains[4].DNS != kept {
+		t.Error("Expected records from a checkpoint to be kept")
+	}
+	if checkpoints != 1 {
+		t.Errorf("Expected one final checkpoint, got %d", checkpoints)
+	}
+}
@@ -64,6 +64,9 @@ var headerValueFingerprints = []headerValueFingerprint{
 	{Header: "X-Cache", Contains: "cloudfront", Name: "AWS CloudFront", Category: "CDN"},
 	{Header: "X-Amz-Cf-Id", Contains: "", Name: "AWS CloudFront", Category: "CDN"},
 	{Header: "X-Azure-Ref", Contains: "", Name: "Azure CDN", Category: "CDN"},
+	{Header: "X-Iinfo", Contains: "", Name: "Imperva Incapsula", Category: "WAF"},
+	{Header: "X-Sucuri-ID", Contains: "", Name: "Sucuri", Category: "WAF"},
+	{Header: "X-Served-By", Contains: "cache-", Name: "Fastly", Category: "CDN"},
 	{Header: "Via", Contains: "vegur", Name: "Heroku", Category: "PaaS"},
 	{Header: "Via", Contains: "varnish", Name: "Varnish", Category: "Cache"},
 	{Header: "X-Vercel-Id", Contains: "", Name: "Vercel", Category: "PaaS"},
@@ -183,6 +186,20 @@ func FingerprintTechnologies(resp *http.Response, body []byte) []types.Technolog
 	return techs
 }
 
+// DetectCDN returns the CDN or WAF whose headers are on resp, or "".
+func DetectCDN(resp *http.Response) string {
+	for _, fp := range headerValueFingerprints {
+		if fp.Category != "CDN" && fp.Category != "WAF" {
+			continue
+		}
+		val := resp.Header.Get(fp.Header)
+		if val != "" && (fp.Contains == "" || strings.Contains(strings.ToLower(val), strings.ToLower(fp.Contains))) {
+			return fp.Name
+		}
+	}
+	return ""
+}
+
 // parseServerHeader extracts name and version from a Server header value
 // like "nginx/1.24.0" or "Apache/2.4.52 (Ubuntu)".
 func parseServerHeader(val string) (string, string) {
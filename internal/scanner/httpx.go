@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
 	"github.com/itszeeshan/subdomainx/v2/internal/types"
@@ -30,12 +31,18 @@ func (h *HTTPXScanner) Scan(ctx context.Context, targets []string, cfg *config.C
 		"-tech-detect",
 		"-status-code",
 		"-content-length",
+		"-tls-grab",       // certificate details for the HTTPS URLs
+		"-cdn",            // CDN or WAF in front of each host
+		"-hash", "sha256", // body hash, to match hosts serving the same site
 		"-rate-limit", "1000", // Increase rate limit
 		"-threads", "50", // Increase threads
 		"-timeout", "10", // Reduce timeout
 		"-follow-redirects", // Follow redirects
 		"-no-color",         // Disable colors for faster output
 	}
+	if cfg.OriginCheck {
+		args = append(args, "-favicon")
+	}
 
 	cmd := exec.CommandContext(ctx, "httpx", args...)
 
@@ -70,6 +77,22 @@ func (h *HTTPXScanner) Scan(ctx context.Context, targets []string, cfg *config.C
 			Title         string   `json:"title"`
 			ContentLength int      `json:"content_length"`
 			Technologies  []string `json:"tech"`
+			Favicon       string   `json:"favicon"`
+			CDNName       string   `json:"cdn_name"`
+			Hash          struct {
+				BodySHA256 string `json:"body_sha256"`
+			} `json:"hash"`
+			TLS *struct {
+				SubjectCN   string    `json:"subject_cn"`
+				SubjectAN   []string  `json:"subject_an"`
+				IssuerCN    string    `json:"issuer_cn"`
+				IssuerOrg   []string  `json:"issuer_org"`
+				NotBefore   time.Time `json:"not_before"`
+				NotAfter    time.Time `json:"not_after"`
+				Fingerprint struct {
+					SHA256 string `json:"sha256"`
+				} `json:"fingerprint_hash"`
+			} `json:"tls"`
 		}
 
 		if err := json.Unmarshal([]byte(line), &httpxResult); err != nil {
@@ -82,6 +105,22 @@ func (h *HTTPXScanner) Scan(ctx context.Context, targets []string, cfg *config.C
 			Title:         httpxResult.Title,
 			ContentLength: httpxResult.ContentLength,
 			Technologies:  httpxResult.Technologies,
+			BodyHash:      httpxResult.Hash.BodySHA256,
+			FaviconHash:   httpxResult.Favicon,
+			CDN:           httpxResult.CDNName,
+		}
+		if tls := httpxResult.TLS; tls != nil {
+			result.TLS = &types.TLSCert{
+				SubjectCN:   tls.SubjectCN,
+				SANs:        tls.SubjectAN,
+				IssuerCN:    tls.IssuerCN,
+				NotBefore:   tls.NotBefore,
+				NotAfter:    tls.NotAfter,
+				Fingerprint: tls.Fingerprint.SHA256,
+			}
+			if len(tls.IssuerOrg) > 0 {
+				result.TLS.IssuerOrg = tls.IssuerOrg[0]
+			}
 		}
 
 		results = append(results, result)
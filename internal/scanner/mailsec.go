@@ -0,0 +1,531 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+	"github.com/miekg/dns"
+)
+
+// spfLookupLimit is the number of DNS-querying SPF terms a receiver
+// evaluates before failing with PermError (RFC 7208, section 4.6.4).
+const spfLookupLimit = 10
+
+// spfMaxDepth bounds how deep include and redirect chains are followed.
+const spfMaxDepth = 10
+
+// DefaultDKIMSelectors are the selectors probed on every apex, covering
+// the common mail providers; cfg.DKIMSelectors adds to them.
+var DefaultDKIMSelectors = []string{
+	"default", "dkim", "mail", "email", "smtp", "k1", "k2", "k3", "s1", "s2",
+	"selector1", "selector2", "google", "mandrill", "mxvault", "zoho",
+	"protonmail", "protonmail2", "protonmail3", "fm1", "fm2", "fm3",
+	"everlytickey1", "everlytickey2", "amazonses", "pm", "mailjet", "sig1",
+}
+
+// mailPosture is what one apex publishes for email authentication, with
+// the findings its records raise.
+type mailPosture struct {
+	apex     string
+	findings []types.Finding
+	details  []string // the evidence chain of the summary finding
+	hosts    []string // host names named by SPF and MTA-STS
+}
+
+func (p *mailPosture) add(findingType, severity, title, evidence string, details ...string) {
+	p.findings = append(p.findings, types.Finding{
+		Type:     findingType,
+		Severity: severity,
+		Target:   p.apex,
+		Title:    title,
+		Evidence: evidence,
+		Details:  details,
+	})
+}
+
+// RunEmailSecurity checks the email authentication posture of every apex:
+// its SPF record and include chain, DMARC policy, DKIM keys under common
+// selectors, MTA-STS, TLS-RPT and BIMI. It returns one summary finding per
+// apex with the records found, a finding per weakness, and the host names
+// the records point at so they can be fed back as subdomains. DNS queries
+// go through the resolver carried by ctx.
+func RunEmailSecurity(ctx context.Context, cfg *config.Config, apexes []string, sink tui.EventSink) ([]types.Finding, []string) {
+	selectors := append(slices.Clone(DefaultDKIMSelectors), cfg.DKIMSelectors...)
+	client := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+
+	var (
+		findings []types.Finding
+		hosts    []string
+	)
+	for i, apex := range apexes {
+		p := &mailPosture{apex: apex}
+		receivesMail := checkMX(ctx, p)
+		checkSPF(ctx, p)
+		enforced := checkDMARC(ctx, p)
+		checkDKIM(ctx, p, selectors)
+		checkMTASTS(ctx, p, client, receivesMail)
+		checkBIMI(ctx, p, enforced)
+
+		findings = append(findings, types.Finding{
+			Type:     "email-posture",
+			Severity: types.SeverityInfo,
+			Target:   apex,
+			Title:    fmt.Sprintf("Email authentication records for %s", apex),
+			Evidence: fmt.Sprintf("%d issues found", len(p.findings)),
+			Details:  p.details,
+		})
+		findings = append(findings, p.findings...)
+		hosts = append(hosts, p.hosts...)
+		sink.StageProgress("email", i+1, len(apexes))
+	}
+	return findings, hosts
+}
+
+// checkMX records the apex's mail exchangers and reports whether it
+// receives mail at all (a null MX says it does not).
+func checkMX(ctx context.Context, p *mailPosture) bool {
+	resp, err := queryDNS(ctx, p.apex, dns.TypeMX)
+	if err != nil {
+		p.details = append(p.details, "MX: lookup failed: "+err.Error())
+		return false
+	}
+	var mxs []string
+	for _, rr := range resp.Answer {
+		if mx, ok := rr.(*dns.MX); ok {
+			mxs = append(mxs, fmt.Sprintf("%d %s", mx.Preference, presentName(mx.Mx)))
+		}
+	}
+	if len(mxs) == 0 {
+		p.details = append(p.details, "MX: none")
+		return false
+	}
+	slices.Sort(mxs)
+	p.details = append(p.details, "MX: "+strings.Join(mxs, ", "))
+	return !(len(mxs) == 1 && mxs[0] == "0 ") // RFC 7505 null MX
+}
+
+// spfWalk follows an SPF record through its include and redirect terms.
+type spfWalk struct {
+	ctx     context.Context
+	p       *mailPosture
+	lookups int
+	seen    map[string]bool
+	broken  []string // include and redirect targets without a usable record
+	ptr     bool
+}
+
+// checkSPF parses the apex's SPF record and its include chain.
+func checkSPF(ctx context.Context, p *mailPosture) {
+	records, err := txtRecords(ctx, p.apex, "v=spf1")
+	if err != nil {
+		p.details = append(p.details, "SPF: lookup failed: "+err.Error())
+		return
+	}
+	switch len(records) {
+	case 0:
+		p.details = append(p.details, "SPF: none")
+		p.add("spf-missing", types.SeverityMedium, fmt.Sprintf("No SPF record for %s", p.apex),
+			"no TXT record starting with v=spf1; any host can send mail as this domain")
+		return
+	case 1:
+	default:
+		p.details = append(p.details, "SPF: "+strings.Join(records, " | "))
+		p.add("spf-multiple-records", types.SeverityMedium, fmt.Sprintf("Multiple SPF records for %s", p.apex),
+			"receivers fail SPF with PermError when more than one v=spf1 record is published", records...)
+		return
+	}
+
+	w := &spfWalk{ctx: ctx, p: p, seen: map[string]bool{p.apex: true}}
+	p.details = append(p.details, "SPF: "+records[0])
+	chainStart := len(p.details)
+	all := w.walk(records[0], 1)
+	chain := slices.Clone(p.details[chainStart:])
+
+	if w.lookups > spfLookupLimit {
+		p.add("spf-lookup-limit", types.SeverityMedium,
+			fmt.Sprintf("SPF record for %s needs %d DNS lookups (limit %d)", p.apex, w.lookups, spfLookupLimit),
+			"receivers stop after 10 lookups and fail SPF with PermError", chain...)
+	}
+	for _, target := range w.broken {
+		p.add("spf-broken-include", types.SeverityMedium,
+			fmt.Sprintf("SPF record for %s includes %s, which has no SPF record", p.apex, target),
+			"receivers fail SPF with PermError; if the domain is unregistered, whoever registers it can authorise their own senders", chain...)
+	}
+	if w.ptr {
+		p.add("spf-ptr", types.SeverityLow, fmt.Sprintf("SPF record for %s uses the ptr mechanism", p.apex),
+			"ptr is deprecated (RFC 7208, section 5.5), slow and unreliable", records[0])
+	}
+	switch all {
+	case "+all", "all":
+		p.add("spf-pass-all", types.SeverityHigh, fmt.Sprintf("SPF record for %s authorises every sender (+all)", p.apex),
+			"any host on the internet passes SPF for this domain", records[0])
+	case "?all":
+		p.add("spf-neutral-all", types.SeverityMedium, fmt.Sprintf("SPF record for %s ends in ?all", p.apex),
+			"mail from unlisted hosts gets a neutral result and is not rejected", records[0])
+	case "~all":
+		p.add("spf-softfail-all", types.SeverityLow, fmt.Sprintf("SPF record for %s ends in ~all", p.apex),
+			"mail from unlisted hosts only soft-fails; without an enforcing DMARC policy it is usually delivered", records[0])
+	case "":
+		p.add("spf-no-all", types.SeverityMedium, fmt.Sprintf("SPF record for %s has no all mechanism", p.apex),
+			"mail from unlisted hosts gets the default neutral result", records[0])
+	}
+	p.details = append(p.details, fmt.Sprintf("SPF: %d DNS lookups", w.lookups))
+}
+
+// walk evaluates the terms of record at depth and returns its all term,
+// or that of its redirect target when it has none.
+func (w *spfWalk) walk(record string, depth int) string {
+	indent := strings.Repeat("  ", depth)
+	all, redirect := "", ""
+	for _, term := range strings.Fields(record)[1:] {
+		lower := strings.ToLower(term)
+		if name, value, ok := strings.Cut(lower, "="); ok && !strings.ContainsAny(name, ":/") {
+			if name == "redirect" {
+				redirect = value
+			}
+			continue // exp= and unknown modifiers
+		}
+		mechanism := strings.TrimLeft(lower, "+-~?")
+		name, arg, _ := strings.Cut(mechanism, ":")
+		name, _, _ = strings.Cut(name, "/")
+		switch name {
+		case "all":
+			all = lower
+		case "include":
+			w.lookups++
+			w.follow(arg, indent+"include:", depth)
+		case "a", "mx":
+			w.lookups++
+			w.addHost(arg)
+		case "ptr":
+			w.lookups++
+			w.ptr = true
+		case "exists":
+			w.lookups++
+		}
+	}
+	if redirect != "" && all == "" {
+		w.lookups++
+		return w.follow(redirect, indent+"redirect=", depth)
+	}
+	return all
+}
+
+// follow walks the SPF record of target, named by an include or redirect
+// term, and returns its all term.
+func (w *spfWalk) follow(target, label string, depth int) string {
+	if target == "" || strings.Contains(target, "%") {
+		return "" // macros are expanded per message
+	}
+	target = strings.TrimSuffix(target, ".")
+	w.addHost(target)
+	if w.seen[target] || depth >= spfMaxDepth {
+		w.p.details = append(w.p.details, label+target+" (already followed)")
+		return ""
+	}
+	w.seen[target] = true
+
+	records, err := txtRecords(w.ctx, target, "v=spf1")
+	if err != nil {
+		w.p.details = append(w.p.details, label+target+" (lookup failed)")
+		return ""
+	}
+	if len(records) != 1 {
+		w.p.details = append(w.p.details, fmt.Sprintf("%s%s (%d SPF records)", label, target, len(records)))
+		w.broken = append(w.broken, target)
+		return ""
+	}
+	w.p.details = append(w.p.details, label+target+" -> "+records[0])
+	return w.walk(records[0], depth+1)
+}
+
+// addHost keeps a host name from an SPF term, without CIDR lengths.
+// Names with macros or underscore labels ("_spf.example.com") name no host.
+func (w *spfWalk) addHost(host string) {
+	host, _, _ = strings.Cut(host, "/")
+	host = strings.TrimSuffix(host, ".")
+	if host != "" && !strings.ContainsAny(host, "%_") && !slices.Contains(w.p.hosts, host) {
+		w.p.hosts = append(w.p.hosts, host)
+	}
+}
+
+// checkDMARC parses the apex's DMARC policy and reports whether it is
+// enforced (quarantine or reject).
+func checkDMARC(ctx context.Context, p *mailPosture) bool {
+	records, err := txtRecords(ctx, "_dmarc."+p.apex, "v=DMARC1")
+	if err != nil {
+		p.details = append(p.details, "DMARC: lookup failed: "+err.Error())
+		return false
+	}
+	switch len(records) {
+	case 0:
+		p.details = append(p.details, "DMARC: none")
+		p.add("dmarc-missing", types.SeverityMedium, fmt.Sprintf("No DMARC record for %s", p.apex),
+			"no v=DMARC1 record at _dmarc."+p.apex+"; receivers get no policy for mail that fails SPF and DKIM")
+		return false
+	case 1:
+	default:
+		p.details = append(p.details, "DMARC: "+strings.Join(records, " | "))
+		p.add("dmarc-multiple-records", types.SeverityMedium, fmt.Sprintf("Multiple DMARC records for %s", p.apex),
+			"receivers ignore DMARC when more than one record is published", records...)
+		return false
+	}
+	record := records[0]
+	p.details = append(p.details, "DMARC: "+record)
+
+	tags := parseTags(record)
+	policy := strings.ToLower(tags["p"])
+	switch policy {
+	case "none":
+		p.add("dmarc-policy-none", types.SeverityMedium, fmt.Sprintf("DMARC policy for %s only monitors (p=none)", p.apex),
+			"mail failing SPF and DKIM is still delivered", record)
+	case "quarantine", "reject":
+	default:
+		p.add("dmarc-invalid", types.SeverityMedium, fmt.Sprintf("DMARC record for %s has no valid policy", p.apex),
+			fmt.Sprintf("p=%q; receivers ignore the record", tags["p"]), record)
+		return false
+	}
+	enforced := policy != "none"
+
+	if pct, err := strconv.Atoi(tags["pct"]); err == nil && pct < 100 && enforced {
+		p.add("dmarc-partial", types.SeverityLow, fmt.Sprintf("DMARC policy for %s applies to %d%% of mail", p.apex, pct),
+			"the rest of the failing mail is treated as p=none", record)
+	}
+	if sp := strings.ToLower(tags["sp"]); sp == "none" && enforced {
+		p.add("dmarc-subdomain-policy-none", types.SeverityLow, fmt.Sprintf("DMARC policy for subdomains of %s only monitors (sp=none)", p.apex),
+			"mail spoofing any subdomain is still delivered", record)
+	}
+	if tags["rua"] == "" {
+		p.add("dmarc-no-reporting", types.SeverityInfo, fmt.Sprintf("DMARC record for %s requests no aggregate reports", p.apex),
+			"without rua the owner does not see who sends mail as the domain", record)
+	}
+	return enforced
+}
+
+// checkDKIM probes selectors for DKIM keys and checks the keys found.
+func checkDKIM(ctx context.Context, p *mailPosture, selectors []string) {
+	type key struct{ selector, record string }
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found []key
+	)
+	for _, selector := range slices.Compact(slices.Sorted(slices.Values(selectors))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records, err := txtRecords(ctx, selector+"._domainkey."+p.apex, "")
+			if err != nil {
+				return
+			}
+			for _, r := range records {
+				if strings.HasPrefix(r, "v=DKIM1") || strings.Contains(r, "p=") {
+					mu.Lock()
+					found = append(found, key{selector, r})
+					mu.Unlock()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(found) == 0 {
+		p.details = append(p.details, fmt.Sprintf("DKIM: no key under %d common selectors", len(selectors)))
+		p.add("dkim-not-found", types.SeverityInfo, fmt.Sprintf("No DKIM key found for %s under common selectors", p.apex),
+			"selectors probed: "+strings.Join(selectors, ", "))
+		return
+	}
+	slices.SortFunc(found, func(a, b key) int { return strings.Compare(a.selector, b.selector) })
+	for _, k := range found {
+		name := k.selector + "._domainkey." + p.apex
+		tags := parseTags(k.record)
+		bits := rsaKeyBits(tags)
+		switch {
+		case tags["p"] == "":
+			p.details = append(p.details, fmt.Sprintf("DKIM: %s revoked (empty p=)", name))
+			continue
+		case bits > 0:
+			p.details = append(p.details, fmt.Sprintf("DKIM: %s (%d-bit RSA)", name, bits))
+		default:
+			p.details = append(p.details, fmt.Sprintf("DKIM: %s (k=%s)", name, tags["k"]))
+		}
+		if bits > 0 && bits < 1024 {
+			p.add("dkim-weak-key", types.SeverityMedium, fmt.Sprintf("DKIM key %s is %d-bit RSA", name, bits),
+				"RSA keys under 1024 bits can be factored to sign mail as the domain", k.record)
+		}
+		if slices.Contains(strings.Split(tags["t"], ":"), "y") {
+			p.add("dkim-testing", types.SeverityLow, fmt.Sprintf("DKIM key %s is in testing mode (t=y)", name),
+				"receivers may treat signatures with this key as unsigned", k.record)
+		}
+	}
+}
+
+// rsaKeyBits returns the size of the RSA key in DKIM tags, or 0 when it is
+// not an RSA key or cannot be parsed.
+func rsaKeyBits(tags map[string]string) int {
+	if k := strings.ToLower(tags["k"]); k != "" && k != "rsa" {
+		return 0
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(tags["p"]), ""))
+	if err != nil {
+		return 0
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		if rsaPub, err := x509.ParsePKCS1PublicKey(der); err == nil {
+			return rsaPub.N.BitLen()
+		}
+		return 0
+	}
+	if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+		return rsaPub.N.BitLen()
+	}
+	return 0
+}
+
+// checkMTASTS checks the MTA-STS policy and TLS-RPT record of an apex that
+// receives mail.
+func checkMTASTS(ctx context.Context, p *mailPosture, client *http.Client, receivesMail bool) {
+	records, _ := txtRecords(ctx, "_mta-sts."+p.apex, "v=STSv1")
+	switch {
+	case len(records) == 0:
+		p.details = append(p.details, "MTA-STS: none")
+		if receivesMail {
+			p.add("mta-sts-missing", types.SeverityInfo, fmt.Sprintf("No MTA-STS policy for %s", p.apex),
+				"sending servers may deliver mail to this domain without TLS or to a spoofed MX")
+		}
+	default:
+		policyHost := "mta-sts." + p.apex
+		p.hosts = append(p.hosts, policyHost)
+		mode, err := fetchMTASTSMode(ctx, client, policyHost)
+		if err != nil {
+			p.details = append(p.details, fmt.Sprintf("MTA-STS: %s, policy unavailable: %v", records[0], err))
+			p.add("mta-sts-policy-unavailable", types.SeverityMedium, fmt.Sprintf("MTA-STS policy for %s cannot be fetched", p.apex),
+				fmt.Sprintf("https://%s/.well-known/mta-sts.txt: %v; senders ignore the _mta-sts record", policyHost, err), records[0])
+			break
+		}
+		p.details = append(p.details, fmt.Sprintf("MTA-STS: %s, mode %s", records[0], mode))
+		if mode != "enforce" {
+			p.add("mta-sts-not-enforced", types.SeverityLow, fmt.Sprintf("MTA-STS policy for %s is in %s mode", p.apex, mode),
+				"senders still deliver when TLS to the MX fails", "mode: "+mode)
+		}
+	}
+
+	records, _ = txtRecords(ctx, "_smtp._tls."+p.apex, "v=TLSRPTv1")
+	if len(records) == 0 {
+		p.details = append(p.details, "TLS-RPT: none")
+		if receivesMail {
+			p.add("tls-rpt-missing", types.SeverityInfo, fmt.Sprintf("No TLS-RPT record for %s", p.apex),
+				"the owner gets no reports of failed TLS deliveries")
+		}
+		return
+	}
+	p.details = append(p.details, "TLS-RPT: "+records[0])
+}
+
+// fetchMTASTSMode fetches the MTA-STS policy from host and returns its mode.
+func fetchMTASTSMode(ctx context.Context, client *http.Client, host string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://"+host+"/.well-known/mta-sts.txt", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "SubdomainX/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 64*1024))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == "mode" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", fmt.Errorf("policy has no mode")
+}
+
+// checkBIMI records the apex's BIMI record, which mail clients only honour
+// under an enforced DMARC policy.
+func checkBIMI(ctx context.Context, p *mailPosture, dmarcEnforced bool) {
+	records, _ := txtRecords(ctx, "default._bimi."+p.apex, "v=BIMI1")
+	if len(records) == 0 {
+		p.details = append(p.details, "BIMI: none")
+		return
+	}
+	p.details = append(p.details, "BIMI: "+records[0])
+	if !dmarcEnforced {
+		p.add("bimi-without-enforcement", types.SeverityLow, fmt.Sprintf("BIMI record for %s without an enforced DMARC policy", p.apex),
+			"mail clients only show the logo when DMARC is p=quarantine or p=reject", records[0])
+	}
+}
+
+// queryDNS sends one question for name through the scan resolver.
+func queryDNS(scanCtx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	ctx, cancel := context.WithTimeout(scanCtx, utils.DNSQueryTimeout)
+	defer cancel()
+	resp, err := resolver.FromContext(ctx).Query(ctx, dns.Fqdn(name), qtype)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("%s", dns.RcodeToString[resp.Rcode])
+	}
+	return resp, nil
+}
+
+// txtRecords returns the TXT records of name that start with the version
+// tag version (case-insensitively), or all of them when version is empty.
+func txtRecords(ctx context.Context, name, version string) ([]string, error) {
+	resp, err := queryDNS(ctx, name, dns.TypeTXT)
+	if err != nil {
+		return nil, err
+	}
+	var records []string
+	for _, rr := range resp.Answer {
+		txt, ok := rr.(*dns.TXT)
+		if !ok {
+			continue
+		}
+		record := strings.TrimSpace(strings.Join(txt.Txt, ""))
+		if version != "" {
+			tag, _, _ := strings.Cut(record, ";")
+			if first := strings.Fields(tag); len(first) == 0 || !strings.EqualFold(first[0], version) {
+				continue
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseTags parses a "k=v; k=v" record into its tags.
+func parseTags(record string) map[string]string {
+	tags := make(map[string]string)
+	for _, part := range strings.Split(record, ";") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return tags
+}
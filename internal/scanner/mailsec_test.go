@@ -0,0 +1,208 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// weakDKIMKey is a 512-bit RSA public key.
+const weakDKIMKey = "MFwwDQYJKoZIhvcNAQEBBQADSwAwSAJBANy/tOW00Ez1i+My2SiR8qb+9dQcJmNAJLZo3hL2uuqprNsCsl7wKrg85G1p1h0QNCi5N6zJm2jZYvT87Y2DVdcCAwEAAQ=="
+
+func TestRunEmailSecurity(t *testing.T) {
+	zone := newZoneResolver(t,
+		"example.com. 300 IN MX 10 mx.example.com.",
+		`example.com. 300 IN TXT "v=spf1 include:_spf.example.net include:missing.example.org mx ~all"`,
+		`example.com. 300 IN TXT "google-site-verification=abc"`,
+		`_spf.example.net. 300 IN TXT "v=spf1 a mx exists:%{i}.spf.example.net include:c.example.net -all"`,
+		`c.example.net. 300 IN TXT "v=spf1 a a a a a a:relay.example.com/24 -all"`,
+		`_dmarc.example.com. 300 IN TXT "v=DMARC1; p=none"`,
+		`selector1._domainkey.example.com. 300 IN TXT "v=DKIM1; k=rsa; p=`+weakDKIMKey+`"`,
+	)
+	ctx := resolver.NewContext(context.Background(), zone)
+
+	findings, hosts := RunEmailSecurity(ctx, &config.Config{Timeout: 1}, []string{"example.com"}, tui.NewCLIEventSink())
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Type+"/"+f.Severity)
+	}
+	want := []string{
+		"email-posture/info",
+		"spf-lookup-limit/medium",
+		"spf-broken-include/medium",
+		"spf-softfail-all/low",
+		"dmarc-policy-none/medium",
+		"dmarc-no-reporting/info",
+		"dkim-weak-key/medium",
+		"mta-sts-missing/info",
+		"tls-rpt-missing/info",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+
+	limit := findings[slices.IndexFunc(findings, func(f types.Finding) bool { return f.Type == "spf-lookup-limit" })]
+	if limit.Title != "SPF record for example.com needs 13 DNS lookups (limit 10)" {
+		t.Errorf("Unexpected lookup-limit title %q", limit.Title)
+	}
+	if !slices.Contains(limit.Details, "    include:c.example.net -> v=spf1 a a a a a a:relay.example.com/24 -all") {
+		t.Errorf("Expected the include chain in the details, got %v", limit.Details)
+	}
+
+	wantHosts := []string{"c.example.net", "relay.example.com", "missing.example.org"}
+	if !slices.Equal(hosts, wantHosts) {
+		t.Errorf("hosts = %v, want %v", hosts, wantHosts)
+	}
+}
+
+// findingTypes returns the types of the findings p raised, in order.
+func findingTypes(p *mailPosture) []string {
+	var got []string
+	for _, f := range p.findings {
+		got = append(got, f.Type)
+	}
+	return got
+}
+
+func TestCheckSPF(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []string
+		want    []string
+		lookups int
+		details []string // expected among the evidence chain
+	}{
+		{
+			name: "include recursion",
+			records: []string{
+				`example.com. 300 IN TXT "v=spf1 include:a.example.net -all"`,
+				`a.example.net. 300 IN TXT "v=spf1 include:b.example.net -all"`,
+				`b.example.net. 300 IN TXT "v=spf1 mx:mail.example.net -all"`,
+			},
+			lookups: 3,
+			details: []string{
+				"  include:a.example.net -> v=spf1 include:b.example.net -all",
+				"    include:b.example.net -> v=spf1 mx:mail.example.net -all",
+			},
+		},
+		{
+			name: "include loop",
+			records: []string{
+				`example.com. 300 IN TXT "v=spf1 include:a.example.net -all"`,
+				`a.example.net. 300 IN TXT "v=spf1 include:example.com -all"`,
+			},
+			lookups: 2,
+			details: []string{"    include:example.com (already followed)"},
+		},
+		{
+			name:    "ten lookups",
+			records: []string{`example.com. 300 IN TXT "v=spf1 a a a a a mx mx mx mx exists:x.example.com -all"`},
+			lookups: 10,
+		},
+		{
+			name:    "eleven lookups",
+			records: []string{`example.com. 300 IN TXT "v=spf1 a a a a a mx mx mx mx mx ptr -all"`},
+			want:    []string{"spf-lookup-limit", "spf-ptr"},
+			lookups: 11,
+		},
+		{
+			name: "redirect",
+			records: []string{
+				`example.com. 300 IN TXT "v=spf1 redirect=_spf.example.net"`,
+				`_spf.example.net. 300 IN TXT "v=spf1 mx ?all"`,
+			},
+			want:    []string{"spf-neutral-all"},
+			lookups: 2,
+			details: []string{"  redirect=_spf.example.net -> v=spf1 mx ?all"},
+		},
+		{
+			name: "redirect ignored with all",
+			records: []string{
+				`example.com. 300 IN TXT "v=spf1 redirect=_spf.example.net -all"`,
+				`_spf.example.net. 300 IN TXT "v=spf1 +all"`,
+			},
+		},
+		{
+			name:    "pass all",
+			records: []string{`example.com. 300 IN TXT "v=spf1 +all"`},
+			want:    []string{"spf-pass-all"},
+		},
+		{
+			name:    "bare all",
+			records: []string{`example.com. 300 IN TXT "v=spf1 all"`},
+			want:    []string{"spf-pass-all"},
+		},
+		{
+			name:    "neutral all",
+			records: []string{`example.com. 300 IN TXT "v=spf1 ?all"`},
+			want:    []string{"spf-neutral-all"},
+		},
+		{
+			name:    "no all",
+			records: []string{`example.com. 300 IN TXT "v=spf1 ip4:192.0.2.0/24"`},
+			want:    []string{"spf-no-all"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := resolver.NewContext(context.Background(), newZoneResolver(t, tt.records...))
+			p := &mailPosture{apex: "example.com"}
+			checkSPF(ctx, p)
+
+			if got := findingTypes(p); !slices.Equal(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+			if last, want := p.details[len(p.details)-1], fmt.Sprintf("SPF: %d DNS lookups", tt.lookups); last != want {
+				t.Errorf("last detail = %q, want %q", last, want)
+			}
+			for _, d := range tt.details {
+				if !slices.Contains(p.details, d) {
+					t.Errorf("Expected %q in the details, got %q", d, p.details)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDMARC(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   string
+		want     []string
+		enforced bool
+	}{
+		{"reject", "v=DMARC1; p=reject; rua=mailto:d@example.com", nil, true},
+		{"subdomains not enforced", "v=DMARC1; p=reject; sp=none; rua=mailto:d@example.com", []string{"dmarc-subdomain-policy-none"}, true},
+		{"subdomains quarantined", "v=DMARC1; p=reject; sp=quarantine; rua=mailto:d@example.com", nil, true},
+		{"sp=none under p=none", "v=DMARC1; p=none; sp=none; rua=mailto:d@example.com", []string{"dmarc-policy-none"}, false},
+		{"partial", "v=DMARC1; p=quarantine; pct=25; rua=mailto:d@example.com", []string{"dmarc-partial"}, true},
+		{"full", "v=DMARC1; p=quarantine; pct=100; rua=mailto:d@example.com", nil, true},
+		{"pct under p=none", "v=DMARC1; p=none; pct=25; rua=mailto:d@example.com", []string{"dmarc-policy-none"}, false},
+		{"unparsable pct", "v=DMARC1; p=reject; pct=half; rua=mailto:d@example.com", nil, true},
+		{"no reporting", "v=DMARC1; p=reject", []string{"dmarc-no-reporting"}, true},
+		{"invalid policy", "v=DMARC1; p=block; sp=none; pct=10", []string{"dmarc-invalid"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := newZoneResolver(t, `_dmarc.example.com. 300 IN TXT "`+tt.record+`"`)
+			ctx := resolver.NewContext(context.Background(), zone)
+			p := &mailPosture{apex: "example.com"}
+
+			if enforced := checkDMARC(ctx, p); enforced != tt.enforced {
+				t.Errorf("enforced = %v, want %v", enforced, tt.enforced)
+			}
+			if got := findingTypes(p); !slices.Equal(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package scanner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// cdnCNAMESuffixes name the CDN or WAF behind a CNAME target.
+var cdnCNAMESuffixes = []struct{ suffix, name string }{
+	{".cdn.cloudflare.net", "Cloudflare"},
+	{".cloudfront.net", "AWS CloudFront"},
+	{".akamaiedge.net", "Akamai"},
+	{".edgekey.net", "Akamai"},
+	{".edgesuite.net", "Akamai"},
+	{".akamaized.net", "Akamai"},
+	{".fastly.net", "Fastly"},
+	{".fastlylb.net", "Fastly"},
+	{".incapdns.net", "Imperva Incapsula"},
+	{".impervadns.net", "Imperva"},
+	{".azureedge.net", "Azure CDN"},
+	{".azurefd.net", "Azure Front Door"},
+	{".sucuri.net", "Sucuri"},
+	{".stackpathdns.com", "StackPath"},
+	{".edgecastcdn.net", "Edgecast"},
+	{".b-cdn.net", "BunnyCDN"},
+	{".cdn77.org", "CDN77"},
+}
+
+// cdnTechNames are substrings of the technology names httpx gives CDNs and
+// WAFs, in lower case.
+var cdnTechNames = []string{
+	"cloudflare", "cloudfront", "akamai", "imperva", "incapsula", "fastly", "sucuri",
+	"azure cdn", "azure front door", "stackpath", "edgecast", "bunnycdn", "cdn77", "keycdn",
+}
+
+// cdnProviders are the IP enrichment providers whose addresses only front
+// other sites.
+var cdnProviders = map[string]bool{
+	"cloudflare": true, "akamai": true, "fastly": true, "imperva": true,
+	"incapsula": true, "sucuri": true, "stackpath": true,
+}
+
+// Kinds of evidence that two hosts serve the same site, in report order,
+// with their weights. A pair is reported from originReportScore up and as
+// high severity from originHighScore up: the same body is enough on its
+// own, a shared title, favicon or certificate (often a wildcard one) needs
+// a second signal.
+var originSignalWeights = []struct {
+	kind   string
+	weight int
+}{
+	{"body", 2},
+	{"favicon", 1},
+	{"certificate", 1},
+	{"title", 1},
+}
+
+const (
+	originReportScore = 2
+	originHighScore   = 3
+)
+
+// originHost is what the origin analysis knows about one subdomain.
+type originHost struct {
+	name      string
+	ips       []string
+	infos     []types.IPInfo
+	cdn       string              // CDN or WAF in front, "" when reached directly
+	cdnReason string              // how cdn was detected
+	signals   map[string][]string // evidence values by kind
+}
+
+func (h *originHost) setCDN(name, reason string) {
+	if h.cdn == "" {
+		h.cdn, h.cdnReason = name, reason
+	}
+}
+
+func (h *originHost) addSignal(kind, value string) {
+	if value != "" && !slices.Contains(h.signals[kind], value) {
+		h.signals[kind] = append(h.signals[kind], value)
+	}
+}
+
+// FindExposedOrigins looks for sites served through a CDN or WAF that a
+// sibling subdomain also serves from a direct address, which lets anyone
+// bypass the protection. Hosts are matched on their certificate
+// fingerprint, body hash, favicon hash and title; each match that scores
+// enough is reported as an "origin-exposed" finding on the fronted host,
+// with the evidence chain in its details. A host counts as fronted when
+// its addresses belong to a CDN provider (from IP enrichment), its CNAME
+// chain ends at a CDN, or its responses carry CDN or WAF headers.
+func FindExposedOrigins(subdomains []types.SubdomainResult, httpResults []types.HTTPResult) []types.Finding {
+	hosts := make(map[string]*originHost, len(subdomains))
+	for _, s := range subdomains {
+		h := &originHost{name: s.Subdomain, ips: s.IPs, infos: s.IPInfo, signals: make(map[string][]string)}
+		for _, info := range s.IPInfo {
+			if name := cdnAddress(info); name != "" {
+				h.setCDN(name, "resolves to "+info.String())
+			}
+		}
+		for _, cname := range s.DNS.Values("CNAME") {
+			for _, c := range cdnCNAMESuffixes {
+				if strings.HasSuffix(strings.ToLower(cname), c.suffix) {
+					h.setCDN(c.name, "CNAME "+cname)
+				}
+			}
+		}
+		hosts[s.Subdomain] = h
+	}
+
+	for _, r := range httpResults {
+		h := hosts[ExtractHostFromURL(r.URL)]
+		if h == nil {
+			continue
+		}
+		if name := responseCDN(r); name != "" {
+			h.setCDN(name, fmt.Sprintf("%s responded through %s", r.URL, name))
+		}
+		if r.TLS != nil {
+			h.addSignal("certificate", r.TLS.Fingerprint)
+		}
+		// Error pages say nothing about the site behind them
+		if r.StatusCode < 200 || r.StatusCode >= 400 {
+			continue
+		}
+		h.addSignal("body", r.BodyHash)
+		h.addSignal("favicon", r.FaviconHash)
+		h.addSignal("title", strings.TrimSpace(r.Title))
+	}
+
+	var fronted, direct []*originHost
+	for _, s := range subdomains {
+		h := hosts[s.Subdomain]
+		switch {
+		case len(h.signals) == 0:
+		case h.cdn != "":
+			fronted = append(fronted, h)
+		case len(h.ips) > 0:
+			direct = append(direct, h)
+		}
+	}
+
+	var findings []types.Finding
+	for _, f := range fronted {
+		for _, d := range direct {
+			if slices.ContainsFunc(d.ips, func(ip string) bool { return slices.Contains(f.ips, ip) }) {
+				continue // the same endpoint, so nothing is bypassed
+			}
+			if finding, ok := compareOrigin(f, d); ok {
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+// compareOrigin matches the direct host d against the fronted host f and
+// returns the finding when they share enough evidence.
+func compareOrigin(f, d *originHost) (types.Finding, bool) {
+	score := 0
+	var kinds, evidence []string
+	for _, w := range originSignalWeights {
+		matched := false
+		for _, value := range f.signals[w.kind] {
+			if !slices.Contains(d.signals[w.kind], value) {
+				continue
+			}
+			matched = true
+			switch w.kind {
+			case "body":
+				evidence = append(evidence, "Same body: SHA-256 "+value)
+			case "favicon":
+				evidence = append(evidence, "Same favicon: hash "+value)
+			case "certificate":
+				evidence = append(evidence, "Same TLS certificate: SHA-256 "+value)
+			case "title":
+				evidence = append(evidence, fmt.Sprintf("Same title: %q", value))
+			}
+		}
+		if matched {
+			score += w.weight
+			kinds = append(kinds, w.kind)
+		}
+	}
+	if score < originReportScore {
+		return types.Finding{}, false
+	}
+
+	severity := types.SeverityMedium
+	if score >= originHighScore {
+		severity = types.SeverityHigh
+	}
+
+	addresses := make([]string, 0, len(d.ips))
+	for _, ip := range d.ips {
+		addr := ip
+		for _, info := range d.infos {
+			if info.IP == ip {
+				addr = info.String()
+			}
+		}
+		addresses = append(addresses, addr)
+	}
+	pin := d.ips[0]
+	if strings.Contains(pin, ":") {
+		pin = "[" + pin + "]"
+	}
+
+	details := []string{
+		fmt.Sprintf("%s is behind %s: %s", f.name, f.cdn, f.cdnReason),
+		fmt.Sprintf("%s resolves to %s with no CDN or WAF in front", d.name, strings.Join(addresses, ", ")),
+	}
+	details = append(details, evidence...)
+	details = append(details, fmt.Sprintf("Confirm with: curl -sk --resolve %s:443:%s https://%s/", f.name, pin, f.name))
+
+	return types.Finding{
+		Type:     "origin-exposed",
+		Severity: severity,
+		Target:   f.name,
+		Title:    fmt.Sprintf("Origin of %s exposed at %s (%s)", f.name, d.name, strings.Join(d.ips, ", ")),
+		Evidence: fmt.Sprintf("%s serves the same site without %s in front (same %s)", d.name, f.cdn, strings.Join(kinds, ", ")),
+		Details:  details,
+	}, true
+}
+
+// cdnAddress returns the CDN operating the tagged address, or "".
+func cdnAddress(info types.IPInfo) string {
+	switch {
+	case cdnProviders[info.Provider]:
+		return info.Provider
+	case info.Provider == "aws" && info.Service == "CLOUDFRONT",
+		info.Provider == "azure" && strings.HasPrefix(info.Service, "AzureFrontDoor"):
+		return info.Provider + "/" + info.Service
+	}
+	return ""
+}
+
+// responseCDN returns the CDN or WAF an HTTP result was served through,
+// from httpx's CDN detection or the detected technologies, or "".
+func responseCDN(r types.HTTPResult) string {
+	if r.CDN != "" {
+		return r.CDN
+	}
+	for _, t := range r.DetectedTech {
+		if t.Category == "CDN" || t.Category == "WAF" {
+			return t.Name
+		}
+	}
+	for _, tech := range r.Technologies {
+		lower := strings.ToLower(tech)
+		for _, name := range cdnTechNames {
+			if strings.Contains(lower, name) {
+				return tech
+			}
+		}
+	}
+	return ""
+}
+
+// fetchFaviconHash returns the FaviconHash of /favicon.ico on the site
+// page belongs to, or "" when it has none.
+func fetchFaviconHash(ctx context.Context, client *http.Client, page *url.URL) string {
+	u := page.ResolveReference(&url.URL{Path: "/favicon.ico"})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("User-Agent", "SubdomainX/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+	return FaviconHash(data)
+}
+
+// FaviconHash returns the hash Shodan and httpx index favicons by: the
+// signed 32-bit MurmurHash3 of the file's base64 encoding, wrapped with a
+// newline every 76 characters.
+func FaviconHash(data []byte) string {
+	enc := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for len(enc) > 76 {
+		b.WriteString(enc[:76])
+		b.WriteByte('\n')
+		enc = enc[76:]
+	}
+	b.WriteString(enc)
+	b.WriteByte('\n')
+	return strconv.Itoa(int(int32(murmur3([]byte(b.String())))))
+}
+
+// murmur3 is the 32-bit x86 MurmurHash3 of data with seed 0.
+func murmur3(data []byte) uint32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	mix := func(k uint32) uint32 {
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		return k * c2
+	}
+
+	var h uint32
+	n := len(data) / 4 * 4
+	for i := 0; i < n; i += 4 {
+		h ^= mix(binary.LittleEndian.Uint32(data[i:]))
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	switch tail := data[n:]; len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		h ^= mix(k)
+	}
+
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+func TestFindExposedOrigins(t *testing.T) {
+	cert := &types.TLSCert{Fingerprint: "aa11"}
+	subdomains := []types.SubdomainResult{
+		{Subdomain: "www.example.com", IPs: []string{"104.16.1.1"}},
+		{Subdomain: "shop.example.com", IPs: []string{"104.16.1.2"}, IPInfo: []types.IPInfo{{IP: "104.16.1.2", ASN: 13335, Provider: "cloudflare"}}},
+		{Subdomain: "origin.example.com", IPs: []string{"203.0.113.5"}, IPInfo: []types.IPInfo{{IP: "203.0.113.5", ASN: 64500, Org: "EXAMPLE-NET", Country: "US"}}},
+		{Subdomain: "dev.example.com", IPs: []string{"203.0.113.6"}},
+		{Subdomain: "cdn-down.example.com", IPs: []string{"203.0.113.7"}},
+	}
+	httpResults := []types.HTTPResult{
+		{URL: "https://www.example.com", StatusCode: 200, Title: "Example", BodyHash: "b1", FaviconHash: "-123", TLS: cert, CDN: "cloudflare"},
+		{URL: "https://shop.example.com", StatusCode: 200, Title: "Shop", FaviconHash: "-123"},
+		{URL: "https://origin.example.com", StatusCode: 200, Title: "Example", BodyHash: "b1", FaviconHash: "-123", TLS: cert},
+		// Same favicon as the shop but nothing else: not enough
+		{URL: "https://dev.example.com", StatusCode: 200, Title: "Dev", FaviconHash: "-123"},
+		// Error pages don't count
+		{URL: "https://cdn-down.example.com", StatusCode: 502, Title: "Example", BodyHash: "b1"},
+	}
+
+	findings := FindExposedOrigins(subdomains, httpResults)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Severity+" "+f.Title)
+	}
+	want := []string{
+		"high Origin of www.example.com exposed at origin.example.com (203.0.113.5)",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("findings = %v, want %v", got, want)
+	}
+
+	f := findings[0]
+	if f.Type != "origin-exposed" || f.Target != "www.example.com" {
+		t.Errorf("Unexpected finding %+v", f)
+	}
+	if f.Evidence != "origin.example.com serves the same site without cloudflare in front (same body, favicon, certificate, title)" {
+		t.Errorf("Unexpected evidence %q", f.Evidence)
+	}
+	wantDetails := []string{
+		"www.example.com is behind cloudflare: https://www.example.com responded through cloudflare",
+		"origin.example.com resolves to 203.0.113.5 (AS64500 EXAMPLE-NET, US) with no CDN or WAF in front",
+		"Same body: SHA-256 b1",
+		"Same favicon: hash -123",
+		"Same TLS certificate: SHA-256 aa11",
+		`Same title: "Example"`,
+		"Confirm with: curl -sk --resolve www.example.com:443:203.0.113.5 https://www.example.com/",
+	}
+	if !slices.Equal(f.Details, wantDetails) {
+		t.Errorf("details = %q, want %q", f.Details, wantDetails)
+	}
+}
+
+func TestFindExposedOriginsCNAME(t *testing.T) {
+	subdomains := []types.SubdomainResult{
+		{Subdomain: "app.example.com", IPs: []string{"13.32.1.1"}, DNS: &types.DNSRecords{CNAME: []string{"d111.cloudfront.net"}}},
+		{Subdomain: "app-origin.example.com", IPs: []string{"2001:db8::1"}},
+	}
+	httpResults := []types.HTTPResult{
+		{URL: "https://app.example.com", StatusCode: 200, Title: "App", BodyHash: "b2"},
+		{URL: "https://app-origin.example.com", StatusCode: 200, Title: "Login", BodyHash: "b2"},
+	}
+
+	findings := FindExposedOrigins(subdomains, httpResults)
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %+v", findings)
+	}
+	if findings[0].Severity != types.SeverityMedium {
+		t.Errorf("Expected medium severity for a body match alone, got %s", findings[0].Severity)
+	}
+	if findings[0].Details[0] != "app.example.com is behind AWS CloudFront: CNAME d111.cloudfront.net" {
+		t.Errorf("Unexpected CDN evidence %q", findings[0].Details[0])
+	}
+	if last := findings[0].Details[len(findings[0].Details)-1]; last != "Confirm with: curl -sk --resolve app.example.com:443:[2001:db8::1] https://app.example.com/" {
+		t.Errorf("Unexpected confirmation command %q", last)
+	}
+}
+
+func TestMurmur3(t *testing.T) {
+	tests := map[string]int32{
+		"":      0,
+		"hello": 613153351,
+		"foo":   -156908512,
+	}
+	for in, want := range tests {
+		if got := int32(murmur3([]byte(in))); got != want {
+			t.Errorf("murmur3(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/plugin"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// PluginScanner runs an out-of-process plugin that declared the scanner
+// capability against URLs.
+type PluginScanner struct {
+	plugin *plugin.Plugin
+}
+
+// PluginPortScanner runs an out-of-process plugin that declared the
+// port-scanner capability against hosts.
+type PluginPortScanner struct {
+	plugin *plugin.Plugin
+}
+
+// RegisterPlugin registers p for each scanning capability it declared.
+func RegisterPlugin(p *plugin.Plugin) {
+	if p.Has(plugin.CapScanner) {
+		RegisterScanner(&PluginScanner{plugin: p})
+	}
+	if p.Has(plugin.CapPortScanner) {
+		RegisterPortScanner(&PluginPortScanner{plugin: p})
+	}
+}
+
+func (s *PluginScanner) Name() string {
+	return s.plugin.Name
+}
+
+func (s *PluginScanner) Scan(ctx context.Context, targets []string, cfg *config.Config) ([]types.HTTPResult, error) {
+	var results []types.HTTPResult
+	req := plugin.Message{Type: plugin.TypeScan, Targets: targets, Options: plugin.OptionsFrom(cfg)}
+	err := s.plugin.Call(ctx, req, func(msg plugin.Message) {
+		if msg.Type == plugin.TypeHTTPResult && msg.HTTPResult != nil {
+			results = append(results, *msg.HTTPResult)
+		}
+	})
+	if err != nil {
+		return results, fmt.Errorf("%s plugin failed: %v", s.plugin.Name, err)
+	}
+	return results, nil
+}
+
+func (s *PluginPortScanner) Name() string {
+	return s.plugin.Name
+}
+
+func (s *PluginPortScanner) Scan(ctx context.Context, targets []string, cfg *config.Config) ([]types.PortResult, error) {
+	var results []types.PortResult
+	req := plugin.Message{Type: plugin.TypePortScan, Targets: targets, Options: plugin.OptionsFrom(cfg)}
+	err := s.plugin.Call(ctx, req, func(msg plugin.Message) {
+		if msg.Type == plugin.TypePortResult && msg.PortResult != nil {
+			results = append(results, *msg.PortResult)
+		}
+	})
+	if err != nil {
+		return results, fmt.Errorf("%s plugin failed: %v", s.plugin.Name, err)
+	}
+	return results, nil
+}
+
+// runExtraScanners runs every enabled HTTP scanner besides httpx, such as
+// plugins, and returns their filtered results. A failing scanner is logged
+// and keeps what it returned.
+func runExtraScanners(ctx context.Context, cfg *config.Config, urls []string, sink tui.EventSink) []types.HTTPResult {
+	var results []types.HTTPResult
+	for _, name := range extraNames(scanners, "httpx", cfg) {
+		found, err := scanners[name].Scan(ctx, urls, cfg)
+		if err != nil {
+			sink.Log("warn", fmt.Sprintf("HTTP scan error: %v", err))
+		}
+		for _, r := range found {
+			if shouldIncludeHTTPResult(r, cfg) {
+				results = append(results, r)
+			}
+		}
+	}
+	return results
+}
+
+// runExtraPortScanners runs every enabled port scanner besides smap.
+func runExtraPortScanners(ctx context.Context, cfg *config.Config, hosts []string, sink tui.EventSink) []types.PortResult {
+	var results []types.PortResult
+	for _, name := range extraNames(portScanners, "smap", cfg) {
+		found, err := portScanners[name].Scan(ctx, hosts, cfg)
+		if err != nil {
+			sink.Log("warn", fmt.Sprintf("Port scan error: %v", err))
+		}
+		for _, r := range found {
+			if shouldIncludePortResult(r, cfg) {
+				results = append(results, r)
+			}
+		}
+	}
+	return results
+}
+
+// extraNames returns, sorted, the registry entries other than builtin that
+// cfg enables.
+func extraNames[T any](registry map[string]T, builtin string, cfg *config.Config) []string {
+	var names []string
+	for name := range registry {
+		if name != builtin && cfg.Tools[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/cache"
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/itszeeshan/subdomainx/v2/internal/utils"
+)
+
+// resolveCheckpointInterval is the number of resolved subdomains between
+// intermediate checkpoint saves.
+const resolveCheckpointInterval = 500
+
+// RunResolution resolves the A and AAAA records of every subdomain through a
+// DNSCache and fills in SubdomainResult.IPs in place. Subdomains that already
+// carry IPs (e.g. from a resumed checkpoint) are skipped. checkpoint, when
+// non-nil, is called periodically and once at the end so progress survives
+// interruptions; it runs while no worker is writing to subdomains. Lookups go
+// through the resolver carried by ctx.
+func RunResolution(scanCtx context.Context, cfg *config.Config, subdomains []types.SubdomainResult, sink tui.EventSink, checkpoint func()) {
+	var pending []int
+	for i := range subdomains {
+		if len(subdomains[i].IPs) == 0 {
+			pending = append(pending, i)
+		}
+	}
+
+	total := len(subdomains)
+	done := total - len(pending)
+	sink.StageProgress("resolution", done, total)
+	if len(pending) == 0 {
+		return
+	}
+
+	dnsCache := cache.NewDNSCacheWithResolver(resolver.FromContext(scanCtx))
+
+	threads := cfg.DNSThreads
+	if threads <= 0 {
+		threads = 50
+	}
+	pool := utils.NewWorkerPool(threads, cfg.DNSRateLimit)
+	defer pool.Stop()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, i := range pending {
+		host := subdomains[i].Subdomain
+		wg.Add(1)
+		pool.Submit(func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(scanCtx, utils.DNSQueryTimeout)
+			defer cancel()
+
+			// Query the rooted name so the local search list is never appended.
+			ips := dnsCache.ResolveContext(ctx, host+".")
+
+			mu.Lock()
+			defer mu.Unlock()
+			subdomains[i].IPs = ips
+			done++
+			sink.StageProgress("resolution", done, total)
+			if checkpoint != nil && done%resolveCheckpointInterval == 0 {
+				checkpoint()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if checkpoint != nil {
+		checkpoint()
+	}
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
+	"github.com/itszeeshan/subdomainx/v2/internal/tui"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+	"github.com/miekg/dns"
+)
+
+// fakeResolver answers LookupHost from a fixed table of rooted names.
+type fakeResolver struct {
+	hosts   map[string][]string
+	lookups atomic.Int32
+}
+
+func (f *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	f.lookups.Add(1)
+	if ips, ok := f.hosts[host]; ok {
+		return ips, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (f *fakeResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	return host, nil
+}
+
+func (f *fakeResolver) Query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	return new(dns.Msg), nil
+}
+
+func TestRunResolution(t *testing.T) {
+	fake := &fakeResolver{hosts: map[string][]string{
+		"www.example.com.": {"192.0.2.2", "192.0.2.1"},
+		"api.example.com.": {"2001:db8::1"},
+	}}
+	ctx := resolver.NewContext(context.Background(), fake)
+
+	subdomains := []types.SubdomainResult{
+		{Subdomain: "www.example.com"},
+		{Subdomain: "api.example.com"},
+		{Subdomain: "gone.example.com"},
+		{Subdomain: "kept.example.com", IPs: []string{"198.51.100.1"}}, // resumed from a checkpoint
+	}
+	checkpoints := 0
+	RunResolution(ctx, &config.Config{DNSThreads: 2}, subdomains, tui.NewCLIEventSink(), func() { checkpoints++ })
+
+	want := map[string][]string{
+		"www.example.com":  {"192.0.2.1", "192.0.2.2"},
+		"api.example.com":  {"2001:db8::1"},
+		"gone.example.com": {},
+		"kept.example.com": {"198.51.100.1"},
+	}
+	for _, s := range subdomains {
+		if !equalStrings(s.IPs, want[s.Subdomain]) {
+			t.Errorf("%s: IPs = %v, want %v", s.Subdomain, s.IPs, want[s.Subdomain])
+		}
+	}
+	if got := fake.lookups.Load(); got != 3 {
+		t.Errorf("Expected 3 lookups (already resolved hosts skipped), got %d", got)
+	}
+	if checkpoints != 1 {
+		t.Errorf("Expected one final checkpoint, got %d", checkpoints)
+	}
+}
+
+func TestRunResolutionNothingPending(t *testing.T) {
+	fake := &fakeResolver{}
+	ctx := resolver.NewContext(context.Background(), fake)
+
+	subdomains := []types.SubdomainResult{{Subdomain: "www.example.com", IPs: []string{"192.0.2.1"}}}
+	called := false
+	RunResolution(ctx, &config.Config{}, subdomains, tui.NewCLIEventSink(), func() { called = true })
+
+	if fake.lookups.Load() != 0 || called {
+		t.Errorf("Expected no lookups or checkpoint when every subdomain is resolved")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
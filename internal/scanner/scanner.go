@@ -2,6 +2,8 @@ package scanner
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
 	"github.com/itszeeshan/subdomainx/v2/internal/tui"
 	"github.com/itszeeshan/subdomainx/v2/internal/types"
 	"github.com/itszeeshan/subdomainx/v2/internal/utils"
@@ -33,6 +36,10 @@ type PortScanner interface {
 var scanners = make(map[string]Scanner)
 var portScanners = make(map[string]PortScanner)
 
+// probeTransport is shared by the built-in HTTP probes so they resolve hosts
+// through the scan's resolver, like every other lookup in the pipeline.
+var probeTransport = resolver.NewHTTPTransport()
+
 // RegisterScanner registers a new scanner
 func RegisterScanner(s Scanner) {
 	scanners[s.Name()] = s
@@ -43,8 +50,9 @@ func RegisterPortScanner(s PortScanner) {
 	portScanners[s.Name()] = s
 }
 
-// RunHTTPx runs HTTP scanning on discovered subdomains
-func RunHTTPx(cfg *config.Config, subdomains []types.SubdomainResult, sink tui.EventSink) ([]types.HTTPResult, error) {
+// RunHTTPx runs HTTP scanning on discovered subdomains. Host lookups go
+// through the resolver carried by ctx.
+func RunHTTPx(ctx context.Context, cfg *config.Config, subdomains []types.SubdomainResult, sink tui.EventSink) ([]types.HTTPResult, error) {
 	if len(subdomains) == 0 {
 		return []types.HTTPResult{}, nil
 	}
@@ -64,19 +72,20 @@ func RunHTTPx(cfg *config.Config, subdomains []types.SubdomainResult, sink tui.E
 
 	// Use httpx scanner if available
 	if httpxScanner, exists := scanners["httpx"]; exists {
-		results, err := httpxScanner.Scan(context.Background(), urls, cfg)
+		results, err := httpxScanner.Scan(ctx, urls, cfg)
 		if err != nil {
 			return nil, err
 		}
+		results = append(results, runExtraScanners(ctx, cfg, urls, sink)...)
 		// When tech detection is enabled, enrich results with fingerprinting
 		if cfg.TechDetect {
-			enrichWithFingerprinting(cfg, results)
+			enrichWithFingerprinting(ctx, cfg, results)
 		}
 		return results, nil
 	}
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout*len(urls))*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Timeout*len(urls))*time.Second)
 	defer cancel()
 
 	// Create worker pool
@@ -130,33 +139,38 @@ func RunHTTPx(cfg *config.Config, subdomains []types.SubdomainResult, sink tui.E
 		sink.Log("warn", fmt.Sprintf("HTTP scan error: %v", err))
 	}
 
+	httpResults = append(httpResults, runExtraScanners(ctx, cfg, urls, sink)...)
 	return httpResults, nil
 }
 
 // RunSmap runs port scanning on discovered subdomains
-func RunSmap(cfg *config.Config, subdomains []types.SubdomainResult, sink tui.EventSink) ([]types.PortResult, error) {
+func RunSmap(ctx context.Context, cfg *config.Config, subdomains []types.SubdomainResult, sink tui.EventSink) ([]types.PortResult, error) {
 	if len(subdomains) == 0 {
 		return []types.PortResult{}, nil
 	}
 
-	// Extract unique hosts
-	hosts := make(map[string]bool)
-	for _, subdomain := range subdomains {
-		hosts[subdomain.Subdomain] = true
-	}
-
+	// Scan each resolved IP once, on behalf of every subdomain sharing it
+	targets := groupHostsByIP(subdomains)
 	var uniqueHosts []string
-	for host := range hosts {
-		uniqueHosts = append(uniqueHosts, host)
+	for _, t := range targets {
+		uniqueHosts = append(uniqueHosts, t.host)
+	}
+	if skipped := len(subdomains) - len(uniqueHosts); skipped > 0 {
+		sink.Log("info", fmt.Sprintf("Port scan deduplicated by IP: %d hosts share an address with another target", skipped))
 	}
 
 	// Use smap scanner if available
 	if smapScanner, exists := portScanners["smap"]; exists {
-		return smapScanner.Scan(context.Background(), uniqueHosts, cfg)
+		results, err := smapScanner.Scan(ctx, uniqueHosts, cfg)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, runExtraPortScanners(ctx, cfg, uniqueHosts, sink)...)
+		return expandPortResults(results, targets), nil
 	}
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout*len(uniqueHosts))*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Timeout*len(uniqueHosts))*time.Second)
 	defer cancel()
 
 	// Create worker pool
@@ -210,7 +224,70 @@ func RunSmap(cfg *config.Config, subdomains []types.SubdomainResult, sink tui.Ev
 		sink.Log("warn", fmt.Sprintf("Port scan error: %v", err))
 	}
 
-	return portResults, nil
+	portResults = append(portResults, runExtraPortScanners(ctx, cfg, uniqueHosts, sink)...)
+	return expandPortResults(portResults, targets), nil
+}
+
+// portTarget is a host scanned on behalf of every subdomain resolving to
+// the same IP.
+type portTarget struct {
+	host    string
+	ip      string
+	aliases []string
+}
+
+// groupHostsByIP groups subdomains by their first resolved IP. Subdomains
+// without IPs are scanned individually by name.
+func groupHostsByIP(subdomains []types.SubdomainResult) []*portTarget {
+	var targets []*portTarget
+	byIP := make(map[string]*portTarget)
+	seen := make(map[string]bool)
+
+	for _, s := range subdomains {
+		if seen[s.Subdomain] {
+			continue
+		}
+		seen[s.Subdomain] = true
+
+		if len(s.IPs) == 0 {
+			targets = append(targets, &portTarget{host: s.Subdomain})
+			continue
+		}
+		if t, ok := byIP[s.IPs[0]]; ok {
+			t.aliases = append(t.aliases, s.Subdomain)
+			continue
+		}
+		t := &portTarget{host: s.Subdomain, ip: s.IPs[0]}
+		byIP[s.IPs[0]] = t
+		targets = append(targets, t)
+	}
+	return targets
+}
+
+// expandPortResults fills in the scanned IP and copies each result to the
+// subdomains that share the target's address.
+func expandPortResults(results []types.PortResult, targets []*portTarget) []types.PortResult {
+	byHost := make(map[string]*portTarget, len(targets))
+	for _, t := range targets {
+		byHost[t.host] = t
+	}
+
+	var expanded []types.PortResult
+	for _, r := range results {
+		t, ok := byHost[r.Host]
+		if !ok {
+			expanded = append(expanded, r)
+			continue
+		}
+		if r.IP == "" {
+			r.IP = t.ip
+		}
+		expanded = append(expanded, r)
+		for _, alias := range t.aliases {
+			expanded = append(expanded, types.PortResult{Host: alias, IP: r.IP, Ports: r.Ports})
+		}
+	}
+	return expanded
 }
 
 // shouldIncludeHTTPResult checks if an HTTP result should be included based on filters
@@ -247,7 +324,8 @@ func shouldIncludePortResult(result types.PortResult, cfg *config.Config) bool {
 // scanHTTP performs HTTP scanning on a single URL
 func scanHTTP(ctx context.Context, url string, cfg *config.Config) (types.HTTPResult, error) {
 	client := &http.Client{
-		Timeout: time.Duration(cfg.Timeout) * time.Second,
+		Timeout:   time.Duration(cfg.Timeout) * time.Second,
+		Transport: probeTransport,
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -277,6 +355,17 @@ func scanHTTP(ctx context.Context, url string, cfg *config.Config) (types.HTTPRe
 		ContentLength: int(resp.ContentLength),
 		Technologies:  technologies,
 	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		result.TLS = types.NewTLSCert(resp.TLS.PeerCertificates[0])
+	}
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		result.BodyHash = hex.EncodeToString(sum[:])
+	}
+	result.CDN = DetectCDN(resp)
+	if cfg.OriginCheck {
+		result.FaviconHash = fetchFaviconHash(ctx, client, resp.Request.URL)
+	}
 
 	// Technology fingerprinting (when enabled via config)
 	if cfg.TechDetect {
@@ -315,7 +404,7 @@ func scanPorts(ctx context.Context, host string, cfg *config.Config) (types.Port
 
 	return types.PortResult{
 		Host:  host,
-		IP:    "", // Would be resolved in real implementation
+		IP:    "", // Filled in from the resolution stage by expandPortResults
 		Ports: openPorts,
 	}, nil
 }
@@ -363,13 +452,14 @@ func isPortOpen(host string, port int) bool {
 // enrichWithFingerprinting runs our fingerprint engine on HTTP results from
 // the external httpx scanner. It makes a follow-up HTTP request per unique host
 // to detect technologies from headers, cookies, and HTML body patterns.
-func enrichWithFingerprinting(cfg *config.Config, results []types.HTTPResult) {
+func enrichWithFingerprinting(scanCtx context.Context, cfg *config.Config, results []types.HTTPResult) {
 	if len(results) == 0 {
 		return
 	}
 
 	client := &http.Client{
-		Timeout: time.Duration(cfg.Timeout) * time.Second,
+		Timeout:   time.Duration(cfg.Timeout) * time.Second,
+		Transport: probeTransport,
 	}
 
 	pool := utils.NewWorkerPool(cfg.Threads, cfg.RateLimit)
@@ -394,7 +484,7 @@ func enrichWithFingerprinting(cfg *config.Config, results []types.HTTPResult) {
 		pool.Submit(func() {
 			defer wg.Done()
 
-			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+			ctx, cancel := context.WithTimeout(scanCtx, time.Duration(cfg.Timeout)*time.Second)
 			defer cancel()
 
 			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+func TestGroupHostsByIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		subdomains []types.SubdomainResult
+		want       []portTarget
+	}{
+		{
+			name: "shared IP",
+			subdomains: []types.SubdomainResult{
+				{Subdomain: "a.example.com", IPs: []string{"192.0.2.1"}},
+				{Subdomain: "b.example.com", IPs: []string{"192.0.2.1"}},
+				{Subdomain: "c.example.com", IPs: []string{"192.0.2.2"}},
+			},
+			want: []portTarget{
+				{host: "a.example.com", ip: "192.0.2.1", aliases: []string{"b.example.com"}},
+				{host: "c.example.com", ip: "192.0.2.2"},
+			},
+		},
+		{
+			name: "no IPs",
+			subdomains: []types.SubdomainResult{
+				{Subdomain: "a.example.com"},
+				{Subdomain: "b.example.com", IPs: []string{}},
+			},
+			want: []portTarget{
+				{host: "a.example.com"},
+				{host: "b.example.com"},
+			},
+		},
+		{
+			name: "keyed on first IP",
+			subdomains: []types.SubdomainResult{
+				{Subdomain: "a.example.com", IPs: []string{"192.0.2.1", "192.0.2.2"}},
+				{Subdomain: "b.example.com", IPs: []string{"192.0.2.2", "192.0.2.1"}},
+				{Subdomain: "c.example.com", IPs: []string{"192.0.2.1"}},
+			},
+			want: []portTarget{
+				{host: "a.example.com", ip: "192.0.2.1", aliases: []string{"c.example.com"}},
+				{host: "b.example.com", ip: "192.0.2.2"},
+			},
+		},
+		{
+			name: "duplicate subdomain",
+			subdomains: []types.SubdomainResult{
+				{Subdomain: "a.example.com", IPs: []string{"192.0.2.1"}},
+				{Subdomain: "a.example.com", IPs: []string{"192.0.2.1"}},
+			},
+			want: []portTarget{
+				{host: "a.example.com", ip: "192.0.2.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := groupHostsByIP(tt.subdomains)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d targets, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i, want := range tt.want {
+				if got[i].host != want.host || got[i].ip != want.ip || !equalStrings(got[i].aliases, want.aliases) {
+					t.Errorf("Target %d = %+v, want %+v", i, *got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandPortResults(t *testing.T) {
+	web := []types.Port{{Number: 80, Protocol: "tcp", State: "open"}, {Number: 443, Protocol: "tcp", State: "open"}}
+	ssh := []types.Port{{Number: 22, Protocol: "tcp", State: "open"}}
+	alt := []types.Port{{Number: 8080, Protocol: "tcp", State: "open"}}
+	targets := []*portTarget{
+		{host: "a.example.com", ip: "192.0.2.1", aliases: []string{"b.example.com", "c.example.com"}},
+		{host: "d.example.com"},
+	}
+	results := []types.PortResult{
+		{Host: "a.example.com", Ports: web},
+		{Host: "d.example.com", IP: "192.0.2.4", Ports: ssh},
+		{Host: "other.example.com", Ports: alt},
+	}
+
+	expanded := expandPortResults(results, targets)
+
+	want := []types.PortResult{
+		{Host: "a.example.com", IP: "192.0.2.1", Ports: web},
+		{Host: "b.example.com", IP: "192.0.2.1", Ports: web},
+		{Host: "c.example.com", IP: "192.0.2.1", Ports: web},
+		{Host: "d.example.com", IP: "192.0.2.4", Ports: ssh},
+		{Host: "other.example.com", Ports: alt},
+	}
+	if len(expanded) != len(want) {
+		t.Fatalf("Expected %d results, got %d: %+v", len(want), len(expanded), expanded)
+	}
+	for i, w := range want {
+		got := expanded[i]
+		if got.Host != w.Host || got.IP != w.IP || len(got.Ports) != len(w.Ports) {
+			t.Errorf("Result %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
 	"github.com/itszeeshan/subdomainx/v2/internal/tui"
 	"github.com/itszeeshan/subdomainx/v2/internal/types"
 	"github.com/itszeeshan/subdomainx/v2/internal/utils"
@@ -101,7 +102,7 @@ var serviceFingerprints = []serviceFingerprint{
 
 // RunTakeoverCheck checks subdomains for potential takeover vulnerabilities
 // by examining CNAME records and optionally HTTP responses.
-func RunTakeoverCheck(cfg *config.Config, subdomains []types.SubdomainResult, httpResults []types.HTTPResult, sink tui.EventSink) ([]types.TakeoverResult, error) {
+func RunTakeoverCheck(ctx context.Context, cfg *config.Config, subdomains []types.SubdomainResult, httpResults []types.HTTPResult, sink tui.EventSink) ([]types.TakeoverResult, error) {
 	if len(subdomains) == 0 {
 		return nil, nil
 	}
@@ -109,7 +110,7 @@ func RunTakeoverCheck(cfg *config.Config, subdomains []types.SubdomainResult, ht
 	// Build HTTP body lookup map from existing HTTP results
 	httpBodyMap := make(map[string]string) // subdomain -> response body
 	if len(httpResults) > 0 {
-		httpBodyMap = fetchBodiesForTakeover(cfg, httpResults)
+		httpBodyMap = fetchBodiesForTakeover(ctx, cfg, httpResults)
 	}
 
 	pool := utils.NewWorkerPool(cfg.Threads, cfg.RateLimit)
@@ -123,7 +124,7 @@ func RunTakeoverCheck(cfg *config.Config, subdomains []types.SubdomainResult, ht
 		wg.Add(1)
 		pool.Submit(func() {
 			defer wg.Done()
-			if result, ok := checkTakeover(sub.Subdomain, httpBodyMap); ok {
+			if result, ok := checkTakeover(ctx, sub, httpBodyMap); ok {
 				results <- result
 			}
 		})
@@ -142,14 +143,27 @@ func RunTakeoverCheck(cfg *config.Config, subdomains []types.SubdomainResult, ht
 	return takeoverResults, nil
 }
 
-// checkTakeover checks a single subdomain for takeover vulnerability.
-func checkTakeover(subdomain string, httpBodyMap map[string]string) (types.TakeoverResult, bool) {
+// checkTakeover checks a single subdomain for takeover vulnerability. The
+// CNAME chain stored by the DNS enrichment stage is used when present.
+func checkTakeover(scanCtx context.Context, sub types.SubdomainResult, httpBodyMap map[string]string) (types.TakeoverResult, bool) {
+	ctx, cancel := context.WithTimeout(scanCtx, utils.DNSQueryTimeout)
+	defer cancel()
+	subdomain := sub.Subdomain
+
 	// Look up CNAME
-	cname, err := net.LookupCNAME(subdomain)
-	if err != nil || cname == "" || cname == subdomain+"." {
-		return types.TakeoverResult{}, false
+	var cname string
+	if sub.DNS != nil {
+		if len(sub.DNS.CNAME) == 0 {
+			return types.TakeoverResult{}, false
+		}
+		cname = sub.DNS.CNAME[len(sub.DNS.CNAME)-1]
+	} else {
+		target, err := resolver.FromContext(ctx).LookupCNAME(ctx, subdomain)
+		if err != nil || target == "" || target == subdomain+"." {
+			return types.TakeoverResult{}, false
+		}
+		cname = strings.TrimSuffix(target, ".")
 	}
-	cname = strings.TrimSuffix(cname, ".")
 
 	// Check if CNAME matches a known vulnerable service
 	for _, fp := range serviceFingerprints {
@@ -158,7 +172,7 @@ func checkTakeover(subdomain string, httpBodyMap map[string]string) (types.Takeo
 		}
 
 		// Check if CNAME target is dangling (NXDOMAIN)
-		isDangling := isCnameDangling(cname)
+		isDangling := isCnameDangling(ctx, cname)
 
 		// Check HTTP body if available
 		bodyMatch := false
@@ -195,8 +209,8 @@ func MatchesCnamePattern(cname string, patterns []string) bool {
 }
 
 // isCnameDangling checks if a CNAME target fails to resolve (NXDOMAIN).
-func isCnameDangling(cname string) bool {
-	_, err := net.LookupHost(cname)
+func isCnameDangling(ctx context.Context, cname string) bool {
+	_, err := resolver.FromContext(ctx).LookupHost(ctx, cname)
 	if err != nil {
 		if dnsErr, ok := err.(*net.DNSError); ok {
 			return dnsErr.IsNotFound
@@ -221,10 +235,11 @@ func buildEvidence(isDangling, bodyMatch bool, cname string, fp serviceFingerpri
 
 // fetchBodiesForTakeover fetches HTTP response bodies for the subdomains
 // that have HTTP results, to check for takeover fingerprints in the body.
-func fetchBodiesForTakeover(cfg *config.Config, httpResults []types.HTTPResult) map[string]string {
+func fetchBodiesForTakeover(scanCtx context.Context, cfg *config.Config, httpResults []types.HTTPResult) map[string]string {
 	bodies := make(map[string]string)
 	client := &http.Client{
-		Timeout: time.Duration(cfg.Timeout) * time.Second,
+		Timeout:   time.Duration(cfg.Timeout) * time.Second,
+		Transport: probeTransport,
 	}
 
 	for _, hr := range httpResults {
@@ -233,7 +248,7 @@ func fetchBodiesForTakeover(cfg *config.Config, httpResults []types.HTTPResult)
 			continue
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+		ctx, cancel := context.WithTimeout(scanCtx, time.Duration(cfg.Timeout)*time.Second)
 		req, err := http.NewRequestWithContext(ctx, "GET", hr.URL, nil)
 		if err != nil {
 			cancel()
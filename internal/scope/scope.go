@@ -0,0 +1,297 @@
+// Package scope decides which hosts and addresses belong to the program
+// scope. Host rules are globs ("*.corp.example.com") or regular expressions
+// prefixed with "re:"; address rules are CIDRs or single IPs.
+package scope
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// Scope holds the compiled scope rules for a scan.
+type Scope struct {
+	apexes  map[string]bool
+	hosts   map[string]bool // explicit targets, matched exactly
+	include []rule
+	exclude []rule
+	allow   []*net.IPNet
+	deny    []*net.IPNet
+}
+
+type rule struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// New compiles the scope rules in cfg. Hosts must sit under one of
+// cfg.ScopeApexes or, when that list is empty, under one of apexes or be
+// one of hosts exactly. Addresses in hosts are left to the address rules.
+func New(cfg *config.Config, apexes, hosts []string) (*Scope, error) {
+	s := &Scope{apexes: make(map[string]bool), hosts: make(map[string]bool)}
+
+	list := cfg.ScopeApexes
+	if len(list) == 0 {
+		list = apexes
+		for _, h := range hosts {
+			if h = normalizeHost(h); h != "" && net.ParseIP(h) == nil {
+				s.hosts[h] = true
+			}
+		}
+	}
+	for _, a := range list {
+		if a = normalizeHost(a); a != "" {
+			s.apexes[a] = true
+		}
+	}
+
+	var err error
+	if s.include, err = compileRules(cfg.ScopeInclude); err != nil {
+		return nil, err
+	}
+	if s.exclude, err = compileRules(cfg.ScopeExclude); err != nil {
+		return nil, err
+	}
+	if s.allow, err = parseCIDRs(cfg.ScopeAllowCIDRs); err != nil {
+		return nil, err
+	}
+	if s.deny, err = parseCIDRs(cfg.ScopeDenyCIDRs); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Host reports whether host is in scope. When it is not, reason names the
+// rule that excluded it. Literal IPs are checked against the address rules.
+func (s *Scope) Host(host string) (ok bool, reason string) {
+	host = normalizeHost(host)
+	if net.ParseIP(host) != nil {
+		return s.IP(host)
+	}
+
+	if (len(s.apexes) > 0 || len(s.hosts) > 0) && !s.hosts[host] && !s.underApex(host) {
+		return false, "not under any scope apex"
+	}
+
+	for _, r := range s.exclude {
+		if r.re.MatchString(host) {
+			return false, fmt.Sprintf("exclude rule %q", r.pattern)
+		}
+	}
+
+	if len(s.include) > 0 {
+		for _, r := range s.include {
+			if r.re.MatchString(host) {
+				return true, ""
+			}
+		}
+		return false, "no include rule matched"
+	}
+	return true, ""
+}
+
+// underApex reports whether host is a scope apex or sits below one, walking
+// its parent names instead of scanning every apex.
+func (s *Scope) underApex(host string) bool {
+	for name := host; name != ""; {
+		if s.apexes[name] {
+			return true
+		}
+		_, name, _ = strings.Cut(name, ".")
+	}
+	return false
+}
+
+// IP reports whether a single address is in scope.
+func (s *Scope) IP(addr string) (ok bool, reason string) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return true, ""
+	}
+	if n := containing(s.deny, ip); n != nil {
+		return false, fmt.Sprintf("deny CIDR %s", n)
+	}
+	if len(s.allow) > 0 && containing(s.allow, ip) == nil {
+		return false, "outside allow CIDRs"
+	}
+	return true, ""
+}
+
+// Subdomain checks a result's host and, once resolved, its addresses. A host
+// is dropped if any address is denied, or if allow CIDRs are set and none of
+// its addresses falls inside them. Unresolved hosts pass the address rules.
+func (s *Scope) Subdomain(r types.SubdomainResult) (ok bool, reason string) {
+	if ok, reason := s.Host(r.Subdomain); !ok {
+		return false, reason
+	}
+	if len(r.IPs) == 0 {
+		return true, ""
+	}
+
+	allowed := len(s.allow) == 0
+	for _, addr := range r.IPs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		if n := containing(s.deny, ip); n != nil {
+			return false, fmt.Sprintf("deny CIDR %s (%s)", n, addr)
+		}
+		if !allowed && containing(s.allow, ip) != nil {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return false, "no address inside allow CIDRs"
+	}
+	return true, ""
+}
+
+// FilterSubdomains returns the in-scope results and calls drop for every
+// excluded one.
+func (s *Scope) FilterSubdomains(results []types.SubdomainResult, drop func(asset, reason string)) []types.SubdomainResult {
+	var kept []types.SubdomainResult
+	for _, r := range results {
+		if ok, reason := s.Subdomain(r); ok {
+			kept = append(kept, r)
+		} else {
+			drop(r.Subdomain, reason)
+		}
+	}
+	return kept
+}
+
+// FilterHTTP drops HTTP results whose URL host is out of scope, e.g. after a
+// redirect to a third-party host.
+func (s *Scope) FilterHTTP(results []types.HTTPResult, drop func(asset, reason string)) []types.HTTPResult {
+	var kept []types.HTTPResult
+	for _, r := range results {
+		host := r.URL
+		if u, err := url.Parse(r.URL); err == nil && u.Hostname() != "" {
+			host = u.Hostname()
+		}
+		if ok, reason := s.Host(host); ok {
+			kept = append(kept, r)
+		} else {
+			drop(r.URL, reason)
+		}
+	}
+	return kept
+}
+
+// FilterPorts drops port results whose host or address is out of scope.
+func (s *Scope) FilterPorts(results []types.PortResult, drop func(asset, reason string)) []types.PortResult {
+	var kept []types.PortResult
+	for _, r := range results {
+		ok, reason := s.Host(r.Host)
+		if ok && r.IP != "" {
+			ok, reason = s.IP(r.IP)
+		}
+		if ok {
+			kept = append(kept, r)
+		} else {
+			drop(r.Host, reason)
+		}
+	}
+	return kept
+}
+
+// FilterWayback drops Wayback entries for out-of-scope subdomains.
+func (s *Scope) FilterWayback(results []types.WaybackEntry, drop func(asset, reason string)) []types.WaybackEntry {
+	var kept []types.WaybackEntry
+	for _, r := range results {
+		if ok, reason := s.Host(r.Subdomain); ok {
+			kept = append(kept, r)
+		} else {
+			drop(r.Subdomain, reason)
+		}
+	}
+	return kept
+}
+
+// FilterTakeover drops takeover results for out-of-scope subdomains.
+func (s *Scope) FilterTakeover(results []types.TakeoverResult, drop func(asset, reason string)) []types.TakeoverResult {
+	var kept []types.TakeoverResult
+	for _, r := range results {
+		if ok, reason := s.Host(r.Subdomain); ok {
+			kept = append(kept, r)
+		} else {
+			drop(r.Subdomain, reason)
+		}
+	}
+	return kept
+}
+
+// compileRules turns glob and "re:" patterns into anchored, case-insensitive
+// regular expressions. In globs '*' matches any run of characters, dots
+// included, so "*.corp.example.com" covers every name below that zone.
+func compileRules(patterns []string) ([]rule, error) {
+	var rules []rule
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		var expr string
+		if re, ok := strings.CutPrefix(p, "re:"); ok {
+			expr = "(?i)" + re
+		} else {
+			quoted := regexp.QuoteMeta(normalizeHost(p))
+			quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+			quoted = strings.ReplaceAll(quoted, `\?`, ".")
+			expr = "(?i)^" + quoted + "$"
+		}
+
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope rule %q: %v", p, err)
+		}
+		rules = append(rules, rule{pattern: p, re: re})
+	}
+	return rules, nil
+}
+
+// parseCIDRs parses CIDRs, accepting bare IPs as single-address networks.
+func parseCIDRs(list []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, c := range list {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if ip := net.ParseIP(c); ip != nil {
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope CIDR %q: %v", c, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// containing returns the first network in nets that contains ip.
+func containing(nets []*net.IPNet, ip net.IP) *net.IPNet {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return n
+		}
+	}
+	return nil
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
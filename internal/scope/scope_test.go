@@ -0,0 +1,154 @@
+package scope
+
+import (
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+func TestHostRules(t *testing.T) {
+	cfg := &config.Config{
+		ScopeExclude: []string{"*.corp.example.com", "re:^cdn[0-9]+\\."},
+	}
+	s, err := New(cfg, []string{"example.com"}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"api.example.com", true},
+		{"API.Example.com.", true},
+		{"vpn.corp.example.com", false},
+		{"a.b.corp.example.com", false},
+		{"corp.example.com", true},
+		{"cdn01.example.com", false},
+		{"example.org", false},
+		{"notexample.com", false},
+	}
+	for _, tt := range tests {
+		got, reason := s.Host(tt.host)
+		if got != tt.want {
+			t.Errorf("Host(%q) = %v (%s), want %v", tt.host, got, reason, tt.want)
+		}
+		if !got && reason == "" {
+			t.Errorf("Host(%q) excluded without a reason", tt.host)
+		}
+	}
+}
+
+func TestIncludeRules(t *testing.T) {
+	cfg := &config.Config{
+		ScopeApexes:  []string{"example.com"},
+		ScopeInclude: []string{"*.app.example.com", "www.example.com"},
+	}
+	s, err := New(cfg, []string{"ignored.org"}, []string{"host.ignored.org"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if ok, _ := s.Host("x.app.example.com"); !ok {
+		t.Error("Expected x.app.example.com to match an include rule")
+	}
+	if ok, _ := s.Host("www.example.com"); !ok {
+		t.Error("Expected www.example.com to match an include rule")
+	}
+	if ok, reason := s.Host("mail.example.com"); ok || reason != "no include rule matched" {
+		t.Errorf("Expected mail.example.com to be excluded, got %v (%s)", ok, reason)
+	}
+	if ok, _ := s.Host("www.ignored.org"); ok {
+		t.Error("ScopeApexes should replace the target domains")
+	}
+	if ok, _ := s.Host("host.ignored.org"); ok {
+		t.Error("ScopeApexes should replace the target hosts")
+	}
+}
+
+func TestExplicitHosts(t *testing.T) {
+	cfg := &config.Config{ScopeExclude: []string{"admin.*"}}
+	s, err := New(cfg, []string{"example.com"}, []string{"App.Other.net.", "admin.other.net", "192.0.2.5"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"www.example.com", true},
+		{"app.other.net", true},
+		{"x.app.other.net", false}, // explicit hosts are not apexes
+		{"other.net", false},
+		{"admin.other.net", false}, // exclude rules still apply
+		{"192.0.2.5", true},
+	}
+	for _, tt := range tests {
+		if got, reason := s.Host(tt.host); got != tt.want {
+			t.Errorf("Host(%q) = %v (%s), want %v", tt.host, got, reason, tt.want)
+		}
+	}
+}
+
+func TestAddressRules(t *testing.T) {
+	cfg := &config.Config{
+		ScopeAllowCIDRs: []string{"203.0.113.0/24", "2001:db8::/32"},
+		ScopeDenyCIDRs:  []string{"203.0.113.66"},
+	}
+	s, err := New(cfg, []string{"example.com"}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		ips  []string
+		want bool
+	}{
+		{nil, true},
+		{[]string{"203.0.113.10"}, true},
+		{[]string{"198.51.100.1", "203.0.113.10"}, true},
+		{[]string{"198.51.100.1"}, false},
+		{[]string{"203.0.113.10", "203.0.113.66"}, false},
+		{[]string{"2001:db8::1"}, true},
+	}
+	for _, tt := range tests {
+		r := types.SubdomainResult{Subdomain: "a.example.com", IPs: tt.ips}
+		if got, reason := s.Subdomain(r); got != tt.want {
+			t.Errorf("Subdomain(%v) = %v (%s), want %v", tt.ips, got, reason, tt.want)
+		}
+	}
+}
+
+func TestFilterHTTP(t *testing.T) {
+	cfg := &config.Config{ScopeDenyCIDRs: []string{"10.0.0.0/8"}}
+	s, err := New(cfg, []string{"example.com"}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var dropped []string
+	kept := s.FilterHTTP([]types.HTTPResult{
+		{URL: "https://api.example.com/login"},
+		{URL: "https://thirdparty.net/"},
+		{URL: "http://10.1.2.3:8080/"},
+	}, func(asset, reason string) { dropped = append(dropped, asset) })
+
+	if len(kept) != 1 || kept[0].URL != "https://api.example.com/login" {
+		t.Errorf("Unexpected kept results: %v", kept)
+	}
+	if len(dropped) != 2 {
+		t.Errorf("Expected 2 dropped results, got %v", dropped)
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	if _, err := New(&config.Config{ScopeInclude: []string{"re:("}}, nil, nil); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+	if _, err := New(&config.Config{ScopeDenyCIDRs: []string{"10.0.0.0/33"}}, nil, nil); err == nil {
+		t.Error("Expected error for invalid CIDR")
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"slices"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/types"
@@ -9,7 +10,8 @@ import (
 // APIEventSink implements tui.EventSink and routes pipeline progress
 // updates into a ScanJob's fields for REST API polling.
 type APIEventSink struct {
-	job *ScanJob
+	job   *ScanJob
+	index map[string]int // subdomain → position in job.Results.Subdomains; guarded by job.mu
 }
 
 // NewAPIEventSink creates an EventSink that writes into the given ScanJob.
@@ -22,6 +24,8 @@ func (s *APIEventSink) StageStarted(stage, message string) {
 	defer s.job.mu.Unlock()
 	s.job.Progress.Stage = stage
 	s.job.Progress.StageMessage = message
+	s.job.Progress.StageDone = 0
+	s.job.Progress.StageTotal = 0
 	s.job.Status = StatusRunning
 }
 
@@ -32,25 +36,62 @@ func (s *APIEventSink) StageCompleted(stage, message string) {
 	s.job.Progress.StageMessage = message
 }
 
+func (s *APIEventSink) StageProgress(stage string, done, total int) {
+	s.job.mu.Lock()
+	defer s.job.mu.Unlock()
+	if s.job.Progress.Stage == stage {
+		s.job.Progress.StageDone = done
+		s.job.Progress.StageTotal = total
+	}
+}
+
 func (s *APIEventSink) ToolProgress(tool, domain, status string, found int, err error) {
 	s.job.mu.Lock()
 	defer s.job.mu.Unlock()
 	switch status {
-	case "completed":
+	case "completed", "partial":
 		s.job.Progress.ToolsCompleted++
-	case "failed":
+	case "failed", "circuit-open":
 		s.job.Progress.ToolsFailed++
 	}
 }
 
+// SubdomainDiscovered appends live discoveries so GET /api/scan/{id} shows
+// them while the enumerators are still running.
+func (s *APIEventSink) SubdomainDiscovered(subdomain, source string, totalUnique int) {
+	s.job.mu.Lock()
+	defer s.job.mu.Unlock()
+	s.job.Progress.SubdomainsFound = totalUnique
+	if s.job.Results == nil {
+		s.job.Results = &ScanResults{}
+	}
+	subs := s.job.Results.Subdomains
+	if s.index == nil {
+		s.index = make(map[string]int, len(subs))
+		for i, r := range subs {
+			s.index[r.Subdomain] = i
+		}
+	}
+
+	if i, ok := s.index[subdomain]; ok {
+		subs[i].AddSource(source, time.Now())
+		return
+	}
+	r := types.SubdomainResult{Subdomain: subdomain, IPs: []string{}}
+	r.AddSource(source, time.Now())
+	s.index[subdomain] = len(subs)
+	s.job.Results.Subdomains = append(subs, r)
+}
+
 func (s *APIEventSink) SubdomainsFound(results []types.SubdomainResult, totalUnique int) {
 	s.job.mu.Lock()
 	defer s.job.mu.Unlock()
+	s.index = nil
 	s.job.Progress.SubdomainsFound = totalUnique
 	if s.job.Results == nil {
 		s.job.Results = &ScanResults{}
 	}
-	s.job.Results.Subdomains = results
+	s.job.Results.Subdomains = slices.Clone(results)
 }
 
 func (s *APIEventSink) HTTPResults(results []types.HTTPResult, total int) {
@@ -60,7 +101,7 @@ func (s *APIEventSink) HTTPResults(results []types.HTTPResult, total int) {
 	if s.job.Results == nil {
 		s.job.Results = &ScanResults{}
 	}
-	s.job.Results.HTTP = results
+	s.job.Results.HTTP = slices.Clone(results)
 }
 
 func (s *APIEventSink) PortResults(results []types.PortResult, total int) {
@@ -70,7 +111,7 @@ func (s *APIEventSink) PortResults(results []types.PortResult, total int) {
 	if s.job.Results == nil {
 		s.job.Results = &ScanResults{}
 	}
-	s.job.Results.Ports = results
+	s.job.Results.Ports = slices.Clone(results)
 }
 
 func (s *APIEventSink) TakeoverResults(results []types.TakeoverResult) {
@@ -79,7 +120,16 @@ func (s *APIEventSink) TakeoverResults(results []types.TakeoverResult) {
 	if s.job.Results == nil {
 		s.job.Results = &ScanResults{}
 	}
-	s.job.Results.Takeover = results
+	s.job.Results.Takeover = slices.Clone(results)
+}
+
+func (s *APIEventSink) Findings(findings []types.Finding) {
+	s.job.mu.Lock()
+	defer s.job.mu.Unlock()
+	if s.job.Results == nil {
+		s.job.Results = &ScanResults{}
+	}
+	s.job.Results.Findings = slices.Clone(findings)
 }
 
 func (s *APIEventSink) Log(level, message string) {
@@ -45,6 +45,8 @@ func (s *Server) handleCreateScan(w http.ResponseWriter, r *http.Request) {
 		Timeout:        withDefault(req.Timeout, 30),
 		RateLimit:      withDefault(req.RateLimit, 100),
 		MaxHTTPTargets: 1000,
+		DNSThreads:     50,
+		DNSRateLimit:   500,
 		Tools:          make(map[string]bool),
 		Filters:        make(map[string]string),
 		Screenshot:     req.Options.Screenshot,
@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
 )
 
 func TestHealthEndpoint(t *testing.T) {
@@ -160,3 +162,38 @@ func TestListScans_Empty(t *testing.T) {
 		t.Fatalf("expected 0 items, got %d", len(items))
 	}
 }
+
+func TestAPIEventSink_SubdomainDiscovered(t *testing.T) {
+	job := &ScanJob{ID: "live", Status: StatusRunning}
+	sink := NewAPIEventSink(job)
+
+	sink.SubdomainDiscovered("a.example.com", "subfinder", 1)
+	sink.SubdomainDiscovered("b.example.com", "subfinder", 2)
+	sink.SubdomainDiscovered("a.example.com", "amass", 2)
+	sink.SubdomainDiscovered("a.example.com", "amass", 2)
+
+	if job.Progress.SubdomainsFound != 2 {
+		t.Fatalf("expected 2 subdomains found, got %d", job.Progress.SubdomainsFound)
+	}
+	if job.Results == nil || len(job.Results.Subdomains) != 2 {
+		t.Fatalf("expected 2 live results, got %+v", job.Results)
+	}
+	if got := job.Results.Subdomains[0].Source; got != "subfinder,amass" {
+		t.Fatalf("expected merged sources, got %s", got)
+	}
+}
+
+func TestAPIEventSink_SubdomainsFoundCopies(t *testing.T) {
+	job := &ScanJob{ID: "copy", Status: StatusRunning}
+	sink := NewAPIEventSink(job)
+
+	results := []types.SubdomainResult{{Subdomain: "a.example.com"}}
+	sink.SubdomainsFound(results, 1)
+
+	// The pipeline keeps resolving into its own slice after reporting it
+	results[0].IPs = []string{"192.0.2.1"}
+
+	if got := job.Results.Subdomains[0].IPs; len(got) != 0 {
+		t.Fatalf("expected the job to keep its own copy, got IPs %v", got)
+	}
+}
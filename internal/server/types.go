@@ -38,6 +38,8 @@ type ScanJob struct {
 type ScanProgress struct {
 	Stage           string `json:"stage"`
 	StageMessage    string `json:"stage_message"`
+	StageDone       int    `json:"stage_done,omitempty"`
+	StageTotal      int    `json:"stage_total,omitempty"`
 	SubdomainsFound int    `json:"subdomains_found"`
 	HTTPResults     int    `json:"http_results"`
 	PortResults     int    `json:"port_results"`
@@ -51,6 +53,7 @@ type ScanResults struct {
 	HTTP       []types.HTTPResult      `json:"http,omitempty"`
 	Ports      []types.PortResult      `json:"ports,omitempty"`
 	Takeover   []types.TakeoverResult  `json:"takeover,omitempty"`
+	Findings   []types.Finding         `json:"findings,omitempty"`
 }
 
 // ScanRequest is the JSON body for POST /api/scan.
@@ -8,11 +8,18 @@ import (
 
 // StageMsg signals a pipeline stage transition.
 type StageMsg struct {
-	Stage   string // "enumeration", "http", "screenshot", "wayback", "ports", "takeover", "output"
+	Stage   string // "enumeration", "resolution", "dns", "ipinfo", "email", "origin", "http", "screenshot", "crawl", "wayback", "ports", "takeover", "output"
 	Status  string // "started", "completed", "failed"
 	Message string
 }
 
+// StageProgressMsg reports item-level progress within a stage.
+type StageProgressMsg struct {
+	Stage string
+	Done  int
+	Total int
+}
+
 // ToolProgressMsg reports per-tool enumeration progress.
 type ToolProgressMsg struct {
 	Tool   string
@@ -22,6 +29,14 @@ type ToolProgressMsg struct {
 	Error  string
 }
 
+// SubdomainMsg delivers a single subdomain as soon as an enumerator reports
+// it, before wildcard filtering.
+type SubdomainMsg struct {
+	Subdomain string
+	Source    string
+	Total     int
+}
+
 // ResultMsg delivers discovered subdomains.
 type ResultMsg struct {
 	Results []types.SubdomainResult
@@ -45,6 +60,11 @@ type TakeoverResultMsg struct {
 	Results []types.TakeoverResult
 }
 
+// FindingsMsg delivers the misconfigurations found so far.
+type FindingsMsg struct {
+	Findings []types.Finding
+}
+
 // LogMsg is a log line to display in the Logs tab.
 type LogMsg struct {
 	Level   string // "info", "warn", "error"
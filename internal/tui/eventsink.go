@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,15 +11,20 @@ import (
 )
 
 // EventSink is the interface the pipeline uses to report progress.
-// It decouples the scan pipeline from the presentation layer.
+// It decouples the scan pipeline from the presentation layer. Result slices
+// stay owned by the pipeline, which keeps updating them after the call;
+// implementations that hold on to one must keep their own copy.
 type EventSink interface {
 	StageStarted(stage, message string)
 	StageCompleted(stage, message string)
+	StageProgress(stage string, done, total int)
 	ToolProgress(tool, domain, status string, found int, err error)
+	SubdomainDiscovered(subdomain, source string, totalUnique int)
 	SubdomainsFound(results []types.SubdomainResult, totalUnique int)
 	HTTPResults(results []types.HTTPResult, total int)
 	PortResults(results []types.PortResult, total int)
 	TakeoverResults(results []types.TakeoverResult)
+	Findings(findings []types.Finding)
 	Log(level, message string)
 	ScanComplete(err error)
 }
@@ -43,6 +49,10 @@ func (s *TUIEventSink) StageCompleted(stage, message string) {
 	s.program.Send(StageMsg{Stage: stage, Status: "completed", Message: message})
 }
 
+func (s *TUIEventSink) StageProgress(stage string, done, total int) {
+	s.program.Send(StageProgressMsg{Stage: stage, Done: done, Total: total})
+}
+
 func (s *TUIEventSink) ToolProgress(tool, domain, status string, found int, err error) {
 	errStr := ""
 	if err != nil {
@@ -51,20 +61,28 @@ func (s *TUIEventSink) ToolProgress(tool, domain, status string, found int, err
 	s.program.Send(ToolProgressMsg{Tool: tool, Domain: domain, Status: status, Found: found, Error: errStr})
 }
 
+func (s *TUIEventSink) SubdomainDiscovered(subdomain, source string, totalUnique int) {
+	s.program.Send(SubdomainMsg{Subdomain: subdomain, Source: source, Total: totalUnique})
+}
+
 func (s *TUIEventSink) SubdomainsFound(results []types.SubdomainResult, totalUnique int) {
-	s.program.Send(ResultMsg{Results: results, Total: totalUnique})
+	s.program.Send(ResultMsg{Results: slices.Clone(results), Total: totalUnique})
 }
 
 func (s *TUIEventSink) HTTPResults(results []types.HTTPResult, total int) {
-	s.program.Send(HTTPResultMsg{Results: results, Total: total})
+	s.program.Send(HTTPResultMsg{Results: slices.Clone(results), Total: total})
 }
 
 func (s *TUIEventSink) PortResults(results []types.PortResult, total int) {
-	s.program.Send(PortResultMsg{Results: results, Total: total})
+	s.program.Send(PortResultMsg{Results: slices.Clone(results), Total: total})
 }
 
 func (s *TUIEventSink) TakeoverResults(results []types.TakeoverResult) {
-	s.program.Send(TakeoverResultMsg{Results: results})
+	s.program.Send(TakeoverResultMsg{Results: slices.Clone(results)})
+}
+
+func (s *TUIEventSink) Findings(findings []types.Finding) {
+	s.program.Send(FindingsMsg{Findings: slices.Clone(findings)})
 }
 
 func (s *TUIEventSink) Log(level, message string) {
@@ -93,6 +111,15 @@ func (s *CLIEventSink) StageCompleted(stage, message string) {
 	log.Println(message)
 }
 
+// cliProgressInterval controls how often CLIEventSink prints stage progress.
+const cliProgressInterval = 500
+
+func (s *CLIEventSink) StageProgress(stage string, done, total int) {
+	if done%cliProgressInterval == 0 || done == total {
+		log.Printf("%s: %d/%d", stage, done, total)
+	}
+}
+
 func (s *CLIEventSink) ToolProgress(tool, domain, status string, found int, err error) {
 	switch status {
 	case "failed":
@@ -101,9 +128,17 @@ func (s *CLIEventSink) ToolProgress(tool, domain, status string, found int, err
 		fmt.Printf("%s found %d subdomains for %s\n", tool, found, domain)
 	case "skipped":
 		fmt.Printf("Skipping %s: tool not found in PATH\n", tool)
+	case "circuit-open":
+		fmt.Printf("Skipping %s on %s: %v\n", tool, domain, err)
+	case "partial":
+		fmt.Printf("%s ran out of time for %s; kept %d subdomains found so far\n", tool, domain, found)
 	}
 }
 
+func (s *CLIEventSink) SubdomainDiscovered(subdomain, source string, totalUnique int) {
+	// The CLI reports per-tool totals instead of every name as it appears
+}
+
 func (s *CLIEventSink) SubdomainsFound(results []types.SubdomainResult, totalUnique int) {
 	fmt.Printf("Total unique subdomains found: %d\n", totalUnique)
 }
@@ -120,6 +155,10 @@ func (s *CLIEventSink) TakeoverResults(results []types.TakeoverResult) {
 	log.Printf("Takeover check completed: %d potential vulnerabilities", len(results))
 }
 
+func (s *CLIEventSink) Findings(findings []types.Finding) {
+	log.Printf("Findings to report: %d", len(findings))
+}
+
 func (s *CLIEventSink) Log(level, message string) {
 	switch level {
 	case "error":
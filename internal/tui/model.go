@@ -40,6 +40,8 @@ type model struct {
 	tools        []toolStatus
 	currentStage string
 	stageMessage string
+	stageDone    int
+	stageTotal   int
 	startTime    time.Time
 
 	// Stats
@@ -47,6 +49,7 @@ type model struct {
 	totalHTTP       int
 	totalPorts      int
 	totalTakeover   int
+	totalFindings   int
 
 	// Resource stats
 	memoryMB   float64
@@ -57,6 +60,8 @@ type model struct {
 	httpResults     []types.HTTPResult
 	portResults     []types.PortResult
 	takeoverResults []types.TakeoverResult
+	findings        []types.Finding
+	subdomainIndex  map[string]int // subdomain → position in subdomains
 
 	// Results tab state
 	resultOffset  int
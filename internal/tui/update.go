@@ -127,6 +127,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StageMsg:
 		m.currentStage = msg.Stage
 		m.stageMessage = msg.Message
+		m.stageDone, m.stageTotal = 0, 0
 		m.logs = append(m.logs, LogMsg{
 			Level:   "info",
 			Message: fmt.Sprintf("[%s] %s", msg.Stage, msg.Message),
@@ -134,9 +135,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		m.updateLogViewport()
 
+	case StageProgressMsg:
+		if msg.Stage == m.currentStage {
+			m.stageDone = msg.Done
+			m.stageTotal = msg.Total
+		}
+
 	case ToolProgressMsg:
 		m.updateToolStatus(msg)
-		if msg.Status != "running" {
+		if msg.Status != "running" && msg.Status != "throttled" {
 			m.logs = append(m.logs, LogMsg{
 				Level:   toolLogLevel(msg.Status),
 				Message: formatToolLog(msg),
@@ -145,8 +152,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateLogViewport()
 		}
 
+	case SubdomainMsg:
+		m.addSubdomain(msg)
+
 	case ResultMsg:
 		m.subdomains = msg.Results
+		m.subdomainIndex = nil
 		m.totalSubdomains = msg.Total
 
 	case HTTPResultMsg:
@@ -161,6 +172,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.takeoverResults = msg.Results
 		m.totalTakeover = len(msg.Results)
 
+	case FindingsMsg:
+		m.findings = msg.Findings
+		m.totalFindings = len(msg.Findings)
+
 	case LogMsg:
 		m.logs = append(m.logs, msg)
 		m.updateLogViewport()
@@ -203,7 +218,28 @@ func (m *model) updateToolStatus(msg ToolProgressMsg) {
 	})
 }
 
+// addSubdomain merges a live discovery into the results table.
+func (m *model) addSubdomain(msg SubdomainMsg) {
+	if m.subdomainIndex == nil {
+		m.subdomainIndex = make(map[string]int, len(m.subdomains))
+		for i, r := range m.subdomains {
+			m.subdomainIndex[r.Subdomain] = i
+		}
+	}
+
+	if i, ok := m.subdomainIndex[msg.Subdomain]; ok {
+		m.subdomains[i].AddSource(msg.Source, time.Now())
+	} else {
+		r := types.SubdomainResult{Subdomain: msg.Subdomain, IPs: []string{}}
+		r.AddSource(msg.Source, time.Now())
+		m.subdomainIndex[msg.Subdomain] = len(m.subdomains)
+		m.subdomains = append(m.subdomains, r)
+	}
+	m.totalSubdomains = msg.Total
+}
+
 func (m *model) sortResults() {
+	m.subdomainIndex = nil
 	sort.Slice(m.subdomains, func(i, j int) bool {
 		var a, b string
 		switch m.sortColumn {
@@ -268,7 +304,7 @@ func toolLogLevel(status string) string {
 	switch status {
 	case "failed":
 		return "error"
-	case "skipped":
+	case "skipped", "circuit-open", "partial":
 		return "warn"
 	default:
 		return "info"
@@ -283,6 +319,10 @@ func formatToolLog(msg ToolProgressMsg) string {
 		return fmt.Sprintf("%s failed for %s: %s", msg.Tool, msg.Domain, msg.Error)
 	case "skipped":
 		return fmt.Sprintf("%s skipped (not in PATH)", msg.Tool)
+	case "circuit-open":
+		return fmt.Sprintf("%s not called for %s: %s", msg.Tool, msg.Domain, msg.Error)
+	case "partial":
+		return fmt.Sprintf("%s ran out of time for %s; kept %d subdomains", msg.Tool, msg.Domain, msg.Found)
 	default:
 		return fmt.Sprintf("%s %s for %s", msg.Tool, msg.Status, msg.Domain)
 	}
@@ -102,10 +102,16 @@ func (m model) renderToolProgress(width int) string {
 		switch t.status {
 		case "completed":
 			detail = statusCompleted.Render(fmt.Sprintf("found %d", t.found))
+		case "partial":
+			detail = statusSkipped.Render(fmt.Sprintf("found %d (partial)", t.found))
 		case "failed":
 			detail = statusFailed.Render(truncate(t.err, 40))
 		case "skipped":
 			detail = statusSkipped.Render("skipped")
+		case "throttled":
+			detail = statusSkipped.Render(fmt.Sprintf("throttled on %s", t.domain))
+		case "circuit-open":
+			detail = statusFailed.Render("circuit open")
 		default:
 			detail = statusRunning.Render(fmt.Sprintf("scanning %s", t.domain))
 		}
@@ -125,6 +131,7 @@ func (m model) renderStats(width int) string {
 	lines = append(lines, m.statLine("HTTP Alive", m.totalHTTP))
 	lines = append(lines, m.statLine("Ports", m.totalPorts))
 	lines = append(lines, m.statLine("Takeover", m.totalTakeover))
+	lines = append(lines, m.statLine("Findings", m.totalFindings))
 	lines = append(lines, "")
 
 	// Stage
@@ -137,6 +144,9 @@ func (m model) renderStats(width int) string {
 	} else {
 		lines = append(lines, stageStyle.Render(fmt.Sprintf("Stage: %s", m.currentStage)))
 		lines = append(lines, m.spinner.View()+" "+statLabel.Render(m.stageMessage))
+		if m.stageTotal > 0 {
+			lines = append(lines, statLabel.Render(fmt.Sprintf("  %d/%d", m.stageDone, m.stageTotal)))
+		}
 	}
 
 	lines = append(lines, "")
@@ -242,9 +252,9 @@ func (m model) renderStatusBar() string {
 
 func (m model) toolIcon(status string) string {
 	switch status {
-	case "completed":
+	case "completed", "partial":
 		return statusCompleted.Render("*")
-	case "failed":
+	case "failed", "circuit-open":
 		return statusFailed.Render("x")
 	case "skipped":
 		return statusSkipped.Render("-")
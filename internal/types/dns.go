@@ -0,0 +1,106 @@
+package types
+
+import (
+	"slices"
+	"strings"
+)
+
+// DNSRecordTypes are the record types kept in DNSRecords, in display order.
+var DNSRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS", "SOA", "CAA"}
+
+// DNSRecords is the record set of one name, as found by the DNS enrichment
+// stage. Values are presentation strings without trailing dots, such as
+// "10 mail.example.com" for MX or `0 issue "letsencrypt.org"` for CAA.
+type DNSRecords struct {
+	A     []string `json:"a,omitempty"`
+	AAAA  []string `json:"aaaa,omitempty"`
+	CNAME []string `json:"cname,omitempty"` // alias chain, in resolution order
+	MX    []string `json:"mx,omitempty"`
+	TXT   []string `json:"txt,omitempty"`
+	NS    []string `json:"ns,omitempty"`
+	SOA   string   `json:"soa,omitempty"`
+	CAA   []string `json:"caa,omitempty"`
+}
+
+// Values returns the records of type rtype (one of DNSRecordTypes).
+func (d *DNSRecords) Values(rtype string) []string {
+	if d == nil {
+		return nil
+	}
+	switch rtype {
+	case "A":
+		return d.A
+	case "AAAA":
+		return d.AAAA
+	case "CNAME":
+		return d.CNAME
+	case "MX":
+		return d.MX
+	case "TXT":
+		return d.TXT
+	case "NS":
+		return d.NS
+	case "SOA":
+		if d.SOA != "" {
+			return []string{d.SOA}
+		}
+	case "CAA":
+		return d.CAA
+	}
+	return nil
+}
+
+// Set replaces the records of type rtype. Every type but CNAME, whose
+// order is the chain's, is sorted so record sets compare and diff stably.
+func (d *DNSRecords) Set(rtype string, values []string) {
+	if rtype != "CNAME" {
+		values = slices.Compact(slices.Sorted(slices.Values(values)))
+	}
+	switch rtype {
+	case "A":
+		d.A = values
+	case "AAAA":
+		d.AAAA = values
+	case "CNAME":
+		d.CNAME = values
+	case "MX":
+		d.MX = values
+	case "TXT":
+		d.TXT = values
+	case "NS":
+		d.NS = values
+	case "SOA":
+		d.SOA = ""
+		if len(values) > 0 {
+			d.SOA = values[0]
+		}
+	case "CAA":
+		d.CAA = values
+	}
+}
+
+// Empty reports whether d holds no records.
+func (d *DNSRecords) Empty() bool {
+	for _, rtype := range DNSRecordTypes {
+		if len(d.Values(rtype)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Lines returns one "TYPE value" line per record, in DNSRecordTypes order.
+func (d *DNSRecords) Lines() []string {
+	var lines []string
+	for _, rtype := range DNSRecordTypes {
+		for _, v := range d.Values(rtype) {
+			lines = append(lines, rtype+" "+v)
+		}
+	}
+	return lines
+}
+
+// String returns the records on one line, separated by "; ".
+func (d *DNSRecords) String() string {
+	return strings.Join(d.Lines(), "; ")
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// IPInfo is what the offline IP data knows about one address: its origin
+// network and, when it falls in a published range, the cloud or CDN
+// provider that operates it.
+type IPInfo struct {
+	IP       string `json:"ip"`
+	ASN      uint32 `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"`
+	Country  string `json:"country,omitempty"`  // ISO 3166-1 alpha-2 code
+	Provider string `json:"provider,omitempty"` // e.g. "aws", "cloudflare", or "client" for the client's own ASNs
+	Service  string `json:"service,omitempty"`  // provider's service or region, e.g. "CLOUDFRONT"
+}
+
+// Tags returns the non-empty facts about the address, such as
+// "AS13335 CLOUDFLARENET", "US" and "cloudflare".
+func (i IPInfo) Tags() []string {
+	var tags []string
+	if i.ASN != 0 {
+		tags = append(tags, strings.TrimSpace(fmt.Sprintf("AS%d %s", i.ASN, i.Org)))
+	} else if i.Org != "" {
+		tags = append(tags, i.Org)
+	}
+	if i.Country != "" {
+		tags = append(tags, i.Country)
+	}
+	if i.Provider != "" {
+		provider := i.Provider
+		if i.Service != "" {
+			provider += "/" + i.Service
+		}
+		tags = append(tags, provider)
+	}
+	return tags
+}
+
+// String returns the address followed by its tags, e.g.
+// "104.16.1.1 (AS13335 CLOUDFLARENET, US, cloudflare)".
+func (i IPInfo) String() string {
+	tags := i.Tags()
+	if len(tags) == 0 {
+		return i.IP
+	}
+	return fmt.Sprintf("%s (%s)", i.IP, strings.Join(tags, ", "))
+}
+
+// Providers returns the distinct providers of infos, in order.
+func Providers(infos []IPInfo) []string {
+	var providers []string
+	for _, info := range infos {
+		if info.Provider != "" && !slices.Contains(providers, info.Provider) {
+			providers = append(providers, info.Provider)
+		}
+	}
+	return providers
+}
+
+// Countries returns the distinct countries of infos, in order.
+func Countries(infos []IPInfo) []string {
+	var countries []string
+	for _, info := range infos {
+		if info.Country != "" && !slices.Contains(countries, info.Country) {
+			countries = append(countries, info.Country)
+		}
+	}
+	return countries
+}
+
+// FormatIPInfo joins the String form of infos with "; ".
+func FormatIPInfo(infos []IPInfo) string {
+	parts := make([]string, len(infos))
+	for i, info := range infos {
+		parts[i] = info.String()
+	}
+	return strings.Join(parts, "; ")
+}
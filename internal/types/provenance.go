@@ -0,0 +1,199 @@
+package types
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source record types: the kind of evidence a tool's answer rests on.
+const (
+	SourceCT          = "ct"          // certificate transparency logs
+	SourcePassiveDNS  = "passive-dns" // historical resolution datasets
+	SourceAggregator  = "aggregator"  // tools that merge many passive sources
+	SourceArchive     = "archive"     // web archive captures
+	SourceCrawl       = "crawl"       // links and headers seen on live pages
+	SourceDNS         = "dns"         // answers from the zone's own servers
+	SourceBruteForce  = "brute-force" // wordlist guesses confirmed by DNS
+	SourcePermutation = "permutation" // mutations of known names confirmed by DNS
+	SourceInput       = "input"       // given by the user
+	SourceUnknown     = "unknown"
+)
+
+// sourceTypesMu guards toolSourceTypes, which RegisterSourceType writes
+// while collectors may already be reading it.
+var sourceTypesMu sync.RWMutex
+
+// toolSourceTypes maps tool names to the record type of their findings.
+var toolSourceTypes = map[string]string{
+	"crtsh":          SourceCT,
+	"censys":         SourceCT,
+	"tls-san":        SourceCT,
+	"securitytrails": SourcePassiveDNS,
+	"virustotal":     SourcePassiveDNS,
+	"hackertarget":   SourcePassiveDNS,
+	"subfinder":      SourceAggregator,
+	"amass":          SourceAggregator,
+	"assetfinder":    SourceAggregator,
+	"findomain":      SourceAggregator,
+	"sublist3r":      SourceAggregator,
+	"knockpy":        SourceAggregator,
+	"waybackurls":    SourceArchive,
+	"urlscan":        SourceCrawl,
+	"linkheader":     SourceCrawl,
+	"crawl":          SourceCrawl,
+	"dnsrecon":       SourceDNS,
+	"axfr":           SourceDNS,
+	"spf":            SourceDNS,
+	"zonewalk":       SourceDNS,
+	"bruteforce":     SourceBruteForce,
+	"massdns":        SourceBruteForce,
+	"fierce":         SourceBruteForce,
+	"permute":        SourcePermutation,
+	"altdns":         SourcePermutation,
+	"input":          SourceInput,
+}
+
+// sourceWeights is how much a single record of each type is trusted on its
+// own. Records confirmed against live DNS weigh more than passive datasets,
+// which may hold names that no longer exist.
+var sourceWeights = map[string]float64{
+	SourceInput:       1.0,
+	SourceDNS:         0.95,
+	SourceCT:          0.8,
+	SourceBruteForce:  0.8,
+	SourcePassiveDNS:  0.7,
+	SourcePermutation: 0.65,
+	SourceAggregator:  0.6,
+	SourceCrawl:       0.6,
+	SourceArchive:     0.5,
+	SourceUnknown:     0.5,
+}
+
+// SourceRecord says which tool reported a subdomain, when, and what kind of
+// record the finding came from.
+type SourceRecord struct {
+	Tool      string    `json:"tool"`
+	Type      string    `json:"type"`
+	FirstSeen time.Time `json:"first_seen,omitzero"`
+	Partial   bool      `json:"partial,omitempty"` // the tool ran out of time before it finished
+}
+
+// RegisterSourceType sets the record type of a tool defined at runtime.
+func RegisterSourceType(tool, typ string) {
+	sourceTypesMu.Lock()
+	defer sourceTypesMu.Unlock()
+	toolSourceTypes[tool] = typ
+}
+
+// SourceType returns the record type for a tool name.
+func SourceType(tool string) string {
+	sourceTypesMu.RLock()
+	defer sourceTypesMu.RUnlock()
+	if t, ok := toolSourceTypes[tool]; ok {
+		return t
+	}
+	return SourceUnknown
+}
+
+// AddSource records that tool reported the subdomain at seen. It keeps the
+// legacy comma-separated Source field in step and returns false when the tool
+// was already recorded.
+func (r *SubdomainResult) AddSource(tool string, seen time.Time) bool {
+	r.Sources = r.SourceRecords()
+	for _, s := range r.Sources {
+		if s.Tool == tool {
+			return false
+		}
+	}
+	r.Sources = append(r.Sources, SourceRecord{Tool: tool, Type: SourceType(tool), FirstSeen: seen})
+	if r.Source == "" {
+		r.Source = tool
+	} else {
+		r.Source += "," + tool
+	}
+	return true
+}
+
+// MarkPartial records that tool's run was cut short by its time budget.
+func (r *SubdomainResult) MarkPartial(tool string) {
+	for i := range r.Sources {
+		if r.Sources[i].Tool == tool {
+			r.Sources[i].Partial = true
+		}
+	}
+}
+
+// SourceRecords returns the structured sources, deriving them from the legacy
+// Source string for results written before provenance was recorded.
+func (r *SubdomainResult) SourceRecords() []SourceRecord {
+	if len(r.Sources) > 0 || r.Source == "" {
+		return r.Sources
+	}
+	var records []SourceRecord
+	for _, tool := range strings.Split(r.Source, ",") {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			records = append(records, SourceRecord{Tool: tool, Type: SourceType(tool)})
+		}
+	}
+	return records
+}
+
+// SourceTypes returns the distinct record types behind the result, in the
+// order they were first seen.
+func (r *SubdomainResult) SourceTypes() []string {
+	var kinds []string
+	seen := make(map[string]bool)
+	for _, s := range r.SourceRecords() {
+		if !seen[s.Type] {
+			seen[s.Type] = true
+			kinds = append(kinds, s.Type)
+		}
+	}
+	return kinds
+}
+
+// FirstSeen returns the earliest time any source reported the result, or the
+// zero time when none is known.
+func (r *SubdomainResult) FirstSeen() time.Time {
+	var first time.Time
+	for _, s := range r.SourceRecords() {
+		if !s.FirstSeen.IsZero() && (first.IsZero() || s.FirstSeen.Before(first)) {
+			first = s.FirstSeen
+		}
+	}
+	return first
+}
+
+// Score fills in Sources (from the legacy string if needed) and Confidence.
+// Each tool counts as independent evidence: the confidence is the chance that
+// not every source is wrong, raised when the name resolves and lowered when
+// it only matches a wildcard record.
+func (r *SubdomainResult) Score() {
+	r.Sources = r.SourceRecords()
+
+	miss := 1.0
+	for _, s := range r.Sources {
+		w, ok := sourceWeights[s.Type]
+		if !ok {
+			w = sourceWeights[SourceUnknown]
+		}
+		miss *= 1 - w
+	}
+	c := 1 - miss
+	if len(r.IPs) > 0 {
+		c += (1 - c) / 2
+	}
+	if r.Wildcard {
+		c /= 2
+	}
+	r.Confidence = math.Round(c*100) / 100
+}
+
+// ScoreAll scores every result in place.
+func ScoreAll(results []SubdomainResult) {
+	for i := range results {
+		results[i].Score()
+	}
+}
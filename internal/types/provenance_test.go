@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSubdomainResultAddSource(t *testing.T) {
+	seen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := SubdomainResult{Subdomain: "api.example.com"}
+
+	if !r.AddSource("crtsh", seen) {
+		t.Error("Expected first crtsh record to be added")
+	}
+	if !r.AddSource("bruteforce", seen.Add(time.Minute)) {
+		t.Error("Expected bruteforce record to be added")
+	}
+	if r.AddSource("crtsh", seen.Add(time.Hour)) {
+		t.Error("Expected duplicate crtsh record to be ignored")
+	}
+
+	if r.Source != "crtsh,bruteforce" {
+		t.Errorf("Expected legacy source 'crtsh,bruteforce', got '%s'", r.Source)
+	}
+	if len(r.Sources) != 2 || r.Sources[0].Type != SourceCT || r.Sources[1].Type != SourceBruteForce {
+		t.Errorf("Unexpected source records: %+v", r.Sources)
+	}
+	if !r.FirstSeen().Equal(seen) {
+		t.Errorf("Expected first seen %v, got %v", seen, r.FirstSeen())
+	}
+}
+
+func TestSubdomainResultLegacySource(t *testing.T) {
+	// Results written before structured sources existed only carry the string.
+	var r SubdomainResult
+	if err := json.Unmarshal([]byte(`{"subdomain":"www.example.com","source":"subfinder, waybackurls","ips":["192.0.2.1"]}`), &r); err != nil {
+		t.Fatalf("Failed to unmarshal legacy result: %v", err)
+	}
+
+	records := r.SourceRecords()
+	if len(records) != 2 || records[0].Tool != "subfinder" || records[1].Type != SourceArchive {
+		t.Errorf("Unexpected derived records: %+v", records)
+	}
+	if !r.FirstSeen().IsZero() {
+		t.Error("Expected unknown first-seen time for legacy result")
+	}
+
+	r.Score()
+	if len(r.Sources) != 2 {
+		t.Errorf("Expected Score to fill in Sources, got %+v", r.Sources)
+	}
+}
+
+func TestSubdomainResultScore(t *testing.T) {
+	single := SubdomainResult{Source: "waybackurls"}
+	single.Score()
+
+	corroborated := SubdomainResult{Source: "waybackurls,crtsh"}
+	corroborated.Score()
+
+	resolved := SubdomainResult{Source: "waybackurls,crtsh", IPs: []string{"192.0.2.1"}}
+	resolved.Score()
+
+	wildcard := SubdomainResult{Source: "waybackurls,crtsh", IPs: []string{"192.0.2.1"}, Wildcard: true}
+	wildcard.Score()
+
+	if single.Confidence != 0.5 {
+		t.Errorf("Expected 0.5 for a single archive source, got %v", single.Confidence)
+	}
+	if corroborated.Confidence <= single.Confidence {
+		t.Errorf("Expected corroboration to raise confidence: %v <= %v", corroborated.Confidence, single.Confidence)
+	}
+	if resolved.Confidence <= corroborated.Confidence {
+		t.Errorf("Expected resolution to raise confidence: %v <= %v", resolved.Confidence, corroborated.Confidence)
+	}
+	if wildcard.Confidence >= resolved.Confidence {
+		t.Errorf("Expected wildcard match to lower confidence: %v >= %v", wildcard.Confidence, resolved.Confidence)
+	}
+	if resolved.Confidence > 1 {
+		t.Errorf("Confidence must not exceed 1, got %v", resolved.Confidence)
+	}
+}
+
+func TestSubdomainResultMarkPartial(t *testing.T) {
+	r := SubdomainResult{Subdomain: "api.example.com"}
+	r.AddSource("amass", time.Time{})
+	r.AddSource("crtsh", time.Time{})
+	r.MarkPartial("amass")
+	if !r.Sources[0].Partial || r.Sources[1].Partial {
+		t.Errorf("Expected only the amass record to be partial, got %+v", r.Sources)
+	}
+	data, _ := json.Marshal(r.Sources[1])
+	if string(data) != `{"tool":"crtsh","type":"ct"}` {
+		t.Errorf("Expected complete records to omit partial, got %s", data)
+	}
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// TLSCert is the leaf certificate a host presented during the TLS handshake.
+type TLSCert struct {
+	SubjectCN   string    `json:"subject_cn,omitempty"`
+	SANs        []string  `json:"sans,omitempty"`
+	IssuerCN    string    `json:"issuer_cn,omitempty"`
+	IssuerOrg   string    `json:"issuer_org,omitempty"`
+	NotBefore   time.Time `json:"not_before,omitzero"`
+	NotAfter    time.Time `json:"not_after,omitzero"`
+	Fingerprint string    `json:"fingerprint_sha256,omitempty"`
+}
+
+// NewTLSCert copies the fields we keep from cert.
+func NewTLSCert(cert *x509.Certificate) *TLSCert {
+	sum := sha256.Sum256(cert.Raw)
+	c := &TLSCert{
+		SubjectCN:   cert.Subject.CommonName,
+		SANs:        append([]string(nil), cert.DNSNames...),
+		IssuerCN:    cert.Issuer.CommonName,
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		Fingerprint: hex.EncodeToString(sum[:]),
+	}
+	if len(cert.Issuer.Organization) > 0 {
+		c.IssuerOrg = cert.Issuer.Organization[0]
+	}
+	return c
+}
+
+// Hostnames returns the distinct host names the certificate is valid for,
+// from the CN and the DNS SANs, lower case and with wildcard labels removed
+// ("*.dev.example.com" becomes "dev.example.com").
+func (c *TLSCert) Hostnames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range append([]string{c.SubjectCN}, c.SANs...) {
+		name = strings.TrimPrefix(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), ".")), "*.")
+		if name == "" || strings.ContainsAny(name, " *") || !strings.Contains(name, ".") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
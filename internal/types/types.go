@@ -1,9 +1,15 @@
 package types
 
 type SubdomainResult struct {
-	Subdomain string   `json:"subdomain"`
-	Source    string   `json:"source"`
-	IPs       []string `json:"ips,omitempty"`
+	Subdomain    string         `json:"subdomain"`
+	Source       string         `json:"source"` // comma-separated tool names, kept for older consumers
+	Sources      []SourceRecord `json:"sources,omitempty"`
+	Confidence   float64        `json:"confidence,omitempty"` // 0-1, see Score
+	IPs          []string       `json:"ips,omitempty"`
+	Wildcard     bool           `json:"wildcard,omitempty"`      // answer only matches the zone's wildcard record
+	WildcardZone string         `json:"wildcard_zone,omitempty"` // closest enclosing zone with a wildcard record
+	DNS          *DNSRecords    `json:"dns,omitempty"`           // full record set, from the DNS enrichment stage
+	IPInfo       []IPInfo       `json:"ip_info,omitempty"`       // origin network of each IP, from the IP enrichment stage
 }
 
 type LinkHeader struct {
@@ -26,6 +32,10 @@ type HTTPResult struct {
 	DetectedTech  []Technology `json:"detected_tech,omitempty"`
 	ContentLength int          `json:"content_length,omitempty"`
 	LinkHeaders   []LinkHeader `json:"link_headers,omitempty"`
+	TLS           *TLSCert     `json:"tls,omitempty"`          // certificate presented by HTTPS URLs
+	BodyHash      string       `json:"body_hash,omitempty"`    // SHA-256 of the response body
+	FaviconHash   string       `json:"favicon_hash,omitempty"` // MurmurHash3 of the favicon, as in Shodan's http.favicon.hash
+	CDN           string       `json:"cdn,omitempty"`          // CDN or WAF the response came through
 }
 
 type TakeoverResult struct {
@@ -36,10 +46,30 @@ type TakeoverResult struct {
 	Evidence  string `json:"evidence"`
 }
 
+// Finding severities, from most to least urgent.
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+	SeverityInfo   = "info"
+)
+
+// Finding is a misconfiguration or exposure worth reporting to the client,
+// such as a nameserver that allows zone transfers.
+type Finding struct {
+	Type     string   `json:"type"` // e.g. "axfr-open"
+	Severity string   `json:"severity"`
+	Target   string   `json:"target"` // host or zone the finding applies to
+	Title    string   `json:"title"`
+	Evidence string   `json:"evidence,omitempty"`
+	Details  []string `json:"details,omitempty"`
+}
+
 type PortResult struct {
-	Host  string `json:"host"`
-	IP    string `json:"ip,omitempty"`
-	Ports []Port `json:"ports,omitempty"`
+	Host   string  `json:"host"`
+	IP     string  `json:"ip,omitempty"`
+	IPInfo *IPInfo `json:"ip_info,omitempty"`
+	Ports  []Port  `json:"ports,omitempty"`
 }
 
 type Port struct {
@@ -63,4 +93,5 @@ type ScanResults struct {
 	Ports      []PortResult      `json:"ports,omitempty"`
 	Wayback    []WaybackEntry    `json:"wayback,omitempty"`
 	Takeover   []TakeoverResult  `json:"takeover,omitempty"`
+	Findings   []Finding         `json:"findings,omitempty"`
 }
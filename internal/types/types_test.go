@@ -75,6 +75,40 @@ func TestSubdomainResultEmptyIPs(t *testing.T) {
 	}
 }
 
+func TestSubdomainResultWildcard(t *testing.T) {
+	// Test wildcard fields round-trip and are omitted when unset
+	result := SubdomainResult{
+		Subdomain:    "random.example.com",
+		Source:       "massdns",
+		Wildcard:     true,
+		WildcardZone: "example.com",
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal SubdomainResult: %v", err)
+	}
+
+	var unmarshaled SubdomainResult
+	if err := json.Unmarshal(jsonData, &unmarshaled); err != nil {
+		t.Fatalf("Failed to unmarshal SubdomainResult: %v", err)
+	}
+	if !unmarshaled.Wildcard {
+		t.Error("Expected Wildcard to be true")
+	}
+	if unmarshaled.WildcardZone != "example.com" {
+		t.Errorf("Expected WildcardZone 'example.com', got '%s'", unmarshaled.WildcardZone)
+	}
+
+	plain, err := json.Marshal(SubdomainResult{Subdomain: "www.example.com", Source: "subfinder"})
+	if err != nil {
+		t.Fatalf("Failed to marshal SubdomainResult: %v", err)
+	}
+	if string(plain) != `{"subdomain":"www.example.com","source":"subfinder"}` {
+		t.Errorf("Expected wildcard fields to be omitted, got %s", plain)
+	}
+}
+
 func TestHTTPResult(t *testing.T) {
 	// Test HTTPResult struct
 	result := HTTPResult{
@@ -383,3 +417,55 @@ func TestScanResultsEmpty(t *testing.T) {
 		t.Errorf("Expected 0 port results, got %d", len(unmarshaled.Ports))
 	}
 }
+
+func TestTLSCertHostnames(t *testing.T) {
+	cert := TLSCert{
+		SubjectCN: "www.example.com",
+		SANs:      []string{"www.example.com", "*.API.example.com", "mail.example.com.", "localhost", "*"},
+	}
+
+	got := cert.Hostnames()
+	want := []string{"www.example.com", "api.example.com", "mail.example.com"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestDNSRecords(t *testing.T) {
+	d := &DNSRecords{}
+	if !d.Empty() {
+		t.Error("Expected a new record set to be empty")
+	}
+	d.Set("A", []string{"192.0.2.2", "192.0.2.1", "192.0.2.2"})
+	d.Set("CNAME", []string{"edge.example.net", "cdn.example.org"})
+	d.Set("SOA", []string{"ns1.example.com hostmaster.example.com 1 7200 3600 1209600 300"})
+
+	want := []string{
+		"A 192.0.2.1",
+		"A 192.0.2.2",
+		"CNAME edge.example.net",
+		"CNAME cdn.example.org",
+		"SOA ns1.example.com hostmaster.example.com 1 7200 3600 1209600 300",
+	}
+	got := d.Lines()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	var nilRecords *DNSRecords
+	if nilRecords.Values("A") != nil || !nilRecords.Empty() {
+		t.Error("Expected a nil record set to hold no records")
+	}
+}
@@ -12,17 +12,19 @@ import (
 )
 
 type Checkpoint struct {
-	ScanID       string                  `json:"scan_id"`
-	Timestamp    time.Time               `json:"timestamp"`
-	Domain       string                  `json:"domain"`
-	WildcardFile string                  `json:"wildcard_file"`
-	Config       map[string]interface{}  `json:"config"`
-	Progress     ProgressState           `json:"progress"`
-	Subdomains   []types.SubdomainResult `json:"subdomains"`
-	HTTPResults  []types.HTTPResult      `json:"http_results"`
-	PortResults  []types.PortResult      `json:"port_results"`
-	Completed    bool                    `json:"completed"`
-	ErrorMessage string                  `json:"error_message,omitempty"`
+	ScanID         string                  `json:"scan_id"`
+	Timestamp      time.Time               `json:"timestamp"`
+	Domain         string                  `json:"domain"`
+	WildcardFile   string                  `json:"wildcard_file"`
+	Config         map[string]interface{}  `json:"config"`
+	Progress       ProgressState           `json:"progress"`
+	Subdomains     []types.SubdomainResult `json:"subdomains"`
+	ResolutionDone bool                    `json:"resolution_done,omitempty"`
+	HTTPResults    []types.HTTPResult      `json:"http_results"`
+	PortResults    []types.PortResult      `json:"port_results"`
+	Findings       []types.Finding         `json:"findings,omitempty"`
+	Completed      bool                    `json:"completed"`
+	ErrorMessage   string                  `json:"error_message,omitempty"`
 }
 
 type ProgressState struct {
@@ -140,6 +142,17 @@ func (c *Checkpoint) AddSubdomains(subdomains []types.SubdomainResult) {
 	c.Subdomains = append(c.Subdomains, subdomains...)
 }
 
+// SetSubdomains replaces the stored subdomains, e.g. after IPs were resolved.
+func (c *Checkpoint) SetSubdomains(subdomains []types.SubdomainResult) {
+	c.Subdomains = subdomains
+	c.Progress.LastUpdate = time.Now()
+}
+
+func (c *Checkpoint) MarkResolutionDone() {
+	c.ResolutionDone = true
+	c.Progress.LastUpdate = time.Now()
+}
+
 func (c *Checkpoint) AddHTTPResults(results []types.HTTPResult) {
 	c.HTTPResults = append(c.HTTPResults, results...)
 }
@@ -148,6 +161,10 @@ func (c *Checkpoint) AddPortResults(results []types.PortResult) {
 	c.PortResults = append(c.PortResults, results...)
 }
 
+func (c *Checkpoint) AddFindings(findings []types.Finding) {
+	c.Findings = append(c.Findings, findings...)
+}
+
 func (c *Checkpoint) MarkCompleted() {
 	c.Completed = true
 	c.Progress.LastUpdate = time.Now()
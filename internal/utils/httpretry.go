@@ -59,10 +59,14 @@ func DoWithRetry(client *http.Client, req *http.Request, maxRetries int, timeout
 		_ = resp.Body.Close()
 
 		if attempt == maxRetries {
-			return nil, &RateLimitError{
+			rl := &RateLimitError{
 				StatusCode: 429,
 				Message:    fmt.Sprintf("rate limited after %d attempts", maxRetries+1),
 			}
+			if h := resp.Header.Get("Retry-After"); h != "" {
+				rl.RetryAfter = ParseRetryAfter(h, attempt)
+			}
+			return nil, rl
 		}
 
 		wait := ParseRetryAfter(resp.Header.Get("Retry-After"), attempt)
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DNSQueryTimeout bounds a single DNS lookup so that one slow resolver
+// cannot stall a worker.
+const DNSQueryTimeout = 5 * time.Second
+
+// DefaultResolvers answers raw queries when the system resolver configuration
+// cannot be read.
+var DefaultResolvers = []string{
+	"1.1.1.1:53",
+	"8.8.8.8:53",
+	"9.9.9.9:53",
+	"1.0.0.1:53",
+	"8.8.4.4:53",
+}
+
+// LoadResolvers parses a resolver specification: either the path to a file
+// containing one resolver per line, or a comma-separated list of resolvers.
+// Entries without a port default to port 53.
+func LoadResolvers(spec string) ([]string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	var entries []string
+	if FileExists(spec) {
+		lines, err := ReadLines(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resolvers file: %v", err)
+		}
+		entries = lines
+	} else {
+		entries = strings.Split(spec, ",")
+	}
+
+	var resolvers []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		resolver, err := NormalizeResolver(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[resolver] {
+			seen[resolver] = true
+			resolvers = append(resolvers, resolver)
+		}
+	}
+	return resolvers, nil
+}
+
+// NormalizeResolver validates a resolver address and returns it in canonical
+// form. Plain addresses ("1.1.1.1", "udp://1.1.1.1") become host:port with the
+// default DNS port; "tcp://" and "tls://" (DNS-over-TLS, port 853) keep their
+// scheme; "https://" URLs are DNS-over-HTTPS endpoints and are kept as-is.
+func NormalizeResolver(addr string) (string, error) {
+	scheme := "udp"
+	if i := strings.Index(addr, "://"); i >= 0 {
+		scheme, addr = strings.ToLower(addr[:i]), addr[i+3:]
+	}
+
+	switch scheme {
+	case "https":
+		u, err := url.Parse("https://" + addr)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("invalid DoH resolver URL: https://%s", addr)
+		}
+		if u.Path == "" {
+			u.Path = "/dns-query"
+		}
+		return u.String(), nil
+	case "udp", "tcp", "tls":
+	default:
+		return "", fmt.Errorf("unsupported resolver scheme: %s", scheme)
+	}
+
+	defaultPort := "53"
+	if scheme == "tls" {
+		defaultPort = "853"
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		// No port (or a bare IPv6 address) — use the default port.
+		host, port = strings.Trim(addr, "[]"), defaultPort
+	}
+	// DoT servers may be given by name since the name is needed for TLS anyway.
+	if net.ParseIP(host) == nil && (scheme != "tls" || ValidateDomain(host) != nil) {
+		return "", fmt.Errorf("invalid resolver address: %s", addr)
+	}
+
+	hostPort := net.JoinHostPort(host, port)
+	if scheme == "udp" {
+		return hostPort, nil
+	}
+	return scheme + "://" + hostPort, nil
+}
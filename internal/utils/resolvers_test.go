@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeResolver(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1.1.1.1", "1.1.1.1:53", false},
+		{"8.8.8.8:5353", "8.8.8.8:5353", false},
+		{"2606:4700:4700::1111", "[2606:4700:4700::1111]:53", false},
+		{"[2606:4700:4700::1111]:53", "[2606:4700:4700::1111]:53", false},
+		{"udp://9.9.9.9", "9.9.9.9:53", false},
+		{"tcp://1.1.1.1", "tcp://1.1.1.1:53", false},
+		{"tls://1.1.1.1", "tls://1.1.1.1:853", false},
+		{"tls://dns.google:853", "tls://dns.google:853", false},
+		{"https://cloudflare-dns.com/dns-query", "https://cloudflare-dns.com/dns-query", false},
+		{"https://dns.google", "https://dns.google/dns-query", false},
+		{"quic://1.1.1.1", "", true},
+		{"resolver.example.com", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeResolver(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeResolver(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeResolver(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLoadResolversList(t *testing.T) {
+	resolvers, err := LoadResolvers("1.1.1.1, 8.8.8.8:53,1.1.1.1")
+	if err != nil {
+		t.Fatalf("LoadResolvers returned error: %v", err)
+	}
+	if len(resolvers) != 2 {
+		t.Fatalf("Expected 2 unique resolvers, got %d: %v", len(resolvers), resolvers)
+	}
+	if resolvers[0] != "1.1.1.1:53" || resolvers[1] != "8.8.8.8:53" {
+		t.Errorf("Unexpected resolvers: %v", resolvers)
+	}
+}
+
+func TestLoadResolversFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolvers.txt")
+	content := "# public resolvers\n9.9.9.9\n\n1.0.0.1:53\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write resolvers file: %v", err)
+	}
+
+	resolvers, err := LoadResolvers(path)
+	if err != nil {
+		t.Fatalf("LoadResolvers returned error: %v", err)
+	}
+	if len(resolvers) != 2 || resolvers[0] != "9.9.9.9:53" || resolvers[1] != "1.0.0.1:53" {
+		t.Errorf("Unexpected resolvers: %v", resolvers)
+	}
+}
+
+func TestLoadResolversInvalid(t *testing.T) {
+	if _, err := LoadResolvers("not-an-ip"); err == nil {
+		t.Error("Expected error for invalid resolver")
+	}
+	resolvers, err := LoadResolvers("")
+	if err != nil || resolvers != nil {
+		t.Errorf("Expected nil resolvers for empty spec, got %v (err %v)", resolvers, err)
+	}
+}
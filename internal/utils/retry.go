@@ -1,10 +1,25 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
+// stopError marks an error that retrying cannot fix.
+type stopError struct {
+	err error
+}
+
+func (e *stopError) Error() string { return e.err.Error() }
+func (e *stopError) Unwrap() error { return e.err }
+
+// StopRetry wraps err so that Retry returns it at once instead of trying
+// again, e.g. when a source is rate limited or its circuit is open.
+func StopRetry(err error) error {
+	return &stopError{err: err}
+}
+
 func Retry[T any](fn func() (T, error), retries int, timeout int) (T, error) {
 	var zero T
 	var err error
@@ -14,6 +29,10 @@ func Retry[T any](fn func() (T, error), retries int, timeout int) (T, error) {
 		if fnErr == nil {
 			return result, nil
 		}
+		var stop *stopError
+		if errors.As(fnErr, &stop) {
+			return zero, stop.err
+		}
 		err = fnErr
 
 		// Exponential backoff
@@ -186,3 +186,20 @@ func TestRetryBackoffTiming(t *testing.T) {
 		t.Errorf("Expected 3 calls, got %d", callCount)
 	}
 }
+
+func TestRetryStop(t *testing.T) {
+	callCount := 0
+	expectedError := errors.New("rate limited")
+	fn := func() (int, error) {
+		callCount++
+		return 0, StopRetry(expectedError)
+	}
+
+	_, err := Retry(fn, 3, 10)
+	if err != expectedError {
+		t.Errorf("Expected the unwrapped error, got %v", err)
+	}
+	if callCount != 1 {
+		t.Errorf("Expected 1 call, got %d", callCount)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
 )
 
 type SignalHandler struct {
@@ -46,6 +48,7 @@ func (sh *SignalHandler) Start() {
 			}
 		}
 
+		credentials.Save()
 		os.Exit(1)
 	}()
 }
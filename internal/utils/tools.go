@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
 )
 
 // Tool represents a subdomain enumeration or scanning tool
@@ -17,8 +19,29 @@ type Tool struct {
 	Required    bool
 }
 
+// registeredTools are tools added at runtime, such as declarative sources,
+// with the check that decides whether each is available.
+var (
+	registeredTools     []Tool
+	registeredAvailable = make(map[string]func() bool)
+)
+
+// RegisterTool adds a runtime-defined tool to GetRequiredTools, so
+// --check-tools lists it; available reports whether it can run.
+func RegisterTool(tool Tool, available func() bool) {
+	if _, exists := registeredAvailable[tool.Command]; !exists {
+		registeredTools = append(registeredTools, tool)
+	}
+	registeredAvailable[tool.Command] = available
+}
+
 // GetRequiredTools returns a list of tools that SubdomainX can use
 func GetRequiredTools() []Tool {
+	return append(builtinTools(), registeredTools...)
+}
+
+// builtinTools returns the tools compiled into SubdomainX.
+func builtinTools() []Tool {
 	return []Tool{
 		{
 			Name:        "subfinder",
@@ -180,9 +203,9 @@ func GetRequiredTools() []Tool {
 			Command:     "securitytrails",
 			Description: "SecurityTrails API for subdomain enumeration",
 			InstallCmd: map[string]string{
-				"linux":   "Set SECURITYTRAILS_API_KEY environment variable",
-				"darwin":  "Set SECURITYTRAILS_API_KEY environment variable",
-				"windows": "Set SECURITYTRAILS_API_KEY environment variable",
+				"linux":   "Set SECURITYTRAILS_API_KEY environment variable or add keys to the credentials file",
+				"darwin":  "Set SECURITYTRAILS_API_KEY environment variable or add keys to the credentials file",
+				"windows": "Set SECURITYTRAILS_API_KEY environment variable or add keys to the credentials file",
 			},
 			Required: false,
 		},
@@ -191,9 +214,9 @@ func GetRequiredTools() []Tool {
 			Command:     "virustotal",
 			Description: "VirusTotal API for subdomain enumeration",
 			InstallCmd: map[string]string{
-				"linux":   "Set VIRUSTOTAL_API_KEY environment variable",
-				"darwin":  "Set VIRUSTOTAL_API_KEY environment variable",
-				"windows": "Set VIRUSTOTAL_API_KEY environment variable",
+				"linux":   "Set VIRUSTOTAL_API_KEY environment variable or add keys to the credentials file",
+				"darwin":  "Set VIRUSTOTAL_API_KEY environment variable or add keys to the credentials file",
+				"windows": "Set VIRUSTOTAL_API_KEY environment variable or add keys to the credentials file",
 			},
 			Required: false,
 		},
@@ -202,13 +225,68 @@ func GetRequiredTools() []Tool {
 			Command:     "censys",
 			Description: "Censys API for subdomain enumeration",
 			InstallCmd: map[string]string{
-				"linux":   "Set CENSYS_API_ID and CENSYS_SECRET environment variables",
-				"darwin":  "Set CENSYS_API_ID and CENSYS_SECRET environment variables",
-				"windows": "Set CENSYS_API_ID and CENSYS_SECRET environment variables",
+				"linux":   "Set CENSYS_API_ID and CENSYS_SECRET environment variables or add keys to the credentials file",
+				"darwin":  "Set CENSYS_API_ID and CENSYS_SECRET environment variables or add keys to the credentials file",
+				"windows": "Set CENSYS_API_ID and CENSYS_SECRET environment variables or add keys to the credentials file",
 			},
 			Required: false,
 		},
 		// --- Built-in / public API tools (always available) ---
+		{
+			Name:        "bruteforce",
+			Command:     "bruteforce",
+			Description: "Native DNS brute-forcing with the shipped or custom wordlist",
+			InstallCmd: map[string]string{
+				"linux":   "Built-in (no installation required)",
+				"darwin":  "Built-in (no installation required)",
+				"windows": "Built-in (no installation required)",
+			},
+			Required: false,
+		},
+		{
+			Name:        "axfr",
+			Command:     "axfr",
+			Description: "Native zone transfer (AXFR) attempts against every nameserver of the target",
+			InstallCmd: map[string]string{
+				"linux":   "Built-in (no installation required)",
+				"darwin":  "Built-in (no installation required)",
+				"windows": "Built-in (no installation required)",
+			},
+			Required: false,
+		},
+		{
+			Name:        "tls-san",
+			Command:     "tls-san",
+			Description: "Native TLS certificate SAN harvesting from discovered hosts",
+			InstallCmd: map[string]string{
+				"linux":   "Built-in (no installation required)",
+				"darwin":  "Built-in (no installation required)",
+				"windows": "Built-in (no installation required)",
+			},
+			Required: false,
+		},
+		{
+			Name:        "zonewalk",
+			Command:     "zonewalk",
+			Description: "Native NSEC walking and NSEC3 hash cracking for DNSSEC-signed zones",
+			InstallCmd: map[string]string{
+				"linux":   "Built-in (no installation required)",
+				"darwin":  "Built-in (no installation required)",
+				"windows": "Built-in (no installation required)",
+			},
+			Required: false,
+		},
+		{
+			Name:        "permute",
+			Command:     "permute",
+			Description: "Native permutation of discovered subdomains (insert, prefix/suffix, number, dash/dot)",
+			InstallCmd: map[string]string{
+				"linux":   "Built-in (no installation required)",
+				"darwin":  "Built-in (no installation required)",
+				"windows": "Built-in (no installation required)",
+			},
+			Required: false,
+		},
 		{
 			Name:        "linkheader",
 			Command:     "linkheader",
@@ -258,16 +336,13 @@ func GetRequiredTools() []Tool {
 
 // CheckToolAvailability checks if a tool is available in PATH or as an API
 func CheckToolAvailability(toolName string) bool {
+	if available, ok := registeredAvailable[toolName]; ok {
+		return available()
+	}
 	switch toolName {
-	case "securitytrails":
-		return strings.TrimSpace(os.Getenv("SECURITYTRAILS_API_KEY")) != ""
-	case "virustotal":
-		return strings.TrimSpace(os.Getenv("VIRUSTOTAL_API_KEY")) != ""
-	case "censys":
-		apiID := strings.TrimSpace(os.Getenv("CENSYS_API_ID"))
-		secret := strings.TrimSpace(os.Getenv("CENSYS_SECRET"))
-		return apiID != "" && secret != ""
-	case "linkheader", "crtsh", "urlscan", "hackertarget":
+	case "securitytrails", "virustotal", "censys":
+		return credentials.Has(toolName)
+	case "linkheader", "crtsh", "urlscan", "hackertarget", "bruteforce", "permute", "axfr", "zonewalk", "tls-san":
 		return true
 	default:
 		_, err := exec.LookPath(toolName)
@@ -513,7 +588,7 @@ func InstallTools() error {
 
 	// --- API keys ---
 	if len(apiTools) > 0 {
-		fmt.Println("API Keys (set these environment variables):")
+		fmt.Println("API Keys (set these environment variables or add them to the credentials file):")
 		apiInfo := map[string]string{
 			"securitytrails": "SECURITYTRAILS_API_KEY    — https://securitytrails.com/",
 			"virustotal":     "VIRUSTOTAL_API_KEY        — https://virustotal.com/",
@@ -556,6 +631,9 @@ func DisplayToolStatus() {
 		fmt.Printf("\n✅ Available tools (%d):\n", len(available))
 		for _, tool := range available {
 			fmt.Printf("  • %s - %s\n", tool.Name, tool.Description)
+			if status := credentials.Status(tool.Name); status != "" {
+				fmt.Printf("      %s\n", status)
+			}
 		}
 	}
 
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
+	"github.com/itszeeshan/subdomainx/v2/internal/enumerator"
+	"github.com/itszeeshan/subdomainx/v2/internal/plugin"
 	"github.com/itszeeshan/subdomainx/v2/internal/tui"
 	"github.com/itszeeshan/subdomainx/v2/internal/utils"
 )
@@ -35,6 +39,18 @@ func main() {
 		timeout         = flag.Int("timeout", 30, "Timeout in seconds")
 		rateLimit       = flag.Int("rate-limit", 100, "Rate limit per second")
 		wordlist        = flag.String("wordlist", "", "Custom wordlist file for brute-forcing")
+		resolvers       = flag.String("resolvers", "", "DNS resolvers: IP[:port], tcp://, tls:// (DoT) or https:// (DoH); comma-separated or file")
+		dnsThreads      = flag.Int("dns-threads", 50, "Number of concurrent DNS queries")
+		dnsRateLimit    = flag.Int("dns-rate-limit", 500, "DNS queries per second")
+		permDepth       = flag.Int("permutation-depth", 1, "Permutation rounds (each round permutes the previous round's finds)")
+		permBudget      = flag.Int("permutation-budget", 20000, "Maximum permutation candidates resolved per domain")
+		recursionDepth  = flag.Int("recursion-depth", 0, "Recursively enumerate discovered sub-zones up to this depth (0 disables)")
+		recursionMin    = flag.Int("recursion-min-children", 3, "Discovered children needed before a sub-zone is enumerated recursively")
+		scopeInclude    = flag.String("scope-include", "", "In-scope host patterns (comma-separated globs or re:regex)")
+		scopeExclude    = flag.String("scope-exclude", "", "Out-of-scope host patterns (comma-separated globs or re:regex)")
+		scopeAllowCIDR  = flag.String("scope-allow-cidr", "", "In-scope address ranges (comma-separated CIDRs or IPs)")
+		scopeDenyCIDR   = flag.String("scope-deny-cidr", "", "Out-of-scope address ranges (comma-separated CIDRs or IPs)")
+		wildcardFilter  = flag.String("wildcard-filter", "", "Handling of wildcard DNS matches: drop, flag or off (default: drop)")
 		resume          = flag.String("resume", "", "Resume scan from checkpoint (scan ID)")
 		listCheckpoints = flag.Bool("list-checkpoints", false, "List available checkpoints")
 		verbose         = flag.Bool("verbose", false, "Verbose output")
@@ -47,12 +63,33 @@ func main() {
 		screenshotRes   = flag.String("screenshot-resolution", "1280x720", "Screenshot viewport resolution (WxH)")
 		diffMode        = flag.Bool("diff", false, "Compare results against previous scan")
 		baselineFile    = flag.String("baseline", "", "Baseline results file for diff comparison")
-		notifyFlag      = flag.String("notify", "", "Notification channels (comma-separated: slack,discord,telegram,email)")
+		notifyFlag      = flag.String("notify", "", "Notification channels (comma-separated: slack,discord,telegram,email or a notifier plugin)")
 		techFlag        = flag.Bool("tech", false, "Enable technology fingerprinting during HTTP scanning")
 		techFilter      = flag.String("tech-filter", "", "Filter results by technology (comma-separated, e.g., 'WordPress,nginx')")
+		crawlFlag       = flag.Bool("crawl", false, "Crawl live HTTP results for host names in pages, scripts and headers")
+		crawlDepth      = flag.Int("crawl-depth", 2, "Link levels the crawler follows from each live URL")
+		crawlPages      = flag.Int("crawl-pages", 500, "Maximum pages the crawler fetches")
+		dnsRecords      = flag.Bool("dns-records", false, "Store the full DNS record set (A, AAAA, CNAME chain, MX, TXT, NS, SOA, CAA) of every subdomain")
+		emailSecurity   = flag.Bool("email-security", false, "Check SPF, DMARC, DKIM, MTA-STS, TLS-RPT and BIMI of every target domain")
+		dkimSelectors   = flag.String("dkim-selectors", "", "Extra DKIM selectors to probe (comma-separated)")
+		originCheck     = flag.Bool("origin-check", false, "Find CDN/WAF-fronted sites whose origin a sibling subdomain serves directly")
+		ipEnrich        = flag.Bool("ip-enrich", false, "Tag IPs with ASN, organisation, country and cloud provider from local data files")
+		ipDataDir       = flag.String("ip-data-dir", "", "Directory of IP data files (default: user cache directory)")
+		ipDatabases     = flag.String("ip-db", "", "Extra ip2asn TSV or MaxMind .mmdb files (comma-separated)")
+		clientASNs      = flag.String("client-asn", "", "The client's own ASNs, tagged as provider \"client\" (comma-separated)")
+		providerFilter  = flag.String("provider-filter", "", "Keep hosts on these providers (comma-separated, !name excludes, e.g. 'aws,!cloudflare')")
+		asnFilter       = flag.String("asn-filter", "", "Keep hosts in these ASNs (comma-separated, !ASN excludes)")
+		countryFilter   = flag.String("country-filter", "", "Keep hosts in these countries (comma-separated ISO codes, !CC excludes)")
+		updateIPData    = flag.Bool("update-ip-data", false, "Download the ip2asn table and cloud IP ranges and exit")
 		takeoverFlag    = flag.Bool("takeover", false, "Check for subdomain takeover vulnerabilities")
 		takeoverOnly    = flag.Bool("takeover-only", false, "Only show subdomains vulnerable to takeover")
 		tuiMode         = flag.Bool("tui", false, "Enable interactive TUI dashboard")
+		sourcesDir      = flag.String("sources-dir", enumerator.DefaultSourcesDir(), "Directory of declarative YAML API source definitions")
+		toolsDir        = flag.String("tools-dir", enumerator.DefaultToolsDir(), "Directory of declarative YAML CLI tool definitions")
+		pluginsDir      = flag.String("plugins-dir", plugin.DefaultDir(), "Directory of out-of-process plugin executables")
+		importFlag      = flag.String("import", "", "Scan names from earlier recon output instead of enumerating (comma-separated files or globs)")
+		credsFile       = flag.String("credentials", credentials.DefaultPath(), "API credentials file (YAML, optionally encrypted)")
+		encryptCreds    = flag.Bool("encrypt-credentials", false, "Encrypt the credentials file with $"+credentials.PassphraseEnv+" and exit")
 
 		flags = toolFlags{}
 	)
@@ -75,8 +112,16 @@ func main() {
 	flag.BoolVar(&flags.useHackerTarget, "hackertarget", false, "Use HackerTarget API")
 	flag.BoolVar(&flags.useWaybackURLs, "waybackurls", false, "Use waybackurls tool")
 	flag.BoolVar(&flags.useLinkHeader, "linkheader", false, "Use Link Header enumeration")
+	flag.BoolVar(&flags.useBruteforce, "bruteforce", false, "Use native DNS brute-forcing")
+	flag.BoolVar(&flags.usePermute, "permute", false, "Use native subdomain permutations of discovered names")
+	flag.BoolVar(&flags.useAXFR, "axfr", false, "Attempt zone transfers against the target's nameservers")
+	flag.BoolVar(&flags.useTLSSAN, "tls-san", false, "Harvest names from the TLS certificates of discovered hosts")
+	flag.BoolVar(&flags.useZoneWalk, "zonewalk", false, "Walk NSEC chains and crack NSEC3 hashes of DNSSEC-signed zones")
 	flag.BoolVar(&flags.useHttpx, "httpx", false, "Use httpx for HTTP scanning")
 	flag.BoolVar(&flags.useSmap, "smap", false, "Use smap for port scanning")
+	flag.StringVar(&flags.sources, "sources", "", "Use declarative API sources (comma-separated names)")
+	flag.StringVar(&flags.customTools, "custom-tools", "", "Use declared CLI tool wrappers (comma-separated names)")
+	flag.StringVar(&flags.plugins, "plugins", "", "Use enumerator and scanner plugins (comma-separated names)")
 
 	flag.Parse()
 
@@ -89,7 +134,34 @@ func main() {
 		showUsage()
 		return
 	}
+
+	// ---- API credentials (before --check-tools so it shows remaining quota) ----
+	if *encryptCreds {
+		if err := encryptCredentials(*credsFile); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+	if err := credentials.Load(*credsFile, os.Getenv(credentials.PassphraseEnv)); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer credentials.Save()
+
+	// ---- Declarative sources and tools (before --check-tools so it lists them) ----
+	sources, err := enumerator.LoadSources(*sourcesDir)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	customTools, err := enumerator.LoadTools(*toolsDir)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
 	if *checkTools {
+		// Plugins are executed to handshake, so only list them when asked to
+		if flagSet("plugins-dir") {
+			registerPlugins(*pluginsDir)
+		}
 		utils.DisplayToolStatus()
 		return
 	}
@@ -99,6 +171,18 @@ func main() {
 		}
 		return
 	}
+	if *updateIPData {
+		dir := *ipDataDir
+		if dir == "" && *configFile != "" {
+			if fileCfg, err := config.LoadConfigFromFile(*configFile); err == nil {
+				dir = fileCfg.IPDataDir
+			}
+		}
+		if err := updateIPDataFiles(dir); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
 	if *listCheckpoints {
 		checkpoints, err := utils.ListCheckpoints(*outputDir)
 		if err != nil {
@@ -116,6 +200,25 @@ func main() {
 		return
 	}
 
+	// ---- Plugins (after the early exits, since discovery runs them) ----
+	plugins := registerPlugins(*pluginsDir)
+	flags.loadedDeclared = append(append(sources, customTools...), plugins...)
+	for _, name := range splitList(flags.sources) {
+		if !slices.Contains(sources, name) {
+			log.Fatalf("Error: unknown source %q (no definition in %s)", name, *sourcesDir)
+		}
+	}
+	for _, name := range splitList(flags.customTools) {
+		if !slices.Contains(customTools, name) {
+			log.Fatalf("Error: unknown tool %q (no definition in %s)", name, *toolsDir)
+		}
+	}
+	for _, name := range splitList(flags.plugins) {
+		if !slices.Contains(plugins, name) {
+			log.Fatalf("Error: unknown plugin %q (no enumerator or scanner plugin in %s)", name, *pluginsDir)
+		}
+	}
+
 	if !*tuiMode {
 		showBanner()
 	}
@@ -137,15 +240,23 @@ func main() {
 	hasDomainArg := len(args) > 0
 
 	cfg := &config.Config{
-		OutputDir:      *outputDir,
-		OutputFormat:   "json",
-		Threads:        *threads,
-		Retries:        *retries,
-		Timeout:        *timeout,
-		RateLimit:      *rateLimit,
-		MaxHTTPTargets: *maxHTTPTargets,
-		Tools:          make(map[string]bool),
-		Filters:        make(map[string]string),
+		OutputDir:            *outputDir,
+		OutputFormat:         "json",
+		Threads:              *threads,
+		Retries:              *retries,
+		Timeout:              *timeout,
+		RateLimit:            *rateLimit,
+		MaxHTTPTargets:       *maxHTTPTargets,
+		DNSThreads:           *dnsThreads,
+		DNSRateLimit:         *dnsRateLimit,
+		PermutationDepth:     *permDepth,
+		PermutationBudget:    *permBudget,
+		RecursionDepth:       *recursionDepth,
+		RecursionMinChildren: *recursionMin,
+		CrawlDepth:           *crawlDepth,
+		CrawlPages:           *crawlPages,
+		Tools:                make(map[string]bool),
+		Filters:              make(map[string]string),
 	}
 	if *wildcardFile != "" {
 		cfg.WildcardFile = *wildcardFile
@@ -159,6 +270,28 @@ func main() {
 	if *wordlist != "" {
 		cfg.Wordlist = *wordlist
 	}
+	if *resolvers != "" {
+		list, err := utils.LoadResolvers(*resolvers)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		cfg.Resolvers = list
+	}
+	if *wildcardFilter != "" {
+		cfg.WildcardFilter = *wildcardFilter
+	}
+	if *scopeInclude != "" {
+		cfg.ScopeInclude = strings.Split(*scopeInclude, ",")
+	}
+	if *scopeExclude != "" {
+		cfg.ScopeExclude = strings.Split(*scopeExclude, ",")
+	}
+	if *scopeAllowCIDR != "" {
+		cfg.ScopeAllowCIDRs = strings.Split(*scopeAllowCIDR, ",")
+	}
+	if *scopeDenyCIDR != "" {
+		cfg.ScopeDenyCIDRs = strings.Split(*scopeDenyCIDR, ",")
+	}
 	if *statusCodes != "" {
 		cfg.Filters["status_code"] = *statusCodes
 	}
@@ -184,25 +317,51 @@ func main() {
 		cfg.TechFilter = *techFilter
 		cfg.TechDetect = true // --tech-filter implies --tech
 	}
+	cfg.Crawl = *crawlFlag
+	cfg.DNSRecords = *dnsRecords
+	cfg.EmailSecurity = *emailSecurity
+	if *dkimSelectors != "" {
+		cfg.DKIMSelectors = splitList(*dkimSelectors)
+	}
+	cfg.OriginCheck = *originCheck
+	cfg.IPEnrich = *ipEnrich
+	cfg.IPDataDir = *ipDataDir
+	if *ipDatabases != "" {
+		cfg.IPDatabases = splitList(*ipDatabases)
+	}
+	if *clientASNs != "" {
+		cfg.ClientASNs = splitList(*clientASNs)
+	}
+	cfg.ProviderFilter = *providerFilter
+	cfg.ASNFilter = *asnFilter
+	cfg.CountryFilter = *countryFilter
+	if *importFlag != "" {
+		cfg.ImportFiles = splitList(*importFlag)
+	}
 	cfg.Takeover = *takeoverFlag
 	cfg.TakeoverOnly = *takeoverOnly
 	if cfg.TakeoverOnly {
 		cfg.Takeover = true // --takeover-only implies --takeover
 	}
 
-	if cfg.WildcardFile == "" && len(args) == 0 && *resume == "" {
+	if cfg.WildcardFile == "" && len(args) == 0 && *resume == "" && len(cfg.ImportFiles) == 0 {
 		log.Fatalf("Error: Either --wildcard file, a domain argument, or --resume is required. Use --help for usage information.")
 	}
 
-	// ---- Domain input: create temp file when a bare domain is given ----
-	cleanup, err := setupWildcardFile(cfg, args)
-	if err != nil {
-		log.Fatalf("Error: %v", err)
-	}
-	defer cleanup()
-
 	// ---- Config file merging (CLI wins) ----
 	cfg = loadAndMergeConfig(cfg, *configFile, hasDomainArg, *resume)
+	if *importFlag != "" && *wildcardFile == "" && !hasDomainArg {
+		cfg.WildcardFile = "" // imports need no targets; the config's wildcard file is not one
+	}
+
+	// ---- Target input: parse domains, URLs, CIDRs, ASNs and scope files ----
+	if *resume == "" {
+		cleanup, err := setupTargets(cfg, args)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		defer cleanup()
+	}
 
 	// ---- Tool selection (after config merge so CLI always wins) ----
 	applyToolSelection(cfg, flags, *verbose)
@@ -215,6 +374,18 @@ func main() {
 	if cfg.TechDetect {
 		cfg.Tools["httpx"] = true
 	}
+	// --crawl implies --httpx (the crawl starts from the live URLs)
+	if cfg.Crawl {
+		cfg.Tools["httpx"] = true
+	}
+	// --origin-check compares the HTTP responses of the hosts
+	if cfg.OriginCheck {
+		cfg.Tools["httpx"] = true
+	}
+	// IP filters need the tags from --ip-enrich
+	if cfg.ProviderFilter != "" || cfg.ASNFilter != "" || cfg.CountryFilter != "" {
+		cfg.IPEnrich = true
+	}
 
 	// ---- Validate and create output directory ----
 	if err := validateCLIInput(cfg); err != nil {
@@ -231,7 +402,7 @@ func main() {
 
 		// Create initial state with a temporary CLI sink for checkpoint loading
 		tmpSink := tui.EventSink(tui.NewCLIEventSink())
-		state, err := initScanState(cfg, args, *resume, *outputDir, tmpSink)
+		state, err := initScanState(cfg, *resume, *outputDir, tmpSink)
 		if err != nil {
 			log.Fatalf("%v", err)
 		}
@@ -240,7 +411,7 @@ func main() {
 		}
 	} else {
 		sink := tui.EventSink(tui.NewCLIEventSink())
-		state, err := initScanState(cfg, args, *resume, *outputDir, sink)
+		state, err := initScanState(cfg, *resume, *outputDir, sink)
 		if err != nil {
 			log.Fatalf("%v", err)
 		}
@@ -0,0 +1,42 @@
+// Package wordlists embeds the wordlists shipped with SubdomainX so that
+// brute-force enumeration and permutations work without any files on disk.
+package wordlists
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed default.txt
+var defaultList string
+
+//go:embed permutations.txt
+var permutationList string
+
+// Default returns the words from the shipped default.txt wordlist, skipping
+// blank lines and '#' comments.
+func Default() []string {
+	return Parse(defaultList)
+}
+
+// Permutations returns the words the permutation engine combines with
+// labels learned from discovered subdomains.
+func Permutations() []string {
+	return Parse(permutationList)
+}
+
+// Parse splits wordlist content into words, one per line, trimming
+// whitespace and dropping blank lines, comments and duplicates.
+func Parse(content string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		word := strings.ToLower(strings.TrimSpace(line))
+		if word == "" || strings.HasPrefix(word, "#") || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/netip"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
 	"github.com/itszeeshan/subdomainx/v2/internal/diff"
 	"github.com/itszeeshan/subdomainx/v2/internal/enumerator"
+	"github.com/itszeeshan/subdomainx/v2/internal/ipinfo"
 	"github.com/itszeeshan/subdomainx/v2/internal/notify"
 	"github.com/itszeeshan/subdomainx/v2/internal/output"
+	"github.com/itszeeshan/subdomainx/v2/internal/resolver"
 	"github.com/itszeeshan/subdomainx/v2/internal/scanner"
+	"github.com/itszeeshan/subdomainx/v2/internal/scope"
 	"github.com/itszeeshan/subdomainx/v2/internal/screenshot"
 	"github.com/itszeeshan/subdomainx/v2/internal/server"
 	"github.com/itszeeshan/subdomainx/v2/internal/tui"
@@ -27,11 +33,14 @@ type scanState struct {
 	portResults     []types.PortResult
 	waybackResults  []types.WaybackEntry
 	takeoverResults []types.TakeoverResult
+	findings        []types.Finding
+	ipdb            *ipinfo.DB     // nil unless cfg.IPEnrich
+	ipFilter        *ipinfo.Filter // nil when no IP filter is set
 }
 
 // initScanState either loads a previous checkpoint (resume mode) or creates a
 // fresh one, returning the initial scanState to continue from.
-func initScanState(cfg *config.Config, args []string, resume, outputDir string, sink tui.EventSink) (*scanState, error) {
+func initScanState(cfg *config.Config, resume, outputDir string, sink tui.EventSink) (*scanState, error) {
 	state := &scanState{}
 
 	if resume != "" {
@@ -51,6 +60,7 @@ func initScanState(cfg *config.Config, args []string, resume, outputDir string,
 		state.results = cp.Subdomains
 		state.httpResults = cp.HTTPResults
 		state.portResults = cp.PortResults
+		state.findings = cp.Findings
 
 		if cp.Domain != "" {
 			cfg.WildcardFile = cp.WildcardFile
@@ -58,15 +68,9 @@ func initScanState(cfg *config.Config, args []string, resume, outputDir string,
 		return state, nil
 	}
 
-	// Fresh scan — create a new checkpoint.
-	scanID := cfg.UniqueName
-	domain := ""
-	if len(args) > 0 {
-		domain = args[0]
-		if scanID == "scan" {
-			scanID = domain
-		}
-	}
+	// Fresh scan — create a new checkpoint named after the normalized
+	// target, never the raw argument (which may be a URL, a CIDR or "-").
+	domain := primaryTarget(cfg)
 
 	configMap := map[string]interface{}{
 		"threads":   cfg.Threads,
@@ -75,7 +79,7 @@ func initScanState(cfg *config.Config, args []string, resume, outputDir string,
 		"rateLimit": cfg.RateLimit,
 		"wordlist":  cfg.Wordlist,
 	}
-	state.checkpoint = utils.CreateCheckpoint(scanID, domain, cfg.WildcardFile, configMap)
+	state.checkpoint = utils.CreateCheckpoint(cfg.UniqueName, domain, cfg.WildcardFile, configMap)
 	return state, nil
 }
 
@@ -93,10 +97,38 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 		}
 	}
 
+	// All DNS lookups from here on go through this scan's resolver, carried
+	// in ctx so concurrent scans (API mode) never share pool state.
+	dnsResolver, err := resolver.New(cfg, func(upstream, reason string) {
+		sink.Log("warn", fmt.Sprintf("Dropping resolver %s: %s", upstream, reason))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialise resolvers: %v", err)
+	}
+	ctx := resolver.NewContext(context.Background(), dnsResolver)
+
+	sc, err := newScanScope(cfg, cp)
+	if err != nil {
+		return fmt.Errorf("invalid scope: %v", err)
+	}
+
+	if cfg.IPEnrich {
+		dir := cfg.IPDataDir
+		if dir == "" {
+			dir = ipinfo.DefaultDir()
+		}
+		if state.ipdb, err = ipinfo.Open(dir, cfg.IPDatabases, cfg.ClientASNs); err != nil {
+			return fmt.Errorf("failed to load IP data: %v", err)
+		}
+		if state.ipFilter, err = ipinfo.ParseFilter(cfg.ProviderFilter, cfg.ASNFilter, cfg.CountryFilter); err != nil {
+			return fmt.Errorf("invalid IP filter: %v", err)
+		}
+	}
+
 	// --- Enumeration ---
 	if resume == "" || len(state.results) == 0 {
 		sink.StageStarted("enumeration", "Starting subdomain enumeration...")
-		results, err := enumerator.Run(cfg, sink)
+		results, findings, err := enumerator.Run(ctx, cfg, sink)
 		if err != nil {
 			cp.MarkError(fmt.Sprintf("Enumeration failed: %v", err))
 			saveCheckpoint(cp, cfg.OutputDir, sink)
@@ -104,27 +136,102 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 			return fmt.Errorf("enumeration failed: %v", err)
 		}
 		state.results = results
+		state.findings = append(state.findings, findings...)
 		cp.AddSubdomains(results)
+		cp.AddFindings(findings)
 		cp.UpdateProgress(len(results), len(results))
 		saveCheckpoint(cp, cfg.OutputDir, sink)
 		sink.StageCompleted("enumeration", fmt.Sprintf("Enumeration completed: %d subdomains", len(results)))
 	}
+	if len(state.findings) > 0 {
+		sink.Findings(state.findings)
+	}
+
+	// --- DNS resolution (A/AAAA) ---
+	if !cp.ResolutionDone && len(state.results) > 0 {
+		sink.StageStarted("resolution", "Resolving A/AAAA records...")
+		scanner.RunResolution(ctx, cfg, state.results, sink, func() {
+			cp.SetSubdomains(state.results)
+			saveCheckpoint(cp, cfg.OutputDir, sink)
+		})
+		types.ScoreAll(state.results) // resolved names score higher
+		cp.SetSubdomains(state.results)
+		cp.MarkResolutionDone()
+		saveCheckpoint(cp, cfg.OutputDir, sink)
+
+		resolved := 0
+		for _, r := range state.results {
+			if len(r.IPs) > 0 {
+				resolved++
+			}
+		}
+		sink.SubdomainsFound(state.results, len(state.results))
+		sink.StageCompleted("resolution", fmt.Sprintf("Resolution completed: %d/%d subdomains resolved", resolved, len(state.results)))
+	}
+
+	// --- DNS enrichment (full record sets) ---
+	if cfg.DNSRecords && len(state.results) > 0 {
+		sink.StageStarted("dns", "Collecting DNS record sets...")
+		scanner.RunDNSRecords(ctx, cfg, state.results, sink, func() {
+			cp.SetSubdomains(state.results)
+			saveCheckpoint(cp, cfg.OutputDir, sink)
+		})
+		withRecords := 0
+		for _, r := range state.results {
+			if r.DNS != nil {
+				withRecords++
+			}
+		}
+		sink.SubdomainsFound(state.results, len(state.results))
+		sink.StageCompleted("dns", fmt.Sprintf("DNS enrichment completed: %d/%d subdomains with records", withRecords, len(state.results)))
+	}
+
+	// --- IP enrichment (ASN, organisation, country, provider) ---
+	if state.ipdb != nil && len(state.results) > 0 {
+		sink.StageStarted("ipinfo", "Tagging IPs with ASN, country and provider...")
+		tagged := state.ipdb.EnrichSubdomains(state.results)
+		if state.ipFilter != nil {
+			before := len(state.results)
+			state.results = state.ipFilter.FilterSubdomains(state.results)
+			sink.Log("info", fmt.Sprintf("Filtered subdomains by IP tags: %d of %d remaining", len(state.results), before))
+		}
+		cp.SetSubdomains(state.results)
+		saveCheckpoint(cp, cfg.OutputDir, sink)
+		sink.SubdomainsFound(state.results, len(state.results))
+		sink.StageCompleted("ipinfo", fmt.Sprintf("IP enrichment completed: %d subdomains tagged", tagged))
+	}
+
+	// --- Scope: drop hosts whose addresses are out of scope before probing ---
+	if before := len(state.results); before > 0 {
+		state.results = sc.FilterSubdomains(state.results, logOutOfScope(sink))
+		if len(state.results) < before {
+			cp.SetSubdomains(state.results)
+			saveCheckpoint(cp, cfg.OutputDir, sink)
+			sink.SubdomainsFound(state.results, len(state.results))
+		}
+	}
 
 	// --- HTTP scanning ---
 	if cfg.Tools["httpx"] && (resume == "" || len(state.httpResults) == 0) {
 		sink.StageStarted("http", "Running HTTP scanning with httpx...")
-		httpResults, err := scanner.RunHTTPx(cfg, state.results, sink)
+		httpResults, err := scanner.RunHTTPx(ctx, cfg, state.results, sink)
 		if err != nil {
 			sink.Log("error", fmt.Sprintf("HTTP scanning failed: %v", err))
 		} else {
 			state.httpResults = httpResults
 			cp.AddHTTPResults(httpResults)
+			if cfg.Tools["tls-san"] {
+				feedBackTLSNames(ctx, cfg, state, sc, sink)
+			}
 			saveCheckpoint(cp, cfg.OutputDir, sink)
-			sink.HTTPResults(httpResults, len(httpResults))
+			sink.HTTPResults(state.httpResults, len(state.httpResults))
 		}
 		sink.StageCompleted("http", fmt.Sprintf("HTTP scanning completed: %d results", len(state.httpResults)))
 	}
 
+	// Redirects can land on hosts outside the scope
+	state.httpResults = sc.FilterHTTP(state.httpResults, logOutOfScope(sink))
+
 	// --- Screenshots ---
 	if cfg.Screenshot && len(state.httpResults) > 0 {
 		sink.StageStarted("screenshot", "Capturing screenshots...")
@@ -137,6 +244,51 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 		sink.StageCompleted("screenshot", "Screenshots done")
 	}
 
+	// --- Crawl live pages for host names ---
+	if cfg.Crawl && len(state.httpResults) > 0 {
+		sink.StageStarted("crawl", "Crawling live HTTP results for host names...")
+		names := scanner.RunCrawl(ctx, cfg, state.httpResults, func(host string) bool {
+			ok, _ := sc.Host(host)
+			return ok
+		}, crawlFetchable(state, sc), sink)
+		before := len(state.results)
+		addFedBackNames(ctx, cfg, state, sc, sink, names, "crawl")
+		saveCheckpoint(cp, cfg.OutputDir, sink)
+		sink.StageCompleted("crawl", fmt.Sprintf("Crawl completed: %d new subdomains", len(state.results)-before))
+	}
+
+	// --- Email security posture of every apex ---
+	if cfg.EmailSecurity && !slices.ContainsFunc(state.findings, isEmailPosture) { // not again on resume
+		apexes := scanApexes(cfg, cp)
+		sink.StageStarted("email", fmt.Sprintf("Checking email security of %d domains...", len(apexes)))
+		findings, hosts := scanner.RunEmailSecurity(ctx, cfg, apexes, sink)
+		state.findings = append(state.findings, findings...)
+		cp.AddFindings(findings)
+		sink.Findings(state.findings)
+		issues := 0
+		for _, f := range findings {
+			if !isEmailPosture(f) {
+				issues++
+			}
+		}
+		before := len(state.results)
+		addFedBackNames(ctx, cfg, state, sc, sink, hosts, "spf")
+		saveCheckpoint(cp, cfg.OutputDir, sink)
+		sink.StageCompleted("email", fmt.Sprintf("Email security checks completed: %d issues, %d new subdomains", issues, len(state.results)-before))
+	}
+
+	// --- Origin exposure: CDN-fronted sites also served from a direct address ---
+	// Recomputed from the results on resume, so not checkpointed
+	if cfg.OriginCheck && len(state.httpResults) > 0 {
+		sink.StageStarted("origin", "Looking for origins exposed behind CDNs and WAFs...")
+		findings := scanner.FindExposedOrigins(state.results, state.httpResults)
+		if len(findings) > 0 {
+			state.findings = append(state.findings, findings...)
+			sink.Findings(state.findings)
+		}
+		sink.StageCompleted("origin", fmt.Sprintf("Origin exposure check completed: %d exposed origins", len(findings)))
+	}
+
 	// --- Wayback URLs for HTTP-alive subdomains ---
 	if cfg.Tools["waybackurls"] && len(state.httpResults) > 0 && len(state.waybackResults) == 0 {
 		sink.StageStarted("wayback", "Collecting Wayback URLs for HTTP-alive subdomains...")
@@ -163,10 +315,16 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 	// --- Port scanning ---
 	if cfg.Tools["smap"] && (resume == "" || len(state.portResults) == 0) {
 		sink.StageStarted("ports", "Running port scanning with smap...")
-		portResults, err := scanner.RunSmap(cfg, state.results, sink)
+		portResults, err := scanner.RunSmap(ctx, cfg, state.results, sink)
 		if err != nil {
 			sink.Log("error", fmt.Sprintf("Port scanning failed: %v", err))
 		} else {
+			if state.ipdb != nil {
+				state.ipdb.EnrichPorts(portResults)
+				if state.ipFilter != nil {
+					portResults = state.ipFilter.FilterPorts(portResults)
+				}
+			}
 			state.portResults = portResults
 			cp.AddPortResults(portResults)
 			saveCheckpoint(cp, cfg.OutputDir, sink)
@@ -178,7 +336,7 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 	// --- Subdomain takeover detection ---
 	if cfg.Takeover {
 		sink.StageStarted("takeover", "Checking for subdomain takeover vulnerabilities...")
-		takeoverResults, err := scanner.RunTakeoverCheck(cfg, state.results, state.httpResults, sink)
+		takeoverResults, err := scanner.RunTakeoverCheck(ctx, cfg, state.results, state.httpResults, sink)
 		if err != nil {
 			sink.Log("error", fmt.Sprintf("Takeover detection failed: %v", err))
 		} else {
@@ -217,7 +375,10 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 
 	// --- Output ---
 	sink.StageStarted("output", "Generating output files...")
-	if err := output.Generate(cfg, state.results, state.httpResults, state.portResults, state.waybackResults, state.takeoverResults, diffResult); err != nil {
+	state.portResults = sc.FilterPorts(state.portResults, logOutOfScope(sink))
+	state.waybackResults = sc.FilterWayback(state.waybackResults, logOutOfScope(sink))
+	state.takeoverResults = sc.FilterTakeover(state.takeoverResults, logOutOfScope(sink))
+	if err := output.Generate(cfg, state.results, state.httpResults, state.portResults, state.waybackResults, state.takeoverResults, state.findings, diffResult); err != nil {
 		return fmt.Errorf("failed to generate output: %v", err)
 	}
 	sink.StageCompleted("output", fmt.Sprintf("Results saved to %s", cfg.OutputDir))
@@ -232,6 +393,7 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 			TotalPorts:      len(state.portResults),
 			Duration:        time.Since(cp.Progress.StartTime),
 			Diff:            diffResult,
+			Findings:        state.findings,
 		}
 		if err := notify.Send(cfg.NotifyChannels, summary); err != nil {
 			sink.Log("warn", fmt.Sprintf("Notification failed: %v", err))
@@ -245,6 +407,138 @@ func executeScanPipeline(cfg *config.Config, state *scanState, resume string, si
 	return nil
 }
 
+// feedBackTLSNames adds the in-scope names from certificates seen during
+// HTTP probing that no enumerator reported, with source tls-san.
+func feedBackTLSNames(ctx context.Context, cfg *config.Config, state *scanState, sc *scope.Scope, sink tui.EventSink) {
+	var names []string
+	for _, h := range state.httpResults {
+		if h.TLS != nil {
+			names = append(names, h.TLS.Hostnames()...)
+		}
+	}
+	addFedBackNames(ctx, cfg, state, sc, sink, names, "tls-san")
+}
+
+// crawlFetchable returns the hosts the crawler may request: subdomains that
+// resolved and passed the scope's address rules, and in-scope addresses.
+// Names first seen while crawling are only fetched once they have been
+// resolved and checked the same way.
+func crawlFetchable(state *scanState, sc *scope.Scope) func(host string) bool {
+	resolved := make(map[string]bool, len(state.results))
+	for _, r := range state.results {
+		if len(r.IPs) > 0 {
+			resolved[r.Subdomain] = true
+		}
+	}
+	return func(host string) bool {
+		host = strings.ToLower(host)
+		if _, err := netip.ParseAddr(host); err == nil {
+			ok, _ := sc.Host(host)
+			return ok
+		}
+		return resolved[host]
+	}
+}
+
+// addFedBackNames adds the names found by a later stage that are in scope
+// and not known yet as subdomains reported by source, then resolves and
+// probes them like the rest.
+func addFedBackNames(ctx context.Context, cfg *config.Config, state *scanState, sc *scope.Scope, sink tui.EventSink, names []string, source string) {
+	known := make(map[string]bool, len(state.results))
+	for _, r := range state.results {
+		known[r.Subdomain] = true
+	}
+
+	now := time.Now()
+	var added []types.SubdomainResult
+	for _, name := range names {
+		if known[name] {
+			continue
+		}
+		known[name] = true
+		if ok, _ := sc.Host(name); !ok {
+			continue // certificates and pages often name other organisations' hosts
+		}
+		r := types.SubdomainResult{Subdomain: name, IPs: []string{}}
+		r.AddSource(source, now)
+		added = append(added, r)
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	scanner.RunResolution(ctx, cfg, added, sink, nil)
+	if cfg.DNSRecords {
+		scanner.RunDNSRecords(ctx, cfg, added, sink, nil)
+	}
+	if state.ipdb != nil {
+		state.ipdb.EnrichSubdomains(added)
+		if state.ipFilter != nil {
+			added = state.ipFilter.FilterSubdomains(added)
+		}
+	}
+	types.ScoreAll(added)
+	added = sc.FilterSubdomains(added, logOutOfScope(sink))
+	for _, r := range added {
+		state.results = append(state.results, r)
+		sink.SubdomainDiscovered(r.Subdomain, source, len(state.results))
+	}
+	sink.Log("info", fmt.Sprintf("Found %d new subdomains via %s", len(added), source))
+	sink.SubdomainsFound(state.results, len(state.results))
+	state.checkpoint.SetSubdomains(state.results)
+
+	if !cfg.Tools["httpx"] {
+		return
+	}
+	httpResults, err := scanner.RunHTTPx(ctx, cfg, added, sink)
+	if err != nil {
+		sink.Log("warn", fmt.Sprintf("HTTP scanning of %s names failed: %v", source, err))
+		return
+	}
+	state.httpResults = append(state.httpResults, httpResults...)
+	state.checkpoint.AddHTTPResults(httpResults)
+}
+
+// isEmailPosture reports whether f is the summary of an email security check.
+func isEmailPosture(f types.Finding) bool {
+	return f.Type == "email-posture"
+}
+
+// newScanScope compiles the scope rules for the scan's target domains.
+func newScanScope(cfg *config.Config, cp *utils.Checkpoint) (*scope.Scope, error) {
+	return scope.New(cfg, targetDomains(cfg, cp), cfg.TargetHosts)
+}
+
+// targetDomains returns the scan's target domains, falling back to the
+// checkpoint's domain when the wildcard file is gone.
+func targetDomains(cfg *config.Config, cp *utils.Checkpoint) []string {
+	domains, err := utils.ReadLines(cfg.WildcardFile)
+	if err != nil && cp.Domain != "" {
+		domains = []string{cp.Domain}
+	}
+	return domains
+}
+
+// scanApexes returns the distinct target domains, without wildcard labels.
+func scanApexes(cfg *config.Config, cp *utils.Checkpoint) []string {
+	var apexes []string
+	for _, d := range targetDomains(cfg, cp) {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(d), ".")), "*.")
+		if d != "" && !slices.Contains(apexes, d) {
+			apexes = append(apexes, d)
+		}
+	}
+	return apexes
+}
+
+// logOutOfScope returns a drop callback that logs every excluded asset with
+// the rule that excluded it.
+func logOutOfScope(sink tui.EventSink) func(asset, reason string) {
+	return func(asset, reason string) {
+		sink.Log("info", fmt.Sprintf("Out of scope: %s (%s)", asset, reason))
+	}
+}
+
 // saveCheckpoint persists cp and logs a warning on failure (non-fatal).
 func saveCheckpoint(cp *utils.Checkpoint, outputDir string, sink tui.EventSink) {
 	if err := utils.SaveCheckpoint(cp, outputDir); err != nil {
@@ -260,6 +554,7 @@ func tuiScanFunc(cfg *config.Config, checkpoint *utils.Checkpoint, resume string
 		results:     checkpoint.Subdomains,
 		httpResults: checkpoint.HTTPResults,
 		portResults: checkpoint.PortResults,
+		findings:    checkpoint.Findings,
 	}
 	return executeScanPipeline(cfg, state, resume, sink)
 }
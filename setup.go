@@ -1,12 +1,26 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/credentials"
+	"github.com/itszeeshan/subdomainx/v2/internal/enumerator"
+	"github.com/itszeeshan/subdomainx/v2/internal/input"
+	"github.com/itszeeshan/subdomainx/v2/internal/ipinfo"
+	"github.com/itszeeshan/subdomainx/v2/internal/notify"
+	"github.com/itszeeshan/subdomainx/v2/internal/plugin"
+	"github.com/itszeeshan/subdomainx/v2/internal/scanner"
+	"github.com/itszeeshan/subdomainx/v2/internal/scope"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
 	"github.com/itszeeshan/subdomainx/v2/internal/utils"
 )
 
@@ -30,8 +44,46 @@ type toolFlags struct {
 	useHackerTarget   bool
 	useWaybackURLs    bool
 	useLinkHeader     bool
+	useBruteforce     bool
+	usePermute        bool
+	useAXFR           bool
+	useZoneWalk       bool
+	useTLSSAN         bool
 	useHttpx          bool
 	useSmap           bool
+	sources           string   // --sources: declarative API sources to use
+	customTools       string   // --custom-tools: declared CLI tool wrappers to use
+	plugins           string   // --plugins: enumerator and scanner plugins to use
+	loadedDeclared    []string // every declarative source, tool and plugin that was loaded
+}
+
+// selectedDeclared returns the names given to --sources, --custom-tools
+// and --plugins.
+func (f toolFlags) selectedDeclared() []string {
+	names := append(splitList(f.sources), splitList(f.customTools)...)
+	return append(names, splitList(f.plugins)...)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
 
 func (f toolFlags) anySelected() bool {
@@ -40,21 +92,115 @@ func (f toolFlags) anySelected() bool {
 		f.useMassdns || f.useAltdns || f.useSecurityTrails || f.useVirusTotal ||
 		f.useCensys || f.useCrtSh || f.useURLScan ||
 		f.useHackerTarget || f.useWaybackURLs || f.useLinkHeader ||
-		f.useHttpx || f.useSmap
+		f.useBruteforce || f.usePermute || f.useAXFR || f.useZoneWalk || f.useTLSSAN || f.useHttpx || f.useSmap ||
+		len(f.selectedDeclared()) > 0
 }
 
-// setupWildcardFile handles the single-domain argument: validates the domain,
-// writes it to a temp file, and sets cfg.WildcardFile. The returned cleanup
+// registerPlugins discovers the plugins in dir and registers each with the
+// enumerator, scanner and notifier registries it declared a capability for,
+// and with the tool list. Discovery executes every file in dir, so it runs
+// only after the early-exit commands, or for --check-tools when
+// --plugins-dir is given. Plugins that fail their handshake or reuse a
+// built-in name are skipped with a warning. It returns the names of the
+// enumerator and scanner plugins, which are selected like tools.
+func registerPlugins(dir string) []string {
+	plugins, err := plugin.Discover(dir)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	known := make(map[string]bool)
+	for _, tool := range utils.GetRequiredTools() {
+		known[tool.Name] = true
+	}
+
+	var names []string
+	for _, p := range plugins {
+		if known[p.Name] || notify.IsValidChannel(p.Name) {
+			log.Printf("Warning: skipping plugin %s: %q is already a tool or channel", p.Path, p.Name)
+			continue
+		}
+		if p.Has(plugin.CapEnumerator) {
+			enumerator.RegisterPlugin(p)
+			if p.SourceType != "" {
+				types.RegisterSourceType(p.Name, p.SourceType)
+			}
+		}
+		scanner.RegisterPlugin(p)
+		if p.Has(plugin.CapNotifier) {
+			notify.RegisterPlugin(p)
+		}
+		if p.Has(plugin.CapEnumerator) || p.Has(plugin.CapScanner) || p.Has(plugin.CapPortScanner) {
+			names = append(names, p.Name)
+		}
+
+		description := p.Description
+		if description == "" {
+			description = "Plugin " + p.Name
+		}
+		if p.Version != "" {
+			description += " v" + p.Version
+		}
+		install := "Built-in (plugin " + p.Path + ")"
+		utils.RegisterTool(utils.Tool{
+			Name:        p.Name,
+			Command:     p.Name,
+			Description: fmt.Sprintf("%s [%s]", description, strings.Join(p.Capabilities, ", ")),
+			InstallCmd:  map[string]string{"linux": install, "darwin": install, "windows": install},
+		}, func() bool { return true })
+	}
+	return names
+}
+
+// maxTargetAddresses caps how many addresses CIDR and ASN targets may expand
+// into.
+const maxTargetAddresses = 65536
+
+// setupTargets reads the scan targets from the domain arguments, the
+// --wildcard file or stdin ("-") in any format the input package knows.
+// Apex domains are written to a temp file that becomes cfg.WildcardFile,
+// explicit hosts and addresses go to cfg.TargetHosts, and out-of-scope
+// entries from scope files extend the scope exclusions. The returned cleanup
 // function removes the temp file and should be deferred by the caller.
-func setupWildcardFile(cfg *config.Config, args []string) (cleanup func(), err error) {
+func setupTargets(cfg *config.Config, args []string) (cleanup func(), err error) {
 	cleanup = func() {} // no-op by default
-	if cfg.WildcardFile != "" || len(args) == 0 {
+
+	var targets *input.Targets
+	switch {
+	case cfg.WildcardFile == "-" || (cfg.WildcardFile == "" && len(args) == 1 && args[0] == "-"):
+		targets, err = input.ParseReader(os.Stdin)
+	case cfg.WildcardFile != "":
+		targets, err = input.ParseFile(cfg.WildcardFile)
+	case len(args) > 0:
+		targets = input.ParseArgs(args)
+	default:
 		return cleanup, nil
 	}
+	if err != nil {
+		return cleanup, err
+	}
 
-	domain := args[0]
-	if err := utils.ValidateDomain(domain); err != nil {
-		return cleanup, fmt.Errorf("invalid domain '%s': %v", domain, err)
+	for _, entry := range targets.Skipped {
+		log.Printf("Warning: skipping unrecognised target %q", entry)
+	}
+
+	if len(targets.ASNs) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+		defer cancel()
+		if err := targets.ExpandASNs(ctx, &http.Client{}); err != nil {
+			return cleanup, err
+		}
+	}
+	addrs, err := targets.Addresses(maxTargetAddresses)
+	if err != nil {
+		return cleanup, err
+	}
+
+	cfg.TargetHosts = append(append(cfg.TargetHosts, targets.Hosts...), addrs...)
+	cfg.ScopeExclude = append(cfg.ScopeExclude, targets.Excluded...)
+	cfg.ScopeDenyCIDRs = append(cfg.ScopeDenyCIDRs, targets.ExcludedNetworks...)
+	if len(targets.Apexes) == 0 && len(cfg.TargetHosts) == 0 {
+		return cleanup, fmt.Errorf("no usable targets found in input")
 	}
 
 	tmpFile, err := os.CreateTemp("", "subdomainx_domain_*.txt")
@@ -62,20 +208,93 @@ func setupWildcardFile(cfg *config.Config, args []string) (cleanup func(), err e
 		return cleanup, fmt.Errorf("failed to create temporary file: %v", err)
 	}
 
-	if _, err := tmpFile.WriteString(domain + "\n"); err != nil {
+	if _, err := tmpFile.WriteString(strings.Join(targets.Apexes, "\n") + "\n"); err != nil {
 		_ = os.Remove(tmpFile.Name())
-		return cleanup, fmt.Errorf("failed to write domain to temporary file: %v", err)
+		return cleanup, fmt.Errorf("failed to write domains to temporary file: %v", err)
 	}
 	_ = tmpFile.Close()
 
 	cfg.WildcardFile = tmpFile.Name()
-	if cfg.UniqueName == "scan" {
-		cfg.UniqueName = domain
+	if cfg.UniqueName == "scan" && len(args) > 0 {
+		if name := fileSafeName(primaryTarget(cfg)); name != "" {
+			cfg.UniqueName = name
+		}
 	}
 
 	return func() { _ = os.Remove(tmpFile.Name()) }, nil
 }
 
+// primaryTarget returns the normalized target a scan is known by: the first
+// apex in cfg.WildcardFile, or else the first explicit host or address.
+func primaryTarget(cfg *config.Config) string {
+	if apexes, err := utils.ReadLines(cfg.WildcardFile); err == nil && len(apexes) > 0 {
+		return apexes[0]
+	}
+	if len(cfg.TargetHosts) > 0 {
+		return cfg.TargetHosts[0]
+	}
+	return ""
+}
+
+// fileSafeName replaces the characters of name that do not belong in a file
+// name, such as the colons of an IPv6 address, with underscores.
+func fileSafeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// encryptCredentials encrypts the credentials file at path in place with the
+// passphrase in credentials.PassphraseEnv.
+func encryptCredentials(path string) error {
+	passphrase := os.Getenv(credentials.PassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("set %s to the passphrase to encrypt %s with", credentials.PassphraseEnv, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read credentials: %v", err)
+	}
+	if credentials.IsEncrypted(data) {
+		return fmt.Errorf("%s is already encrypted", path)
+	}
+	// Parse the file first, so a typo is not locked away behind the passphrase
+	if err := credentials.Load(path, ""); err != nil {
+		return err
+	}
+	encrypted, err := credentials.Encrypt(data, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, encrypted, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials: %v", err)
+	}
+	fmt.Printf("🔒 Encrypted %s; set %s to use it\n", path, credentials.PassphraseEnv)
+	return nil
+}
+
+// updateIPDataFiles downloads the ip2asn table and the cloud providers' range
+// files into dir, or the default data directory when dir is empty.
+func updateIPDataFiles(dir string) error {
+	if dir == "" {
+		dir = ipinfo.DefaultDir()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	err := ipinfo.Update(ctx, &http.Client{}, dir, func(format string, args ...any) {
+		fmt.Printf("✅ "+format+"\n", args...)
+	})
+	if err != nil {
+		return fmt.Errorf("IP data update incomplete: %v", err)
+	}
+	fmt.Printf("🌐 IP data in %s is up to date\n", dir)
+	return nil
+}
+
 // loadAndMergeConfig loads a config file (or the default) and merges it with
 // the CLI-built cfg, returning the merged result. CLI values win.
 func loadAndMergeConfig(cfg *config.Config, configFile string, hasDomainArg bool, resume string) *config.Config {
@@ -94,9 +313,17 @@ func loadAndMergeConfig(cfg *config.Config, configFile string, hasDomainArg bool
 	return cfg
 }
 
+// activeTools query the target's own infrastructure once per candidate
+// name or host. They run only when selected with their flag or enabled in
+// the config file, never as part of the enable-everything default. The same
+// goes for declared tools and plugins whose findings are brute-force or
+// permutation records.
+var activeTools = []string{"bruteforce", "permute", "axfr", "zonewalk", "tls-san"}
+
 // applyToolSelection writes the enabled/disabled tool map into cfg based on
 // the CLI flags. When specific tools are chosen those are applied exclusively;
-// otherwise every tool is enabled. Must be called after config file merging.
+// otherwise every passive tool is enabled and activeTools keep the config
+// file's setting. Must be called after config file merging.
 func applyToolSelection(cfg *config.Config, flags toolFlags, verbose bool) {
 	if flags.anySelected() {
 		cfg.Tools = map[string]bool{
@@ -118,9 +345,17 @@ func applyToolSelection(cfg *config.Config, flags toolFlags, verbose bool) {
 			"hackertarget":   flags.useHackerTarget,
 			"waybackurls":    flags.useWaybackURLs,
 			"linkheader":     flags.useLinkHeader,
+			"bruteforce":     flags.useBruteforce,
+			"permute":        flags.usePermute,
+			"axfr":           flags.useAXFR,
+			"zonewalk":       flags.useZoneWalk,
+			"tls-san":        flags.useTLSSAN,
 			"httpx":          flags.useHttpx,
 			"smap":           flags.useSmap,
 		}
+		for _, name := range flags.selectedDeclared() {
+			cfg.Tools[name] = true
+		}
 		if verbose {
 			var selected []string
 			for tool, enabled := range cfg.Tools {
@@ -133,7 +368,7 @@ func applyToolSelection(cfg *config.Config, flags toolFlags, verbose bool) {
 		return
 	}
 
-	// No specific tools chosen — enable everything.
+	// No specific tools chosen — enable everything but activeTools.
 	if cfg.Tools == nil {
 		cfg.Tools = make(map[string]bool)
 	}
@@ -141,28 +376,63 @@ func applyToolSelection(cfg *config.Config, flags toolFlags, verbose bool) {
 		"subfinder", "amass", "findomain", "assetfinder", "sublist3r",
 		"knockpy", "dnsrecon", "fierce", "massdns", "altdns",
 		"securitytrails", "virustotal", "censys", "crtsh", "urlscan",
-		"hackertarget", "waybackurls", "linkheader",
-		"httpx", "smap",
+		"hackertarget", "waybackurls", "linkheader", "httpx", "smap",
 	} {
 		cfg.Tools[tool] = true
 	}
+	for _, name := range flags.loadedDeclared {
+		switch types.SourceType(name) {
+		case types.SourceBruteForce, types.SourcePermutation:
+			continue
+		}
+		cfg.Tools[name] = true
+	}
 }
 
 // mergeConfig merges two configs with cfg2 taking precedence over cfg1.
 func mergeConfig(cfg1, cfg2 *config.Config) *config.Config {
 	result := &config.Config{
-		WildcardFile:   cfg1.WildcardFile,
-		UniqueName:     cfg1.UniqueName,
-		OutputDir:      cfg1.OutputDir,
-		OutputFormat:   cfg1.OutputFormat,
-		Threads:        cfg1.Threads,
-		Retries:        cfg1.Retries,
-		Timeout:        cfg1.Timeout,
-		RateLimit:      cfg1.RateLimit,
-		Wordlist:       cfg1.Wordlist,
-		MaxHTTPTargets: cfg1.MaxHTTPTargets,
-		Tools:          make(map[string]bool),
-		Filters:        make(map[string]string),
+		WildcardFile:         cfg1.WildcardFile,
+		TargetHosts:          cfg1.TargetHosts,
+		UniqueName:           cfg1.UniqueName,
+		OutputDir:            cfg1.OutputDir,
+		OutputFormat:         cfg1.OutputFormat,
+		Threads:              cfg1.Threads,
+		Retries:              cfg1.Retries,
+		Timeout:              cfg1.Timeout,
+		RateLimit:            cfg1.RateLimit,
+		Wordlist:             cfg1.Wordlist,
+		MaxHTTPTargets:       cfg1.MaxHTTPTargets,
+		Resolvers:            cfg1.Resolvers,
+		DNSThreads:           cfg1.DNSThreads,
+		DNSRateLimit:         cfg1.DNSRateLimit,
+		DNSRecords:           cfg1.DNSRecords,
+		EmailSecurity:        cfg1.EmailSecurity,
+		DKIMSelectors:        cfg1.DKIMSelectors,
+		IPEnrich:             cfg1.IPEnrich,
+		IPDataDir:            cfg1.IPDataDir,
+		IPDatabases:          cfg1.IPDatabases,
+		ClientASNs:           cfg1.ClientASNs,
+		ProviderFilter:       cfg1.ProviderFilter,
+		ASNFilter:            cfg1.ASNFilter,
+		CountryFilter:        cfg1.CountryFilter,
+		OriginCheck:          cfg1.OriginCheck,
+		WildcardFilter:       cfg1.WildcardFilter,
+		ScopeApexes:          cfg1.ScopeApexes,
+		ScopeInclude:         cfg1.ScopeInclude,
+		ScopeExclude:         cfg1.ScopeExclude,
+		ScopeAllowCIDRs:      cfg1.ScopeAllowCIDRs,
+		ScopeDenyCIDRs:       cfg1.ScopeDenyCIDRs,
+		PermutationDepth:     cfg1.PermutationDepth,
+		PermutationBudget:    cfg1.PermutationBudget,
+		RecursionDepth:       cfg1.RecursionDepth,
+		RecursionMinChildren: cfg1.RecursionMinChildren,
+		CrawlDepth:           cfg1.CrawlDepth,
+		CrawlPages:           cfg1.CrawlPages,
+		DomainTimeout:        cfg1.DomainTimeout,
+		ImportFiles:          cfg1.ImportFiles,
+		Tools:                make(map[string]bool),
+		Filters:              make(map[string]string),
 	}
 
 	for k, v := range cfg1.Tools {
@@ -171,10 +441,25 @@ func mergeConfig(cfg1, cfg2 *config.Config) *config.Config {
 	for k, v := range cfg1.Filters {
 		result.Filters[k] = v
 	}
+	for k, v := range cfg1.SourcePolicies {
+		if result.SourcePolicies == nil {
+			result.SourcePolicies = make(map[string]config.SourcePolicy)
+		}
+		result.SourcePolicies[k] = v
+	}
+	for k, v := range cfg1.ToolTimeouts {
+		if result.ToolTimeouts == nil {
+			result.ToolTimeouts = make(map[string]int)
+		}
+		result.ToolTimeouts[k] = v
+	}
 
 	if cfg2.WildcardFile != "" {
 		result.WildcardFile = cfg2.WildcardFile
 	}
+	if len(cfg2.TargetHosts) > 0 {
+		result.TargetHosts = cfg2.TargetHosts
+	}
 	if cfg2.UniqueName != "" {
 		result.UniqueName = cfg2.UniqueName
 	}
@@ -202,6 +487,51 @@ func mergeConfig(cfg1, cfg2 *config.Config) *config.Config {
 	if cfg2.MaxHTTPTargets > 0 {
 		result.MaxHTTPTargets = cfg2.MaxHTTPTargets
 	}
+	if len(cfg2.Resolvers) > 0 {
+		result.Resolvers = cfg2.Resolvers
+	}
+	if cfg2.DNSThreads > 0 {
+		result.DNSThreads = cfg2.DNSThreads
+	}
+	if cfg2.DNSRateLimit > 0 {
+		result.DNSRateLimit = cfg2.DNSRateLimit
+	}
+	if cfg2.PermutationDepth > 0 {
+		result.PermutationDepth = cfg2.PermutationDepth
+	}
+	if cfg2.PermutationBudget > 0 {
+		result.PermutationBudget = cfg2.PermutationBudget
+	}
+	if cfg2.RecursionDepth > 0 {
+		result.RecursionDepth = cfg2.RecursionDepth
+	}
+	if cfg2.RecursionMinChildren > 0 {
+		result.RecursionMinChildren = cfg2.RecursionMinChildren
+	}
+	if cfg2.CrawlDepth > 0 {
+		result.CrawlDepth = cfg2.CrawlDepth
+	}
+	if cfg2.CrawlPages > 0 {
+		result.CrawlPages = cfg2.CrawlPages
+	}
+	if cfg2.WildcardFilter != "" {
+		result.WildcardFilter = cfg2.WildcardFilter
+	}
+	if len(cfg2.ScopeApexes) > 0 {
+		result.ScopeApexes = cfg2.ScopeApexes
+	}
+	if len(cfg2.ScopeInclude) > 0 {
+		result.ScopeInclude = cfg2.ScopeInclude
+	}
+	if len(cfg2.ScopeExclude) > 0 {
+		result.ScopeExclude = cfg2.ScopeExclude
+	}
+	if len(cfg2.ScopeAllowCIDRs) > 0 {
+		result.ScopeAllowCIDRs = cfg2.ScopeAllowCIDRs
+	}
+	if len(cfg2.ScopeDenyCIDRs) > 0 {
+		result.ScopeDenyCIDRs = cfg2.ScopeDenyCIDRs
+	}
 
 	if cfg2.Screenshot {
 		result.Screenshot = true
@@ -236,6 +566,42 @@ func mergeConfig(cfg1, cfg2 *config.Config) *config.Config {
 	if cfg2.TakeoverOnly {
 		result.TakeoverOnly = true
 	}
+	if cfg2.Crawl {
+		result.Crawl = true
+	}
+	if cfg2.DNSRecords {
+		result.DNSRecords = true
+	}
+	if cfg2.EmailSecurity {
+		result.EmailSecurity = true
+	}
+	if len(cfg2.DKIMSelectors) > 0 {
+		result.DKIMSelectors = cfg2.DKIMSelectors
+	}
+	if cfg2.IPEnrich {
+		result.IPEnrich = true
+	}
+	if cfg2.IPDataDir != "" {
+		result.IPDataDir = cfg2.IPDataDir
+	}
+	if len(cfg2.IPDatabases) > 0 {
+		result.IPDatabases = cfg2.IPDatabases
+	}
+	if len(cfg2.ClientASNs) > 0 {
+		result.ClientASNs = cfg2.ClientASNs
+	}
+	if cfg2.ProviderFilter != "" {
+		result.ProviderFilter = cfg2.ProviderFilter
+	}
+	if cfg2.ASNFilter != "" {
+		result.ASNFilter = cfg2.ASNFilter
+	}
+	if cfg2.CountryFilter != "" {
+		result.CountryFilter = cfg2.CountryFilter
+	}
+	if cfg2.OriginCheck {
+		result.OriginCheck = true
+	}
 
 	for k, v := range cfg2.Tools {
 		result.Tools[k] = v
@@ -243,6 +609,24 @@ func mergeConfig(cfg1, cfg2 *config.Config) *config.Config {
 	for k, v := range cfg2.Filters {
 		result.Filters[k] = v
 	}
+	for k, v := range cfg2.SourcePolicies {
+		if result.SourcePolicies == nil {
+			result.SourcePolicies = make(map[string]config.SourcePolicy)
+		}
+		result.SourcePolicies[k] = v
+	}
+	for k, v := range cfg2.ToolTimeouts {
+		if result.ToolTimeouts == nil {
+			result.ToolTimeouts = make(map[string]int)
+		}
+		result.ToolTimeouts[k] = v
+	}
+	if cfg2.DomainTimeout > 0 {
+		result.DomainTimeout = cfg2.DomainTimeout
+	}
+	if len(cfg2.ImportFiles) > 0 {
+		result.ImportFiles = cfg2.ImportFiles
+	}
 
 	return result
 }
@@ -256,6 +640,12 @@ func validateCLIInput(cfg *config.Config) error {
 		return fmt.Errorf("wildcard file not found: %s", cfg.WildcardFile)
 	}
 
+	for _, pattern := range cfg.ImportFiles {
+		if files, _ := filepath.Glob(pattern); len(files) == 0 && !utils.FileExists(pattern) {
+			return fmt.Errorf("import file not found: %s", pattern)
+		}
+	}
+
 	if err := utils.EnsureDirectory(cfg.OutputDir); err != nil {
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
@@ -280,16 +670,66 @@ func validateCLIInput(cfg *config.Config) error {
 	if cfg.RateLimit <= 0 {
 		return fmt.Errorf("rate limit must be greater than 0")
 	}
+	for i, r := range cfg.Resolvers {
+		normalized, err := utils.NormalizeResolver(r)
+		if err != nil {
+			return err
+		}
+		cfg.Resolvers[i] = normalized
+	}
+	if cfg.DNSThreads <= 0 {
+		return fmt.Errorf("dns threads must be greater than 0")
+	}
+	if cfg.DNSRateLimit <= 0 {
+		return fmt.Errorf("dns rate limit must be greater than 0")
+	}
+	if cfg.PermutationDepth <= 0 {
+		return fmt.Errorf("permutation depth must be greater than 0")
+	}
+	if cfg.PermutationBudget <= 0 {
+		return fmt.Errorf("permutation budget must be greater than 0")
+	}
+	if cfg.RecursionDepth < 0 {
+		return fmt.Errorf("recursion depth cannot be negative")
+	}
+	if cfg.RecursionMinChildren <= 0 {
+		return fmt.Errorf("recursion min children must be greater than 0")
+	}
+	if cfg.CrawlDepth < 0 {
+		return fmt.Errorf("crawl depth cannot be negative")
+	}
+	if cfg.CrawlPages <= 0 {
+		return fmt.Errorf("crawl pages must be greater than 0")
+	}
+	validWildcardModes := map[string]bool{"": true, "drop": true, "flag": true, "off": true}
+	if !validWildcardModes[cfg.WildcardFilter] {
+		return fmt.Errorf("invalid wildcard filter: %s. Supported: drop, flag, off", cfg.WildcardFilter)
+	}
+	if _, err := scope.New(cfg, nil, nil); err != nil {
+		return err
+	}
+	if _, err := ipinfo.ParseFilter(cfg.ProviderFilter, cfg.ASNFilter, cfg.CountryFilter); err != nil {
+		return fmt.Errorf("invalid IP filter: %v", err)
+	}
+	for _, asn := range cfg.ClientASNs {
+		if _, err := ipinfo.ParseASN(asn); err != nil {
+			return fmt.Errorf("invalid client ASN: %v", err)
+		}
+	}
+	for _, path := range cfg.IPDatabases {
+		if !utils.FileExists(path) {
+			return fmt.Errorf("IP database not found: %s", path)
+		}
+	}
 	if cfg.Wordlist != "" && !utils.FileExists(cfg.Wordlist) {
 		return fmt.Errorf("wordlist file not found: %s", cfg.Wordlist)
 	}
 	if cfg.BaselineFile != "" && !utils.FileExists(cfg.BaselineFile) {
 		return fmt.Errorf("baseline file not found: %s", cfg.BaselineFile)
 	}
-	validChannels := map[string]bool{"slack": true, "discord": true, "telegram": true, "email": true}
 	for _, ch := range cfg.NotifyChannels {
-		if !validChannels[ch] {
-			return fmt.Errorf("invalid notification channel: %s. Supported: slack, discord, telegram, email", ch)
+		if !notify.IsValidChannel(ch) {
+			return fmt.Errorf("invalid notification channel: %s. Supported: %s", ch, strings.Join(notify.ValidChannels(), ", "))
 		}
 	}
 
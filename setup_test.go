@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/itszeeshan/subdomainx/v2/internal/config"
+	"github.com/itszeeshan/subdomainx/v2/internal/types"
+)
+
+// enabledTools returns the sorted names of the tools cfg enables.
+func enabledTools(cfg *config.Config) []string {
+	var names []string
+	for name, on := range cfg.Tools {
+		if on {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestApplyToolSelectionDefault(t *testing.T) {
+	types.RegisterSourceType("mybrute", types.SourceBruteForce)
+	cfg := &config.Config{}
+	applyToolSelection(cfg, toolFlags{loadedDeclared: []string{"mysource", "mybrute"}}, false)
+
+	want := []string{
+		"altdns", "amass", "assetfinder", "censys", "crtsh", "dnsrecon", "fierce",
+		"findomain", "hackertarget", "httpx", "knockpy", "linkheader", "massdns",
+		"mysource", "securitytrails", "smap", "subfinder", "sublist3r",
+		"urlscan", "virustotal", "waybackurls",
+	}
+	if got := enabledTools(cfg); !slices.Equal(got, want) {
+		t.Errorf("default tools = %v, want %v", got, want)
+	}
+	for _, tool := range append(activeTools, "mybrute") {
+		if cfg.Tools[tool] {
+			t.Errorf("Active tool %s enabled by default", tool)
+		}
+	}
+}
+
+func TestApplyToolSelectionConfigEnablesActive(t *testing.T) {
+	cfg := &config.Config{Tools: map[string]bool{"bruteforce": true, "axfr": false}}
+	applyToolSelection(cfg, toolFlags{}, false)
+
+	if !cfg.Tools["bruteforce"] {
+		t.Error("Expected bruteforce enabled by the config file to stay on")
+	}
+	if cfg.Tools["axfr"] {
+		t.Error("Expected axfr disabled by the config file to stay off")
+	}
+}
+
+func TestApplyToolSelectionFlags(t *testing.T) {
+	cfg := &config.Config{Tools: map[string]bool{"amass": true}}
+	applyToolSelection(cfg, toolFlags{useBruteforce: true, useCrtSh: true}, false)
+
+	if got, want := enabledTools(cfg), []string{"bruteforce", "crtsh"}; !slices.Equal(got, want) {
+		t.Errorf("selected tools = %v, want %v", got, want)
+	}
+}
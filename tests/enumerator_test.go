@@ -85,3 +85,68 @@ func TestConfigValidation(t *testing.T) {
 		t.Error("Rate limit should be greater than 0")
 	}
 }
+
+func TestGeneratePermutations(t *testing.T) {
+	seeds := []string{"api01.example.com", "dev-app.example.com"}
+	candidates := enumerator.GeneratePermutations(seeds, "example.com", []string{"staging"}, 1000)
+
+	got := make(map[string]bool)
+	for _, c := range candidates {
+		got[c] = true
+	}
+
+	want := []string{
+		"api00.example.com",         // number decrement, padding kept
+		"api02.example.com",         // number increment
+		"dev.app.example.com",       // dash to dot
+		"staging-api01.example.com", // prefix
+		"api01-staging.example.com", // suffix
+		"staging.api01.example.com", // insert before
+		"api01.staging.example.com", // insert after
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("Expected permutation %s", w)
+		}
+	}
+	for _, s := range seeds {
+		if got[s] {
+			t.Errorf("Seed %s should not be returned as a candidate", s)
+		}
+	}
+}
+
+func TestGeneratePermutationsLimit(t *testing.T) {
+	seeds := []string{"api.example.com"}
+	words := []string{"dev", "test", "prod", "qa"}
+
+	candidates := enumerator.GeneratePermutations(seeds, "example.com", words, 5)
+	if len(candidates) != 5 {
+		t.Errorf("Expected 5 candidates, got %d", len(candidates))
+	}
+}
+
+func TestLearnWords(t *testing.T) {
+	words := enumerator.LearnWords([]string{
+		"api-v2.example.com",
+		"api.eu.example.com",
+		"web01.example.com",
+		"other.org",
+	}, "example.com")
+
+	if len(words) == 0 || words[0] != "api" {
+		t.Fatalf("Expected most frequent word 'api' first, got %v", words)
+	}
+	learned := make(map[string]bool)
+	for _, w := range words {
+		learned[w] = true
+	}
+	for _, w := range []string{"v2", "eu", "web01", "web"} {
+		if !learned[w] {
+			t.Errorf("Expected learned word %s in %v", w, words)
+		}
+	}
+	if learned["other"] {
+		t.Error("Words from other domains should be ignored")
+	}
+}